@@ -1,16 +1,24 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"mainService/config"
 	pb "mainService/genproto/doccs"
+	pbu "mainService/genproto/user"
+	"mainService/pkg/blob"
+	"mainService/pkg/events"
 	"mainService/pkg/logger"
+	"mainService/pkg/notify"
+	"mainService/pkg/ratelimit"
 	"mainService/service"
 	"mainService/storage/mongodb"
 	"net"
 
+	"github.com/redis/go-redis/v9"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 )
 
 func main() {
@@ -26,13 +34,112 @@ func main() {
 		log.Fatal(err)
 	}
 
+	userConn, err := grpc.NewClient(config.Load().USER_SERVICE, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer userConn.Close()
+
 	logs := logger.NewLogger()
 	mongodbRepoDocument := mongodb.NewDocumentRepository(mongoDB)
 	mongodbRepoVersion := mongodb.NewDocumentVersionRepository(mongoDB)
+	mongodbRepoStarred := mongodb.NewStarredRepository(mongoDB)
+	mongodbRepoRecent := mongodb.NewRecentRepository(mongoDB)
+	mongodbRepoComment := mongodb.NewCommentRepository(mongoDB)
+	mongodbRepoSuggestion := mongodb.NewSuggestionRepository(mongoDB)
+	mongodbRepoTemplate := mongodb.NewTemplateRepository(mongoDB)
+	mongodbRepoShareLink := mongodb.NewShareLinkRepository(mongoDB, config.Load().ShareLinkBaseURL)
+	mongodbRepoCollaborator := mongodb.NewCollaboratorRepository(mongoDB)
+	mongodbRepoNotification := mongodb.NewNotificationRepository(mongoDB)
+	mongodbRepoAccessRequest := mongodb.NewAccessRequestRepository(mongoDB)
+	mongodbRepoAudit := mongodb.NewAuditRepository(mongoDB)
+	mongodbRepoEvent := mongodb.NewEventRepository(mongoDB)
+	mongodbRepoWebhook := mongodb.NewWebhookRepository(mongoDB)
+	mongodbRepoOutbox := mongodb.NewOutboxRepository(mongoDB)
+	mongodbRepoAttachment := mongodb.NewAttachmentRepository(mongoDB)
+	mongodbRepoWorkspace := mongodb.NewWorkspaceRepository(mongoDB)
+	mongodbRepoIdempotency := mongodb.NewIdempotencyRepository(mongoDB, config.Load().IdempotencyTTL)
+	if err := mongodbRepoIdempotency.EnsureIdempotencyIndex(context.Background()); err != nil {
+		log.Fatal(err)
+	}
+
+	cfg := config.Load()
+	mongodbRepoQuota := mongodb.NewQuotaRepository(mongoDB,
+		mongodb.Quota{MaxDocuments: cfg.UserMaxDocuments, MaxBytes: cfg.UserMaxBytes},
+		mongodb.Quota{MaxDocuments: cfg.WorkspaceMaxDocuments, MaxBytes: cfg.WorkspaceMaxBytes})
+
+	var sender notify.Sender
+	switch cfg.NotifySender {
+	case "smtp":
+		sender = notify.NewSMTPSender(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPFrom)
+	default:
+		sender, err = notify.NewFileSender(cfg.NotifyFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	var publisher events.Publisher
+	switch cfg.EventPublisher {
+	case "kafka":
+		publisher = events.NewKafkaPublisher(cfg.KafkaBrokers, cfg.KafkaTopic)
+	case "nats":
+		publisher, err = events.NewNATSPublisher(cfg.NATSURL, cfg.NATSSubject)
+		if err != nil {
+			log.Fatal(err)
+		}
+	default:
+		publisher = events.NewMemoryPublisher()
+	}
+	defer publisher.Close()
+
+	var blobs blob.Store
+	switch cfg.BlobStore {
+	case "gridfs":
+		blobs, err = blob.NewGridFSStore(mongoDB, "attachments")
+	case "s3":
+		blobs = blob.NewS3Store(blob.S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+		}, nil)
+	default:
+		blobs, err = blob.NewLocalStore(cfg.BlobDir)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	methodLimits, err := ratelimit.ParseLimits(cfg.RateLimitMethods)
+	if err != nil {
+		log.Fatal(err)
+	}
+	limiter := service.RateLimiter{
+		Store:   ratelimit.NewMemoryStore(),
+		Default: ratelimit.Limit{Rate: cfg.RateLimitRate, Burst: cfg.RateLimitBurst},
+		Methods: methodLimits,
+		Address: ratelimit.Limit{Rate: cfg.RateLimitAddressRate, Burst: cfg.RateLimitAddressBurst},
+	}
+	if cfg.RateLimitStore == "redis" {
+		client := redis.NewClient(&redis.Options{Addr: cfg.RedisAddr, Password: cfg.RedisPassword, DB: cfg.RedisDB})
+		defer client.Close()
+		limiter.Store = ratelimit.NewRedisStore(client, "ratelimit:")
+	}
+
+	mongodbService := service.NewService(logs, mongodbRepoDocument, mongodbRepoVersion, mongodbRepoStarred, mongodbRepoRecent, mongodbRepoComment, mongodbRepoSuggestion, mongodbRepoTemplate, mongodbRepoShareLink, mongodbRepoCollaborator, mongodbRepoNotification, mongodbRepoAccessRequest, sender, pbu.NewUserServiceClient(userConn), mongodbRepoAudit, mongodbRepoEvent, mongodbRepoWebhook, mongodbRepoOutbox, publisher, mongodbRepoAttachment, blobs, mongodbRepoWorkspace, mongodbRepoQuota, cfg.AutosaveWindow, mongodbRepoIdempotency)
 
-	mongodbService := service.NewService(logs, mongodbRepoDocument, mongodbRepoVersion)
+	go mongodbService.RunGrantSweeper(context.Background(), cfg.GrantSweepInterval)
+	go mongodbService.RunNotificationWorker(context.Background(), cfg.NotifyInterval)
+	go mongodbService.RunWebhookWorker(context.Background(), cfg.WebhookInterval, cfg.WebhookTimeout)
+	go mongodbService.RunEventRelay(context.Background(), cfg.EventRelayInterval)
+	go mongodbService.RunAttachmentSweeper(context.Background(), cfg.AttachmentSweepInterval, cfg.AttachmentGracePeriod)
 
-	server := grpc.NewServer()
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(mongodbService.UnaryInterceptors(limiter)...),
+		grpc.ChainStreamInterceptor(mongodbService.StreamInterceptors(limiter)...),
+	)
 	pb.RegisterDocsServiceServer(server, mongodbService)
 
 	fmt.Printf("Server is listening on port %s\n", config.Load().GOOGLE_DOCS)
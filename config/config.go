@@ -3,6 +3,8 @@ package config
 import (
 	"log"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/spf13/cast"
@@ -12,6 +14,59 @@ type Config struct {
 	GOOGLE_DOCS    string
 	MongoURI          string
 	MongoDBName       string
+	USER_SERVICE      string
+	ShareLinkBaseURL  string
+	GrantSweepInterval time.Duration
+
+	NotifySender       string
+	NotifyFile         string
+	NotifyInterval     time.Duration
+	SMTPHost           string
+	SMTPPort           int
+	SMTPUsername       string
+	SMTPPassword       string
+	SMTPFrom           string
+
+	WebhookInterval    time.Duration
+	WebhookTimeout     time.Duration
+
+	EventPublisher     string
+	EventRelayInterval time.Duration
+	KafkaBrokers       []string
+	KafkaTopic         string
+	NATSURL            string
+	NATSSubject        string
+
+	BlobStore               string
+	BlobDir                 string
+	S3Endpoint              string
+	S3Region                string
+	S3Bucket                string
+	S3AccessKey             string
+	S3SecretKey             string
+	AttachmentSweepInterval time.Duration
+	AttachmentGracePeriod   time.Duration
+
+	UserMaxDocuments      int64
+	UserMaxBytes          int64
+	WorkspaceMaxDocuments int64
+	WorkspaceMaxBytes     int64
+
+	RateLimitStore   string
+	RateLimitRate    float64
+	RateLimitBurst   int
+	RateLimitMethods string
+	RedisAddr        string
+	RedisPassword    string
+	RedisDB          int
+
+	// RateLimitAddressRate and RateLimitAddressBurst limit all calls from one
+	// client address, which may be a gateway for many users.
+	RateLimitAddressRate  float64
+	RateLimitAddressBurst int
+
+	AutosaveWindow time.Duration
+	IdempotencyTTL time.Duration
 }
 
 func Load() Config {
@@ -23,6 +78,56 @@ func Load() Config {
 	config.GOOGLE_DOCS = cast.ToString(Coalesce("GOOGLE_DOCS", ":50052"))
 	config.MongoURI = cast.ToString(Coalesce("MONGO_URI", "mongodb://localhost:27017"))
 	config.MongoDBName = cast.ToString(Coalesce("MONGODB_NAME", "google_docs"))
+	config.USER_SERVICE = cast.ToString(Coalesce("USER_SERVICE", ":50051"))
+	config.ShareLinkBaseURL = cast.ToString(Coalesce("SHARE_LINK_BASE_URL", "http://localhost:3000/share/"))
+	config.GrantSweepInterval = cast.ToDuration(Coalesce("GRANT_SWEEP_INTERVAL", "1m"))
+
+	config.NotifySender = cast.ToString(Coalesce("NOTIFY_SENDER", "file"))
+	config.NotifyFile = cast.ToString(Coalesce("NOTIFY_FILE", "notifications.log"))
+	config.NotifyInterval = cast.ToDuration(Coalesce("NOTIFY_INTERVAL", "10s"))
+	config.SMTPHost = cast.ToString(Coalesce("SMTP_HOST", "localhost"))
+	config.SMTPPort = cast.ToInt(Coalesce("SMTP_PORT", 587))
+	config.SMTPUsername = cast.ToString(Coalesce("SMTP_USERNAME", ""))
+	config.SMTPPassword = cast.ToString(Coalesce("SMTP_PASSWORD", ""))
+	config.SMTPFrom = cast.ToString(Coalesce("SMTP_FROM", "no-reply@localhost"))
+
+	config.WebhookInterval = cast.ToDuration(Coalesce("WEBHOOK_INTERVAL", "5s"))
+	config.WebhookTimeout = cast.ToDuration(Coalesce("WEBHOOK_TIMEOUT", "10s"))
+
+	config.EventPublisher = cast.ToString(Coalesce("EVENT_PUBLISHER", "memory"))
+	config.EventRelayInterval = cast.ToDuration(Coalesce("EVENT_RELAY_INTERVAL", "2s"))
+	config.KafkaBrokers = strings.Split(cast.ToString(Coalesce("KAFKA_BROKERS", "localhost:9092")), ",")
+	config.KafkaTopic = cast.ToString(Coalesce("KAFKA_TOPIC", "docs.events"))
+	config.NATSURL = cast.ToString(Coalesce("NATS_URL", "nats://localhost:4222"))
+	config.NATSSubject = cast.ToString(Coalesce("NATS_SUBJECT", "docs.events"))
+
+	config.BlobStore = cast.ToString(Coalesce("BLOB_STORE", "local"))
+	config.BlobDir = cast.ToString(Coalesce("BLOB_DIR", "blobs"))
+	config.S3Endpoint = cast.ToString(Coalesce("S3_ENDPOINT", "http://localhost:9000"))
+	config.S3Region = cast.ToString(Coalesce("S3_REGION", "us-east-1"))
+	config.S3Bucket = cast.ToString(Coalesce("S3_BUCKET", "docs-attachments"))
+	config.S3AccessKey = cast.ToString(Coalesce("S3_ACCESS_KEY", ""))
+	config.S3SecretKey = cast.ToString(Coalesce("S3_SECRET_KEY", ""))
+	config.AttachmentSweepInterval = cast.ToDuration(Coalesce("ATTACHMENT_SWEEP_INTERVAL", "10m"))
+	config.AttachmentGracePeriod = cast.ToDuration(Coalesce("ATTACHMENT_GRACE_PERIOD", "24h"))
+
+	config.UserMaxDocuments = cast.ToInt64(Coalesce("USER_MAX_DOCUMENTS", 1000))
+	config.UserMaxBytes = cast.ToInt64(Coalesce("USER_MAX_BYTES", 1<<30))
+	config.WorkspaceMaxDocuments = cast.ToInt64(Coalesce("WORKSPACE_MAX_DOCUMENTS", 10000))
+	config.WorkspaceMaxBytes = cast.ToInt64(Coalesce("WORKSPACE_MAX_BYTES", 10<<30))
+
+	config.RateLimitStore = cast.ToString(Coalesce("RATE_LIMIT_STORE", "memory"))
+	config.RateLimitRate = cast.ToFloat64(Coalesce("RATE_LIMIT_RATE", 20))
+	config.RateLimitBurst = cast.ToInt(Coalesce("RATE_LIMIT_BURST", 40))
+	config.RateLimitMethods = cast.ToString(Coalesce("RATE_LIMIT_METHODS", "UpdateDocument=2:10,CreateDocument=1:10,ImportDocument=0.2:5"))
+	config.RateLimitAddressRate = cast.ToFloat64(Coalesce("RATE_LIMIT_ADDRESS_RATE", 500))
+	config.RateLimitAddressBurst = cast.ToInt(Coalesce("RATE_LIMIT_ADDRESS_BURST", 1000))
+	config.RedisAddr = cast.ToString(Coalesce("REDIS_ADDR", "localhost:6379"))
+	config.RedisPassword = cast.ToString(Coalesce("REDIS_PASSWORD", ""))
+	config.RedisDB = cast.ToInt(Coalesce("REDIS_DB", 0))
+
+	config.AutosaveWindow = cast.ToDuration(Coalesce("AUTOSAVE_WINDOW", "5m"))
+	config.IdempotencyTTL = cast.ToDuration(Coalesce("IDEMPOTENCY_TTL", "24h"))
 
 	return config
 }
@@ -19,16 +19,64 @@ import (
 const _ = grpc.SupportPackageIsVersion8
 
 const (
-	DocsService_CreateDocument_FullMethodName   = "/doccs.DocsService/CreateDocument"
-	DocsService_GetDocument_FullMethodName      = "/doccs.DocsService/GetDocument"
-	DocsService_GetAllDocuments_FullMethodName  = "/doccs.DocsService/GetAllDocuments"
-	DocsService_UpdateDocument_FullMethodName   = "/doccs.DocsService/UpdateDocument"
-	DocsService_DeleteDocument_FullMethodName   = "/doccs.DocsService/DeleteDocument"
-	DocsService_ShareDocument_FullMethodName    = "/doccs.DocsService/ShareDocument"
-	DocsService_SearchDocument_FullMethodName   = "/doccs.DocsService/SearchDocument"
-	DocsService_GetAllVersions_FullMethodName   = "/doccs.DocsService/GetAllVersions"
-	DocsService_RestoreVersion_FullMethodName   = "/doccs.DocsService/RestoreVersion"
-	DocsService_DownloadDocument_FullMethodName = "/doccs.DocsService/DownloadDocument"
+	DocsService_CreateDocument_FullMethodName         = "/doccs.DocsService/CreateDocument"
+	DocsService_GetDocument_FullMethodName            = "/doccs.DocsService/GetDocument"
+	DocsService_GetAllDocuments_FullMethodName        = "/doccs.DocsService/GetAllDocuments"
+	DocsService_UpdateDocument_FullMethodName         = "/doccs.DocsService/UpdateDocument"
+	DocsService_DeleteDocument_FullMethodName         = "/doccs.DocsService/DeleteDocument"
+	DocsService_ShareDocument_FullMethodName          = "/doccs.DocsService/ShareDocument"
+	DocsService_SearchDocument_FullMethodName         = "/doccs.DocsService/SearchDocument"
+	DocsService_GetAllVersions_FullMethodName         = "/doccs.DocsService/GetAllVersions"
+	DocsService_RestoreVersion_FullMethodName         = "/doccs.DocsService/RestoreVersion"
+	DocsService_DownloadDocument_FullMethodName       = "/doccs.DocsService/DownloadDocument"
+	DocsService_StarDocument_FullMethodName           = "/doccs.DocsService/StarDocument"
+	DocsService_UnstarDocument_FullMethodName         = "/doccs.DocsService/UnstarDocument"
+	DocsService_ListStarredDocuments_FullMethodName   = "/doccs.DocsService/ListStarredDocuments"
+	DocsService_ListRecentDocuments_FullMethodName    = "/doccs.DocsService/ListRecentDocuments"
+	DocsService_AddComment_FullMethodName             = "/doccs.DocsService/AddComment"
+	DocsService_ReplyComment_FullMethodName           = "/doccs.DocsService/ReplyComment"
+	DocsService_ResolveComment_FullMethodName         = "/doccs.DocsService/ResolveComment"
+	DocsService_ReopenComment_FullMethodName          = "/doccs.DocsService/ReopenComment"
+	DocsService_ListComments_FullMethodName           = "/doccs.DocsService/ListComments"
+	DocsService_CreateSuggestion_FullMethodName       = "/doccs.DocsService/CreateSuggestion"
+	DocsService_ListSuggestions_FullMethodName        = "/doccs.DocsService/ListSuggestions"
+	DocsService_AcceptSuggestion_FullMethodName       = "/doccs.DocsService/AcceptSuggestion"
+	DocsService_RejectSuggestion_FullMethodName       = "/doccs.DocsService/RejectSuggestion"
+	DocsService_SaveAsTemplate_FullMethodName         = "/doccs.DocsService/SaveAsTemplate"
+	DocsService_ListTemplates_FullMethodName          = "/doccs.DocsService/ListTemplates"
+	DocsService_CopyDocument_FullMethodName           = "/doccs.DocsService/CopyDocument"
+	DocsService_TransferOwnership_FullMethodName      = "/doccs.DocsService/TransferOwnership"
+	DocsService_CreateShareLink_FullMethodName        = "/doccs.DocsService/CreateShareLink"
+	DocsService_RevokeShareLink_FullMethodName        = "/doccs.DocsService/RevokeShareLink"
+	DocsService_ListShareLinks_FullMethodName         = "/doccs.DocsService/ListShareLinks"
+	DocsService_ResolveShareLink_FullMethodName       = "/doccs.DocsService/ResolveShareLink"
+	DocsService_UnshareDocument_FullMethodName        = "/doccs.DocsService/UnshareDocument"
+	DocsService_ListCollaborators_FullMethodName      = "/doccs.DocsService/ListCollaborators"
+	DocsService_RequestAccess_FullMethodName          = "/doccs.DocsService/RequestAccess"
+	DocsService_ListAccessRequests_FullMethodName     = "/doccs.DocsService/ListAccessRequests"
+	DocsService_ApproveAccessRequest_FullMethodName   = "/doccs.DocsService/ApproveAccessRequest"
+	DocsService_DenyAccessRequest_FullMethodName      = "/doccs.DocsService/DenyAccessRequest"
+	DocsService_ListAuditLog_FullMethodName           = "/doccs.DocsService/ListAuditLog"
+	DocsService_WatchDocument_FullMethodName          = "/doccs.DocsService/WatchDocument"
+	DocsService_CreateWebhook_FullMethodName          = "/doccs.DocsService/CreateWebhook"
+	DocsService_DeleteWebhook_FullMethodName          = "/doccs.DocsService/DeleteWebhook"
+	DocsService_ListWebhooks_FullMethodName           = "/doccs.DocsService/ListWebhooks"
+	DocsService_ListWebhookDeliveries_FullMethodName  = "/doccs.DocsService/ListWebhookDeliveries"
+	DocsService_RetryWebhookDelivery_FullMethodName   = "/doccs.DocsService/RetryWebhookDelivery"
+	DocsService_ImportDocument_FullMethodName         = "/doccs.DocsService/ImportDocument"
+	DocsService_UploadAttachment_FullMethodName       = "/doccs.DocsService/UploadAttachment"
+	DocsService_DownloadAttachment_FullMethodName     = "/doccs.DocsService/DownloadAttachment"
+	DocsService_ListAttachments_FullMethodName        = "/doccs.DocsService/ListAttachments"
+	DocsService_PurgeDocument_FullMethodName          = "/doccs.DocsService/PurgeDocument"
+	DocsService_StreamDocument_FullMethodName         = "/doccs.DocsService/StreamDocument"
+	DocsService_BulkDocuments_FullMethodName          = "/doccs.DocsService/BulkDocuments"
+	DocsService_CreateWorkspace_FullMethodName        = "/doccs.DocsService/CreateWorkspace"
+	DocsService_AddWorkspaceMember_FullMethodName     = "/doccs.DocsService/AddWorkspaceMember"
+	DocsService_RemoveWorkspaceMember_FullMethodName  = "/doccs.DocsService/RemoveWorkspaceMember"
+	DocsService_ListWorkspaces_FullMethodName         = "/doccs.DocsService/ListWorkspaces"
+	DocsService_ListWorkspaceDocuments_FullMethodName = "/doccs.DocsService/ListWorkspaceDocuments"
+	DocsService_GetUsage_FullMethodName               = "/doccs.DocsService/GetUsage"
+	DocsService_SaveVersion_FullMethodName            = "/doccs.DocsService/SaveVersion"
 )
 
 // DocsServiceClient is the client API for DocsService service.
@@ -45,6 +93,54 @@ type DocsServiceClient interface {
 	GetAllVersions(ctx context.Context, in *GetAllVersionsReq, opts ...grpc.CallOption) (*GetAllVersionsRes, error)
 	RestoreVersion(ctx context.Context, in *RestoreVersionReq, opts ...grpc.CallOption) (*RestoreVersionRes, error)
 	DownloadDocument(ctx context.Context, in *DownloadDocumentReq, opts ...grpc.CallOption) (*DownloadDocumentRes, error)
+	StarDocument(ctx context.Context, in *StarDocumentReq, opts ...grpc.CallOption) (*StarDocumentRes, error)
+	UnstarDocument(ctx context.Context, in *UnstarDocumentReq, opts ...grpc.CallOption) (*UnstarDocumentRes, error)
+	ListStarredDocuments(ctx context.Context, in *ListStarredDocumentsReq, opts ...grpc.CallOption) (*ListStarredDocumentsRes, error)
+	ListRecentDocuments(ctx context.Context, in *ListRecentDocumentsReq, opts ...grpc.CallOption) (*ListRecentDocumentsRes, error)
+	AddComment(ctx context.Context, in *AddCommentReq, opts ...grpc.CallOption) (*AddCommentRes, error)
+	ReplyComment(ctx context.Context, in *ReplyCommentReq, opts ...grpc.CallOption) (*ReplyCommentRes, error)
+	ResolveComment(ctx context.Context, in *ResolveCommentReq, opts ...grpc.CallOption) (*ResolveCommentRes, error)
+	ReopenComment(ctx context.Context, in *ReopenCommentReq, opts ...grpc.CallOption) (*ReopenCommentRes, error)
+	ListComments(ctx context.Context, in *ListCommentsReq, opts ...grpc.CallOption) (*ListCommentsRes, error)
+	CreateSuggestion(ctx context.Context, in *CreateSuggestionReq, opts ...grpc.CallOption) (*CreateSuggestionRes, error)
+	ListSuggestions(ctx context.Context, in *ListSuggestionsReq, opts ...grpc.CallOption) (*ListSuggestionsRes, error)
+	AcceptSuggestion(ctx context.Context, in *AcceptSuggestionReq, opts ...grpc.CallOption) (*AcceptSuggestionRes, error)
+	RejectSuggestion(ctx context.Context, in *RejectSuggestionReq, opts ...grpc.CallOption) (*RejectSuggestionRes, error)
+	SaveAsTemplate(ctx context.Context, in *SaveAsTemplateReq, opts ...grpc.CallOption) (*SaveAsTemplateRes, error)
+	ListTemplates(ctx context.Context, in *ListTemplatesReq, opts ...grpc.CallOption) (*ListTemplatesRes, error)
+	CopyDocument(ctx context.Context, in *CopyDocumentReq, opts ...grpc.CallOption) (*CopyDocumentRes, error)
+	TransferOwnership(ctx context.Context, in *TransferOwnershipReq, opts ...grpc.CallOption) (*TransferOwnershipRes, error)
+	CreateShareLink(ctx context.Context, in *CreateShareLinkReq, opts ...grpc.CallOption) (*CreateShareLinkRes, error)
+	RevokeShareLink(ctx context.Context, in *RevokeShareLinkReq, opts ...grpc.CallOption) (*RevokeShareLinkRes, error)
+	ListShareLinks(ctx context.Context, in *ListShareLinksReq, opts ...grpc.CallOption) (*ListShareLinksRes, error)
+	ResolveShareLink(ctx context.Context, in *ResolveShareLinkReq, opts ...grpc.CallOption) (*ResolveShareLinkRes, error)
+	UnshareDocument(ctx context.Context, in *UnshareDocumentReq, opts ...grpc.CallOption) (*UnshareDocumentRes, error)
+	ListCollaborators(ctx context.Context, in *ListCollaboratorsReq, opts ...grpc.CallOption) (*ListCollaboratorsRes, error)
+	RequestAccess(ctx context.Context, in *RequestAccessReq, opts ...grpc.CallOption) (*RequestAccessRes, error)
+	ListAccessRequests(ctx context.Context, in *ListAccessRequestsReq, opts ...grpc.CallOption) (*ListAccessRequestsRes, error)
+	ApproveAccessRequest(ctx context.Context, in *ApproveAccessRequestReq, opts ...grpc.CallOption) (*ApproveAccessRequestRes, error)
+	DenyAccessRequest(ctx context.Context, in *DenyAccessRequestReq, opts ...grpc.CallOption) (*DenyAccessRequestRes, error)
+	ListAuditLog(ctx context.Context, in *ListAuditLogReq, opts ...grpc.CallOption) (*ListAuditLogRes, error)
+	WatchDocument(ctx context.Context, in *WatchDocumentReq, opts ...grpc.CallOption) (DocsService_WatchDocumentClient, error)
+	CreateWebhook(ctx context.Context, in *CreateWebhookReq, opts ...grpc.CallOption) (*CreateWebhookRes, error)
+	DeleteWebhook(ctx context.Context, in *DeleteWebhookReq, opts ...grpc.CallOption) (*DeleteWebhookRes, error)
+	ListWebhooks(ctx context.Context, in *ListWebhooksReq, opts ...grpc.CallOption) (*ListWebhooksRes, error)
+	ListWebhookDeliveries(ctx context.Context, in *ListWebhookDeliveriesReq, opts ...grpc.CallOption) (*ListWebhookDeliveriesRes, error)
+	RetryWebhookDelivery(ctx context.Context, in *RetryWebhookDeliveryReq, opts ...grpc.CallOption) (*RetryWebhookDeliveryRes, error)
+	ImportDocument(ctx context.Context, opts ...grpc.CallOption) (DocsService_ImportDocumentClient, error)
+	UploadAttachment(ctx context.Context, opts ...grpc.CallOption) (DocsService_UploadAttachmentClient, error)
+	DownloadAttachment(ctx context.Context, in *DownloadAttachmentReq, opts ...grpc.CallOption) (DocsService_DownloadAttachmentClient, error)
+	ListAttachments(ctx context.Context, in *ListAttachmentsReq, opts ...grpc.CallOption) (*ListAttachmentsRes, error)
+	PurgeDocument(ctx context.Context, in *PurgeDocumentReq, opts ...grpc.CallOption) (*PurgeDocumentRes, error)
+	StreamDocument(ctx context.Context, in *StreamDocumentReq, opts ...grpc.CallOption) (DocsService_StreamDocumentClient, error)
+	BulkDocuments(ctx context.Context, in *BulkDocumentsReq, opts ...grpc.CallOption) (*BulkDocumentsRes, error)
+	CreateWorkspace(ctx context.Context, in *CreateWorkspaceReq, opts ...grpc.CallOption) (*CreateWorkspaceRes, error)
+	AddWorkspaceMember(ctx context.Context, in *AddWorkspaceMemberReq, opts ...grpc.CallOption) (*AddWorkspaceMemberRes, error)
+	RemoveWorkspaceMember(ctx context.Context, in *RemoveWorkspaceMemberReq, opts ...grpc.CallOption) (*RemoveWorkspaceMemberRes, error)
+	ListWorkspaces(ctx context.Context, in *ListWorkspacesReq, opts ...grpc.CallOption) (*ListWorkspacesRes, error)
+	ListWorkspaceDocuments(ctx context.Context, in *ListWorkspaceDocumentsReq, opts ...grpc.CallOption) (*ListWorkspaceDocumentsRes, error)
+	GetUsage(ctx context.Context, in *GetUsageReq, opts ...grpc.CallOption) (*GetUsageRes, error)
+	SaveVersion(ctx context.Context, in *SaveVersionReq, opts ...grpc.CallOption) (*SaveVersionRes, error)
 }
 
 type docsServiceClient struct {
@@ -155,6 +251,605 @@ func (c *docsServiceClient) DownloadDocument(ctx context.Context, in *DownloadDo
 	return out, nil
 }
 
+func (c *docsServiceClient) StarDocument(ctx context.Context, in *StarDocumentReq, opts ...grpc.CallOption) (*StarDocumentRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StarDocumentRes)
+	err := c.cc.Invoke(ctx, DocsService_StarDocument_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *docsServiceClient) UnstarDocument(ctx context.Context, in *UnstarDocumentReq, opts ...grpc.CallOption) (*UnstarDocumentRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UnstarDocumentRes)
+	err := c.cc.Invoke(ctx, DocsService_UnstarDocument_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *docsServiceClient) ListStarredDocuments(ctx context.Context, in *ListStarredDocumentsReq, opts ...grpc.CallOption) (*ListStarredDocumentsRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListStarredDocumentsRes)
+	err := c.cc.Invoke(ctx, DocsService_ListStarredDocuments_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *docsServiceClient) ListRecentDocuments(ctx context.Context, in *ListRecentDocumentsReq, opts ...grpc.CallOption) (*ListRecentDocumentsRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRecentDocumentsRes)
+	err := c.cc.Invoke(ctx, DocsService_ListRecentDocuments_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *docsServiceClient) AddComment(ctx context.Context, in *AddCommentReq, opts ...grpc.CallOption) (*AddCommentRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddCommentRes)
+	err := c.cc.Invoke(ctx, DocsService_AddComment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *docsServiceClient) ReplyComment(ctx context.Context, in *ReplyCommentReq, opts ...grpc.CallOption) (*ReplyCommentRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReplyCommentRes)
+	err := c.cc.Invoke(ctx, DocsService_ReplyComment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *docsServiceClient) ResolveComment(ctx context.Context, in *ResolveCommentReq, opts ...grpc.CallOption) (*ResolveCommentRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResolveCommentRes)
+	err := c.cc.Invoke(ctx, DocsService_ResolveComment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *docsServiceClient) ReopenComment(ctx context.Context, in *ReopenCommentReq, opts ...grpc.CallOption) (*ReopenCommentRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReopenCommentRes)
+	err := c.cc.Invoke(ctx, DocsService_ReopenComment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *docsServiceClient) ListComments(ctx context.Context, in *ListCommentsReq, opts ...grpc.CallOption) (*ListCommentsRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCommentsRes)
+	err := c.cc.Invoke(ctx, DocsService_ListComments_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *docsServiceClient) CreateSuggestion(ctx context.Context, in *CreateSuggestionReq, opts ...grpc.CallOption) (*CreateSuggestionRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateSuggestionRes)
+	err := c.cc.Invoke(ctx, DocsService_CreateSuggestion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *docsServiceClient) ListSuggestions(ctx context.Context, in *ListSuggestionsReq, opts ...grpc.CallOption) (*ListSuggestionsRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSuggestionsRes)
+	err := c.cc.Invoke(ctx, DocsService_ListSuggestions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *docsServiceClient) AcceptSuggestion(ctx context.Context, in *AcceptSuggestionReq, opts ...grpc.CallOption) (*AcceptSuggestionRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AcceptSuggestionRes)
+	err := c.cc.Invoke(ctx, DocsService_AcceptSuggestion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *docsServiceClient) RejectSuggestion(ctx context.Context, in *RejectSuggestionReq, opts ...grpc.CallOption) (*RejectSuggestionRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RejectSuggestionRes)
+	err := c.cc.Invoke(ctx, DocsService_RejectSuggestion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *docsServiceClient) SaveAsTemplate(ctx context.Context, in *SaveAsTemplateReq, opts ...grpc.CallOption) (*SaveAsTemplateRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SaveAsTemplateRes)
+	err := c.cc.Invoke(ctx, DocsService_SaveAsTemplate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *docsServiceClient) ListTemplates(ctx context.Context, in *ListTemplatesReq, opts ...grpc.CallOption) (*ListTemplatesRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTemplatesRes)
+	err := c.cc.Invoke(ctx, DocsService_ListTemplates_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *docsServiceClient) CopyDocument(ctx context.Context, in *CopyDocumentReq, opts ...grpc.CallOption) (*CopyDocumentRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CopyDocumentRes)
+	err := c.cc.Invoke(ctx, DocsService_CopyDocument_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *docsServiceClient) TransferOwnership(ctx context.Context, in *TransferOwnershipReq, opts ...grpc.CallOption) (*TransferOwnershipRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TransferOwnershipRes)
+	err := c.cc.Invoke(ctx, DocsService_TransferOwnership_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *docsServiceClient) CreateShareLink(ctx context.Context, in *CreateShareLinkReq, opts ...grpc.CallOption) (*CreateShareLinkRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateShareLinkRes)
+	err := c.cc.Invoke(ctx, DocsService_CreateShareLink_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *docsServiceClient) RevokeShareLink(ctx context.Context, in *RevokeShareLinkReq, opts ...grpc.CallOption) (*RevokeShareLinkRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeShareLinkRes)
+	err := c.cc.Invoke(ctx, DocsService_RevokeShareLink_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *docsServiceClient) ListShareLinks(ctx context.Context, in *ListShareLinksReq, opts ...grpc.CallOption) (*ListShareLinksRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListShareLinksRes)
+	err := c.cc.Invoke(ctx, DocsService_ListShareLinks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *docsServiceClient) ResolveShareLink(ctx context.Context, in *ResolveShareLinkReq, opts ...grpc.CallOption) (*ResolveShareLinkRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResolveShareLinkRes)
+	err := c.cc.Invoke(ctx, DocsService_ResolveShareLink_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *docsServiceClient) UnshareDocument(ctx context.Context, in *UnshareDocumentReq, opts ...grpc.CallOption) (*UnshareDocumentRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UnshareDocumentRes)
+	err := c.cc.Invoke(ctx, DocsService_UnshareDocument_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *docsServiceClient) ListCollaborators(ctx context.Context, in *ListCollaboratorsReq, opts ...grpc.CallOption) (*ListCollaboratorsRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCollaboratorsRes)
+	err := c.cc.Invoke(ctx, DocsService_ListCollaborators_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *docsServiceClient) RequestAccess(ctx context.Context, in *RequestAccessReq, opts ...grpc.CallOption) (*RequestAccessRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RequestAccessRes)
+	err := c.cc.Invoke(ctx, DocsService_RequestAccess_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *docsServiceClient) ListAccessRequests(ctx context.Context, in *ListAccessRequestsReq, opts ...grpc.CallOption) (*ListAccessRequestsRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAccessRequestsRes)
+	err := c.cc.Invoke(ctx, DocsService_ListAccessRequests_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *docsServiceClient) ApproveAccessRequest(ctx context.Context, in *ApproveAccessRequestReq, opts ...grpc.CallOption) (*ApproveAccessRequestRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ApproveAccessRequestRes)
+	err := c.cc.Invoke(ctx, DocsService_ApproveAccessRequest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *docsServiceClient) DenyAccessRequest(ctx context.Context, in *DenyAccessRequestReq, opts ...grpc.CallOption) (*DenyAccessRequestRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DenyAccessRequestRes)
+	err := c.cc.Invoke(ctx, DocsService_DenyAccessRequest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *docsServiceClient) ListAuditLog(ctx context.Context, in *ListAuditLogReq, opts ...grpc.CallOption) (*ListAuditLogRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAuditLogRes)
+	err := c.cc.Invoke(ctx, DocsService_ListAuditLog_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *docsServiceClient) WatchDocument(ctx context.Context, in *WatchDocumentReq, opts ...grpc.CallOption) (DocsService_WatchDocumentClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DocsService_ServiceDesc.Streams[0], DocsService_WatchDocument_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &docsServiceWatchDocumentClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DocsService_WatchDocumentClient interface {
+	Recv() (*DocumentEvent, error)
+	grpc.ClientStream
+}
+
+type docsServiceWatchDocumentClient struct {
+	grpc.ClientStream
+}
+
+func (x *docsServiceWatchDocumentClient) Recv() (*DocumentEvent, error) {
+	m := new(DocumentEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *docsServiceClient) CreateWebhook(ctx context.Context, in *CreateWebhookReq, opts ...grpc.CallOption) (*CreateWebhookRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateWebhookRes)
+	err := c.cc.Invoke(ctx, DocsService_CreateWebhook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *docsServiceClient) DeleteWebhook(ctx context.Context, in *DeleteWebhookReq, opts ...grpc.CallOption) (*DeleteWebhookRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteWebhookRes)
+	err := c.cc.Invoke(ctx, DocsService_DeleteWebhook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *docsServiceClient) ListWebhooks(ctx context.Context, in *ListWebhooksReq, opts ...grpc.CallOption) (*ListWebhooksRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListWebhooksRes)
+	err := c.cc.Invoke(ctx, DocsService_ListWebhooks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *docsServiceClient) ListWebhookDeliveries(ctx context.Context, in *ListWebhookDeliveriesReq, opts ...grpc.CallOption) (*ListWebhookDeliveriesRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListWebhookDeliveriesRes)
+	err := c.cc.Invoke(ctx, DocsService_ListWebhookDeliveries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *docsServiceClient) RetryWebhookDelivery(ctx context.Context, in *RetryWebhookDeliveryReq, opts ...grpc.CallOption) (*RetryWebhookDeliveryRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RetryWebhookDeliveryRes)
+	err := c.cc.Invoke(ctx, DocsService_RetryWebhookDelivery_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *docsServiceClient) ImportDocument(ctx context.Context, opts ...grpc.CallOption) (DocsService_ImportDocumentClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DocsService_ServiceDesc.Streams[1], DocsService_ImportDocument_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &docsServiceImportDocumentClient{ClientStream: stream}
+	return x, nil
+}
+
+type DocsService_ImportDocumentClient interface {
+	Send(*ImportDocumentReq) error
+	CloseAndRecv() (*ImportDocumentRes, error)
+	grpc.ClientStream
+}
+
+type docsServiceImportDocumentClient struct {
+	grpc.ClientStream
+}
+
+func (x *docsServiceImportDocumentClient) Send(m *ImportDocumentReq) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *docsServiceImportDocumentClient) CloseAndRecv() (*ImportDocumentRes, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ImportDocumentRes)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *docsServiceClient) UploadAttachment(ctx context.Context, opts ...grpc.CallOption) (DocsService_UploadAttachmentClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DocsService_ServiceDesc.Streams[2], DocsService_UploadAttachment_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &docsServiceUploadAttachmentClient{ClientStream: stream}
+	return x, nil
+}
+
+type DocsService_UploadAttachmentClient interface {
+	Send(*UploadAttachmentReq) error
+	CloseAndRecv() (*UploadAttachmentRes, error)
+	grpc.ClientStream
+}
+
+type docsServiceUploadAttachmentClient struct {
+	grpc.ClientStream
+}
+
+func (x *docsServiceUploadAttachmentClient) Send(m *UploadAttachmentReq) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *docsServiceUploadAttachmentClient) CloseAndRecv() (*UploadAttachmentRes, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(UploadAttachmentRes)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *docsServiceClient) DownloadAttachment(ctx context.Context, in *DownloadAttachmentReq, opts ...grpc.CallOption) (DocsService_DownloadAttachmentClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DocsService_ServiceDesc.Streams[3], DocsService_DownloadAttachment_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &docsServiceDownloadAttachmentClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DocsService_DownloadAttachmentClient interface {
+	Recv() (*AttachmentChunk, error)
+	grpc.ClientStream
+}
+
+type docsServiceDownloadAttachmentClient struct {
+	grpc.ClientStream
+}
+
+func (x *docsServiceDownloadAttachmentClient) Recv() (*AttachmentChunk, error) {
+	m := new(AttachmentChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *docsServiceClient) ListAttachments(ctx context.Context, in *ListAttachmentsReq, opts ...grpc.CallOption) (*ListAttachmentsRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAttachmentsRes)
+	err := c.cc.Invoke(ctx, DocsService_ListAttachments_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *docsServiceClient) PurgeDocument(ctx context.Context, in *PurgeDocumentReq, opts ...grpc.CallOption) (*PurgeDocumentRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PurgeDocumentRes)
+	err := c.cc.Invoke(ctx, DocsService_PurgeDocument_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *docsServiceClient) StreamDocument(ctx context.Context, in *StreamDocumentReq, opts ...grpc.CallOption) (DocsService_StreamDocumentClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DocsService_ServiceDesc.Streams[4], DocsService_StreamDocument_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &docsServiceStreamDocumentClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DocsService_StreamDocumentClient interface {
+	Recv() (*DocumentChunk, error)
+	grpc.ClientStream
+}
+
+type docsServiceStreamDocumentClient struct {
+	grpc.ClientStream
+}
+
+func (x *docsServiceStreamDocumentClient) Recv() (*DocumentChunk, error) {
+	m := new(DocumentChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *docsServiceClient) BulkDocuments(ctx context.Context, in *BulkDocumentsReq, opts ...grpc.CallOption) (*BulkDocumentsRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BulkDocumentsRes)
+	err := c.cc.Invoke(ctx, DocsService_BulkDocuments_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *docsServiceClient) CreateWorkspace(ctx context.Context, in *CreateWorkspaceReq, opts ...grpc.CallOption) (*CreateWorkspaceRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateWorkspaceRes)
+	err := c.cc.Invoke(ctx, DocsService_CreateWorkspace_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *docsServiceClient) AddWorkspaceMember(ctx context.Context, in *AddWorkspaceMemberReq, opts ...grpc.CallOption) (*AddWorkspaceMemberRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddWorkspaceMemberRes)
+	err := c.cc.Invoke(ctx, DocsService_AddWorkspaceMember_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *docsServiceClient) RemoveWorkspaceMember(ctx context.Context, in *RemoveWorkspaceMemberReq, opts ...grpc.CallOption) (*RemoveWorkspaceMemberRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemoveWorkspaceMemberRes)
+	err := c.cc.Invoke(ctx, DocsService_RemoveWorkspaceMember_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *docsServiceClient) ListWorkspaces(ctx context.Context, in *ListWorkspacesReq, opts ...grpc.CallOption) (*ListWorkspacesRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListWorkspacesRes)
+	err := c.cc.Invoke(ctx, DocsService_ListWorkspaces_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *docsServiceClient) ListWorkspaceDocuments(ctx context.Context, in *ListWorkspaceDocumentsReq, opts ...grpc.CallOption) (*ListWorkspaceDocumentsRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListWorkspaceDocumentsRes)
+	err := c.cc.Invoke(ctx, DocsService_ListWorkspaceDocuments_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *docsServiceClient) GetUsage(ctx context.Context, in *GetUsageReq, opts ...grpc.CallOption) (*GetUsageRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetUsageRes)
+	err := c.cc.Invoke(ctx, DocsService_GetUsage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *docsServiceClient) SaveVersion(ctx context.Context, in *SaveVersionReq, opts ...grpc.CallOption) (*SaveVersionRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SaveVersionRes)
+	err := c.cc.Invoke(ctx, DocsService_SaveVersion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DocsServiceServer is the server API for DocsService service.
 // All implementations must embed UnimplementedDocsServiceServer
 // for forward compatibility
@@ -169,232 +864,1313 @@ type DocsServiceServer interface {
 	GetAllVersions(context.Context, *GetAllVersionsReq) (*GetAllVersionsRes, error)
 	RestoreVersion(context.Context, *RestoreVersionReq) (*RestoreVersionRes, error)
 	DownloadDocument(context.Context, *DownloadDocumentReq) (*DownloadDocumentRes, error)
+	StarDocument(context.Context, *StarDocumentReq) (*StarDocumentRes, error)
+	UnstarDocument(context.Context, *UnstarDocumentReq) (*UnstarDocumentRes, error)
+	ListStarredDocuments(context.Context, *ListStarredDocumentsReq) (*ListStarredDocumentsRes, error)
+	ListRecentDocuments(context.Context, *ListRecentDocumentsReq) (*ListRecentDocumentsRes, error)
+	AddComment(context.Context, *AddCommentReq) (*AddCommentRes, error)
+	ReplyComment(context.Context, *ReplyCommentReq) (*ReplyCommentRes, error)
+	ResolveComment(context.Context, *ResolveCommentReq) (*ResolveCommentRes, error)
+	ReopenComment(context.Context, *ReopenCommentReq) (*ReopenCommentRes, error)
+	ListComments(context.Context, *ListCommentsReq) (*ListCommentsRes, error)
+	CreateSuggestion(context.Context, *CreateSuggestionReq) (*CreateSuggestionRes, error)
+	ListSuggestions(context.Context, *ListSuggestionsReq) (*ListSuggestionsRes, error)
+	AcceptSuggestion(context.Context, *AcceptSuggestionReq) (*AcceptSuggestionRes, error)
+	RejectSuggestion(context.Context, *RejectSuggestionReq) (*RejectSuggestionRes, error)
+	SaveAsTemplate(context.Context, *SaveAsTemplateReq) (*SaveAsTemplateRes, error)
+	ListTemplates(context.Context, *ListTemplatesReq) (*ListTemplatesRes, error)
+	CopyDocument(context.Context, *CopyDocumentReq) (*CopyDocumentRes, error)
+	TransferOwnership(context.Context, *TransferOwnershipReq) (*TransferOwnershipRes, error)
+	CreateShareLink(context.Context, *CreateShareLinkReq) (*CreateShareLinkRes, error)
+	RevokeShareLink(context.Context, *RevokeShareLinkReq) (*RevokeShareLinkRes, error)
+	ListShareLinks(context.Context, *ListShareLinksReq) (*ListShareLinksRes, error)
+	ResolveShareLink(context.Context, *ResolveShareLinkReq) (*ResolveShareLinkRes, error)
+	UnshareDocument(context.Context, *UnshareDocumentReq) (*UnshareDocumentRes, error)
+	ListCollaborators(context.Context, *ListCollaboratorsReq) (*ListCollaboratorsRes, error)
+	RequestAccess(context.Context, *RequestAccessReq) (*RequestAccessRes, error)
+	ListAccessRequests(context.Context, *ListAccessRequestsReq) (*ListAccessRequestsRes, error)
+	ApproveAccessRequest(context.Context, *ApproveAccessRequestReq) (*ApproveAccessRequestRes, error)
+	DenyAccessRequest(context.Context, *DenyAccessRequestReq) (*DenyAccessRequestRes, error)
+	ListAuditLog(context.Context, *ListAuditLogReq) (*ListAuditLogRes, error)
+	WatchDocument(*WatchDocumentReq, DocsService_WatchDocumentServer) error
+	CreateWebhook(context.Context, *CreateWebhookReq) (*CreateWebhookRes, error)
+	DeleteWebhook(context.Context, *DeleteWebhookReq) (*DeleteWebhookRes, error)
+	ListWebhooks(context.Context, *ListWebhooksReq) (*ListWebhooksRes, error)
+	ListWebhookDeliveries(context.Context, *ListWebhookDeliveriesReq) (*ListWebhookDeliveriesRes, error)
+	RetryWebhookDelivery(context.Context, *RetryWebhookDeliveryReq) (*RetryWebhookDeliveryRes, error)
+	ImportDocument(DocsService_ImportDocumentServer) error
+	UploadAttachment(DocsService_UploadAttachmentServer) error
+	DownloadAttachment(*DownloadAttachmentReq, DocsService_DownloadAttachmentServer) error
+	ListAttachments(context.Context, *ListAttachmentsReq) (*ListAttachmentsRes, error)
+	PurgeDocument(context.Context, *PurgeDocumentReq) (*PurgeDocumentRes, error)
+	StreamDocument(*StreamDocumentReq, DocsService_StreamDocumentServer) error
+	BulkDocuments(context.Context, *BulkDocumentsReq) (*BulkDocumentsRes, error)
+	CreateWorkspace(context.Context, *CreateWorkspaceReq) (*CreateWorkspaceRes, error)
+	AddWorkspaceMember(context.Context, *AddWorkspaceMemberReq) (*AddWorkspaceMemberRes, error)
+	RemoveWorkspaceMember(context.Context, *RemoveWorkspaceMemberReq) (*RemoveWorkspaceMemberRes, error)
+	ListWorkspaces(context.Context, *ListWorkspacesReq) (*ListWorkspacesRes, error)
+	ListWorkspaceDocuments(context.Context, *ListWorkspaceDocumentsReq) (*ListWorkspaceDocumentsRes, error)
+	GetUsage(context.Context, *GetUsageReq) (*GetUsageRes, error)
+	SaveVersion(context.Context, *SaveVersionReq) (*SaveVersionRes, error)
+	mustEmbedUnimplementedDocsServiceServer()
+}
+
+// UnimplementedDocsServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedDocsServiceServer struct {
+}
+
+func (UnimplementedDocsServiceServer) CreateDocument(context.Context, *CreateDocumentReq) (*CreateDocumentRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateDocument not implemented")
+}
+func (UnimplementedDocsServiceServer) GetDocument(context.Context, *GetDocumentReq) (*GetDocumentRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDocument not implemented")
+}
+func (UnimplementedDocsServiceServer) GetAllDocuments(context.Context, *GetAllDocumentsReq) (*GetAllDocumentsRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAllDocuments not implemented")
+}
+func (UnimplementedDocsServiceServer) UpdateDocument(context.Context, *UpdateDocumentReq) (*UpdateDocumentRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateDocument not implemented")
+}
+func (UnimplementedDocsServiceServer) DeleteDocument(context.Context, *DeleteDocumentReq) (*DeleteDocumentRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteDocument not implemented")
+}
+func (UnimplementedDocsServiceServer) ShareDocument(context.Context, *ShareDocumentReq) (*ShareDocumentRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ShareDocument not implemented")
+}
+func (UnimplementedDocsServiceServer) SearchDocument(context.Context, *SearchDocumentReq) (*SearchDocumentRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchDocument not implemented")
+}
+func (UnimplementedDocsServiceServer) GetAllVersions(context.Context, *GetAllVersionsReq) (*GetAllVersionsRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAllVersions not implemented")
+}
+func (UnimplementedDocsServiceServer) RestoreVersion(context.Context, *RestoreVersionReq) (*RestoreVersionRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreVersion not implemented")
+}
+func (UnimplementedDocsServiceServer) DownloadDocument(context.Context, *DownloadDocumentReq) (*DownloadDocumentRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DownloadDocument not implemented")
+}
+func (UnimplementedDocsServiceServer) StarDocument(context.Context, *StarDocumentReq) (*StarDocumentRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StarDocument not implemented")
+}
+func (UnimplementedDocsServiceServer) UnstarDocument(context.Context, *UnstarDocumentReq) (*UnstarDocumentRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnstarDocument not implemented")
+}
+func (UnimplementedDocsServiceServer) ListStarredDocuments(context.Context, *ListStarredDocumentsReq) (*ListStarredDocumentsRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListStarredDocuments not implemented")
+}
+func (UnimplementedDocsServiceServer) ListRecentDocuments(context.Context, *ListRecentDocumentsReq) (*ListRecentDocumentsRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRecentDocuments not implemented")
+}
+func (UnimplementedDocsServiceServer) AddComment(context.Context, *AddCommentReq) (*AddCommentRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddComment not implemented")
+}
+func (UnimplementedDocsServiceServer) ReplyComment(context.Context, *ReplyCommentReq) (*ReplyCommentRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplyComment not implemented")
+}
+func (UnimplementedDocsServiceServer) ResolveComment(context.Context, *ResolveCommentReq) (*ResolveCommentRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResolveComment not implemented")
+}
+func (UnimplementedDocsServiceServer) ReopenComment(context.Context, *ReopenCommentReq) (*ReopenCommentRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReopenComment not implemented")
+}
+func (UnimplementedDocsServiceServer) ListComments(context.Context, *ListCommentsReq) (*ListCommentsRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListComments not implemented")
+}
+func (UnimplementedDocsServiceServer) CreateSuggestion(context.Context, *CreateSuggestionReq) (*CreateSuggestionRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSuggestion not implemented")
+}
+func (UnimplementedDocsServiceServer) ListSuggestions(context.Context, *ListSuggestionsReq) (*ListSuggestionsRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSuggestions not implemented")
+}
+func (UnimplementedDocsServiceServer) AcceptSuggestion(context.Context, *AcceptSuggestionReq) (*AcceptSuggestionRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AcceptSuggestion not implemented")
+}
+func (UnimplementedDocsServiceServer) RejectSuggestion(context.Context, *RejectSuggestionReq) (*RejectSuggestionRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RejectSuggestion not implemented")
+}
+func (UnimplementedDocsServiceServer) SaveAsTemplate(context.Context, *SaveAsTemplateReq) (*SaveAsTemplateRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SaveAsTemplate not implemented")
+}
+func (UnimplementedDocsServiceServer) ListTemplates(context.Context, *ListTemplatesReq) (*ListTemplatesRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTemplates not implemented")
+}
+func (UnimplementedDocsServiceServer) CopyDocument(context.Context, *CopyDocumentReq) (*CopyDocumentRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CopyDocument not implemented")
+}
+func (UnimplementedDocsServiceServer) TransferOwnership(context.Context, *TransferOwnershipReq) (*TransferOwnershipRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TransferOwnership not implemented")
+}
+func (UnimplementedDocsServiceServer) CreateShareLink(context.Context, *CreateShareLinkReq) (*CreateShareLinkRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateShareLink not implemented")
+}
+func (UnimplementedDocsServiceServer) RevokeShareLink(context.Context, *RevokeShareLinkReq) (*RevokeShareLinkRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeShareLink not implemented")
+}
+func (UnimplementedDocsServiceServer) ListShareLinks(context.Context, *ListShareLinksReq) (*ListShareLinksRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListShareLinks not implemented")
+}
+func (UnimplementedDocsServiceServer) ResolveShareLink(context.Context, *ResolveShareLinkReq) (*ResolveShareLinkRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResolveShareLink not implemented")
+}
+func (UnimplementedDocsServiceServer) UnshareDocument(context.Context, *UnshareDocumentReq) (*UnshareDocumentRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnshareDocument not implemented")
+}
+func (UnimplementedDocsServiceServer) ListCollaborators(context.Context, *ListCollaboratorsReq) (*ListCollaboratorsRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCollaborators not implemented")
+}
+func (UnimplementedDocsServiceServer) RequestAccess(context.Context, *RequestAccessReq) (*RequestAccessRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestAccess not implemented")
+}
+func (UnimplementedDocsServiceServer) ListAccessRequests(context.Context, *ListAccessRequestsReq) (*ListAccessRequestsRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAccessRequests not implemented")
+}
+func (UnimplementedDocsServiceServer) ApproveAccessRequest(context.Context, *ApproveAccessRequestReq) (*ApproveAccessRequestRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApproveAccessRequest not implemented")
+}
+func (UnimplementedDocsServiceServer) DenyAccessRequest(context.Context, *DenyAccessRequestReq) (*DenyAccessRequestRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DenyAccessRequest not implemented")
+}
+func (UnimplementedDocsServiceServer) ListAuditLog(context.Context, *ListAuditLogReq) (*ListAuditLogRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAuditLog not implemented")
+}
+func (UnimplementedDocsServiceServer) WatchDocument(*WatchDocumentReq, DocsService_WatchDocumentServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchDocument not implemented")
+}
+func (UnimplementedDocsServiceServer) CreateWebhook(context.Context, *CreateWebhookReq) (*CreateWebhookRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateWebhook not implemented")
+}
+func (UnimplementedDocsServiceServer) DeleteWebhook(context.Context, *DeleteWebhookReq) (*DeleteWebhookRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteWebhook not implemented")
+}
+func (UnimplementedDocsServiceServer) ListWebhooks(context.Context, *ListWebhooksReq) (*ListWebhooksRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhooks not implemented")
+}
+func (UnimplementedDocsServiceServer) ListWebhookDeliveries(context.Context, *ListWebhookDeliveriesReq) (*ListWebhookDeliveriesRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhookDeliveries not implemented")
+}
+func (UnimplementedDocsServiceServer) RetryWebhookDelivery(context.Context, *RetryWebhookDeliveryReq) (*RetryWebhookDeliveryRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RetryWebhookDelivery not implemented")
+}
+func (UnimplementedDocsServiceServer) ImportDocument(DocsService_ImportDocumentServer) error {
+	return status.Errorf(codes.Unimplemented, "method ImportDocument not implemented")
+}
+func (UnimplementedDocsServiceServer) UploadAttachment(DocsService_UploadAttachmentServer) error {
+	return status.Errorf(codes.Unimplemented, "method UploadAttachment not implemented")
+}
+func (UnimplementedDocsServiceServer) DownloadAttachment(*DownloadAttachmentReq, DocsService_DownloadAttachmentServer) error {
+	return status.Errorf(codes.Unimplemented, "method DownloadAttachment not implemented")
+}
+func (UnimplementedDocsServiceServer) ListAttachments(context.Context, *ListAttachmentsReq) (*ListAttachmentsRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAttachments not implemented")
+}
+func (UnimplementedDocsServiceServer) PurgeDocument(context.Context, *PurgeDocumentReq) (*PurgeDocumentRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurgeDocument not implemented")
+}
+func (UnimplementedDocsServiceServer) StreamDocument(*StreamDocumentReq, DocsService_StreamDocumentServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamDocument not implemented")
+}
+func (UnimplementedDocsServiceServer) BulkDocuments(context.Context, *BulkDocumentsReq) (*BulkDocumentsRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BulkDocuments not implemented")
+}
+func (UnimplementedDocsServiceServer) CreateWorkspace(context.Context, *CreateWorkspaceReq) (*CreateWorkspaceRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateWorkspace not implemented")
+}
+func (UnimplementedDocsServiceServer) AddWorkspaceMember(context.Context, *AddWorkspaceMemberReq) (*AddWorkspaceMemberRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddWorkspaceMember not implemented")
+}
+func (UnimplementedDocsServiceServer) RemoveWorkspaceMember(context.Context, *RemoveWorkspaceMemberReq) (*RemoveWorkspaceMemberRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveWorkspaceMember not implemented")
+}
+func (UnimplementedDocsServiceServer) ListWorkspaces(context.Context, *ListWorkspacesReq) (*ListWorkspacesRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWorkspaces not implemented")
+}
+func (UnimplementedDocsServiceServer) ListWorkspaceDocuments(context.Context, *ListWorkspaceDocumentsReq) (*ListWorkspaceDocumentsRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWorkspaceDocuments not implemented")
+}
+func (UnimplementedDocsServiceServer) GetUsage(context.Context, *GetUsageReq) (*GetUsageRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUsage not implemented")
+}
+func (UnimplementedDocsServiceServer) SaveVersion(context.Context, *SaveVersionReq) (*SaveVersionRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SaveVersion not implemented")
+}
+func (UnimplementedDocsServiceServer) mustEmbedUnimplementedDocsServiceServer() {}
+
+// UnsafeDocsServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DocsServiceServer will
+// result in compilation errors.
+type UnsafeDocsServiceServer interface {
 	mustEmbedUnimplementedDocsServiceServer()
 }
 
-// UnimplementedDocsServiceServer must be embedded to have forward compatible implementations.
-type UnimplementedDocsServiceServer struct {
+func RegisterDocsServiceServer(s grpc.ServiceRegistrar, srv DocsServiceServer) {
+	s.RegisterService(&DocsService_ServiceDesc, srv)
+}
+
+func _DocsService_CreateDocument_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateDocumentReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocsServiceServer).CreateDocument(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DocsService_CreateDocument_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocsServiceServer).CreateDocument(ctx, req.(*CreateDocumentReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DocsService_GetDocument_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDocumentReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocsServiceServer).GetDocument(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DocsService_GetDocument_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocsServiceServer).GetDocument(ctx, req.(*GetDocumentReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DocsService_GetAllDocuments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAllDocumentsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocsServiceServer).GetAllDocuments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DocsService_GetAllDocuments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocsServiceServer).GetAllDocuments(ctx, req.(*GetAllDocumentsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DocsService_UpdateDocument_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateDocumentReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocsServiceServer).UpdateDocument(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DocsService_UpdateDocument_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocsServiceServer).UpdateDocument(ctx, req.(*UpdateDocumentReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DocsService_DeleteDocument_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteDocumentReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocsServiceServer).DeleteDocument(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DocsService_DeleteDocument_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocsServiceServer).DeleteDocument(ctx, req.(*DeleteDocumentReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DocsService_ShareDocument_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShareDocumentReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocsServiceServer).ShareDocument(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DocsService_ShareDocument_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocsServiceServer).ShareDocument(ctx, req.(*ShareDocumentReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DocsService_SearchDocument_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchDocumentReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocsServiceServer).SearchDocument(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DocsService_SearchDocument_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocsServiceServer).SearchDocument(ctx, req.(*SearchDocumentReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DocsService_GetAllVersions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAllVersionsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocsServiceServer).GetAllVersions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DocsService_GetAllVersions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocsServiceServer).GetAllVersions(ctx, req.(*GetAllVersionsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DocsService_RestoreVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreVersionReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocsServiceServer).RestoreVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DocsService_RestoreVersion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocsServiceServer).RestoreVersion(ctx, req.(*RestoreVersionReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DocsService_DownloadDocument_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DownloadDocumentReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocsServiceServer).DownloadDocument(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DocsService_DownloadDocument_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocsServiceServer).DownloadDocument(ctx, req.(*DownloadDocumentReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DocsService_StarDocument_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StarDocumentReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocsServiceServer).StarDocument(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DocsService_StarDocument_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocsServiceServer).StarDocument(ctx, req.(*StarDocumentReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DocsService_UnstarDocument_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnstarDocumentReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocsServiceServer).UnstarDocument(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DocsService_UnstarDocument_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocsServiceServer).UnstarDocument(ctx, req.(*UnstarDocumentReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DocsService_ListStarredDocuments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListStarredDocumentsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocsServiceServer).ListStarredDocuments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DocsService_ListStarredDocuments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocsServiceServer).ListStarredDocuments(ctx, req.(*ListStarredDocumentsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DocsService_ListRecentDocuments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRecentDocumentsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocsServiceServer).ListRecentDocuments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DocsService_ListRecentDocuments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocsServiceServer).ListRecentDocuments(ctx, req.(*ListRecentDocumentsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DocsService_AddComment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddCommentReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocsServiceServer).AddComment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DocsService_AddComment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocsServiceServer).AddComment(ctx, req.(*AddCommentReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DocsService_ReplyComment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplyCommentReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocsServiceServer).ReplyComment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DocsService_ReplyComment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocsServiceServer).ReplyComment(ctx, req.(*ReplyCommentReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DocsService_ResolveComment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveCommentReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocsServiceServer).ResolveComment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DocsService_ResolveComment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocsServiceServer).ResolveComment(ctx, req.(*ResolveCommentReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DocsService_ReopenComment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReopenCommentReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocsServiceServer).ReopenComment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DocsService_ReopenComment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocsServiceServer).ReopenComment(ctx, req.(*ReopenCommentReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DocsService_ListComments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCommentsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocsServiceServer).ListComments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DocsService_ListComments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocsServiceServer).ListComments(ctx, req.(*ListCommentsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DocsService_CreateSuggestion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSuggestionReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocsServiceServer).CreateSuggestion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DocsService_CreateSuggestion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocsServiceServer).CreateSuggestion(ctx, req.(*CreateSuggestionReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DocsService_ListSuggestions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSuggestionsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocsServiceServer).ListSuggestions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DocsService_ListSuggestions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocsServiceServer).ListSuggestions(ctx, req.(*ListSuggestionsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DocsService_AcceptSuggestion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AcceptSuggestionReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocsServiceServer).AcceptSuggestion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DocsService_AcceptSuggestion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocsServiceServer).AcceptSuggestion(ctx, req.(*AcceptSuggestionReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DocsService_RejectSuggestion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RejectSuggestionReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocsServiceServer).RejectSuggestion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DocsService_RejectSuggestion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocsServiceServer).RejectSuggestion(ctx, req.(*RejectSuggestionReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DocsService_SaveAsTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SaveAsTemplateReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocsServiceServer).SaveAsTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DocsService_SaveAsTemplate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocsServiceServer).SaveAsTemplate(ctx, req.(*SaveAsTemplateReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DocsService_ListTemplates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTemplatesReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocsServiceServer).ListTemplates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DocsService_ListTemplates_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocsServiceServer).ListTemplates(ctx, req.(*ListTemplatesReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DocsService_CopyDocument_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CopyDocumentReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocsServiceServer).CopyDocument(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DocsService_CopyDocument_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocsServiceServer).CopyDocument(ctx, req.(*CopyDocumentReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DocsService_TransferOwnership_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransferOwnershipReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocsServiceServer).TransferOwnership(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DocsService_TransferOwnership_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocsServiceServer).TransferOwnership(ctx, req.(*TransferOwnershipReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DocsService_CreateShareLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateShareLinkReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocsServiceServer).CreateShareLink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DocsService_CreateShareLink_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocsServiceServer).CreateShareLink(ctx, req.(*CreateShareLinkReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DocsService_RevokeShareLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeShareLinkReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocsServiceServer).RevokeShareLink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DocsService_RevokeShareLink_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocsServiceServer).RevokeShareLink(ctx, req.(*RevokeShareLinkReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DocsService_ListShareLinks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListShareLinksReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocsServiceServer).ListShareLinks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DocsService_ListShareLinks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocsServiceServer).ListShareLinks(ctx, req.(*ListShareLinksReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DocsService_ResolveShareLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveShareLinkReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocsServiceServer).ResolveShareLink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DocsService_ResolveShareLink_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocsServiceServer).ResolveShareLink(ctx, req.(*ResolveShareLinkReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DocsService_UnshareDocument_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnshareDocumentReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocsServiceServer).UnshareDocument(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DocsService_UnshareDocument_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocsServiceServer).UnshareDocument(ctx, req.(*UnshareDocumentReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DocsService_ListCollaborators_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCollaboratorsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocsServiceServer).ListCollaborators(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DocsService_ListCollaborators_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocsServiceServer).ListCollaborators(ctx, req.(*ListCollaboratorsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DocsService_RequestAccess_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestAccessReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocsServiceServer).RequestAccess(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DocsService_RequestAccess_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocsServiceServer).RequestAccess(ctx, req.(*RequestAccessReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DocsService_ListAccessRequests_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAccessRequestsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocsServiceServer).ListAccessRequests(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DocsService_ListAccessRequests_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocsServiceServer).ListAccessRequests(ctx, req.(*ListAccessRequestsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DocsService_ApproveAccessRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApproveAccessRequestReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocsServiceServer).ApproveAccessRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DocsService_ApproveAccessRequest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocsServiceServer).ApproveAccessRequest(ctx, req.(*ApproveAccessRequestReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DocsService_DenyAccessRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DenyAccessRequestReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocsServiceServer).DenyAccessRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DocsService_DenyAccessRequest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocsServiceServer).DenyAccessRequest(ctx, req.(*DenyAccessRequestReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DocsService_ListAuditLog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAuditLogReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocsServiceServer).ListAuditLog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DocsService_ListAuditLog_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocsServiceServer).ListAuditLog(ctx, req.(*ListAuditLogReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DocsService_WatchDocument_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchDocumentReq)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DocsServiceServer).WatchDocument(m, &docsServiceWatchDocumentServer{ServerStream: stream})
+}
+
+type DocsService_WatchDocumentServer interface {
+	Send(*DocumentEvent) error
+	grpc.ServerStream
+}
+
+type docsServiceWatchDocumentServer struct {
+	grpc.ServerStream
+}
+
+func (x *docsServiceWatchDocumentServer) Send(m *DocumentEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _DocsService_CreateWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateWebhookReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocsServiceServer).CreateWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DocsService_CreateWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocsServiceServer).CreateWebhook(ctx, req.(*CreateWebhookReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DocsService_DeleteWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteWebhookReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocsServiceServer).DeleteWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DocsService_DeleteWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocsServiceServer).DeleteWebhook(ctx, req.(*DeleteWebhookReq))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (UnimplementedDocsServiceServer) CreateDocument(context.Context, *CreateDocumentReq) (*CreateDocumentRes, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CreateDocument not implemented")
+func _DocsService_ListWebhooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhooksReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocsServiceServer).ListWebhooks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DocsService_ListWebhooks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocsServiceServer).ListWebhooks(ctx, req.(*ListWebhooksReq))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedDocsServiceServer) GetDocument(context.Context, *GetDocumentReq) (*GetDocumentRes, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetDocument not implemented")
+
+func _DocsService_ListWebhookDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhookDeliveriesReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocsServiceServer).ListWebhookDeliveries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DocsService_ListWebhookDeliveries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocsServiceServer).ListWebhookDeliveries(ctx, req.(*ListWebhookDeliveriesReq))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedDocsServiceServer) GetAllDocuments(context.Context, *GetAllDocumentsReq) (*GetAllDocumentsRes, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetAllDocuments not implemented")
+
+func _DocsService_RetryWebhookDelivery_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RetryWebhookDeliveryReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocsServiceServer).RetryWebhookDelivery(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DocsService_RetryWebhookDelivery_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocsServiceServer).RetryWebhookDelivery(ctx, req.(*RetryWebhookDeliveryReq))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedDocsServiceServer) UpdateDocument(context.Context, *UpdateDocumentReq) (*UpdateDocumentRes, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method UpdateDocument not implemented")
+
+func _DocsService_ImportDocument_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(DocsServiceServer).ImportDocument(&docsServiceImportDocumentServer{ServerStream: stream})
 }
-func (UnimplementedDocsServiceServer) DeleteDocument(context.Context, *DeleteDocumentReq) (*DeleteDocumentRes, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DeleteDocument not implemented")
+
+type DocsService_ImportDocumentServer interface {
+	SendAndClose(*ImportDocumentRes) error
+	Recv() (*ImportDocumentReq, error)
+	grpc.ServerStream
 }
-func (UnimplementedDocsServiceServer) ShareDocument(context.Context, *ShareDocumentReq) (*ShareDocumentRes, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ShareDocument not implemented")
+
+type docsServiceImportDocumentServer struct {
+	grpc.ServerStream
 }
-func (UnimplementedDocsServiceServer) SearchDocument(context.Context, *SearchDocumentReq) (*SearchDocumentRes, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SearchDocument not implemented")
+
+func (x *docsServiceImportDocumentServer) SendAndClose(m *ImportDocumentRes) error {
+	return x.ServerStream.SendMsg(m)
 }
-func (UnimplementedDocsServiceServer) GetAllVersions(context.Context, *GetAllVersionsReq) (*GetAllVersionsRes, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetAllVersions not implemented")
+
+func (x *docsServiceImportDocumentServer) Recv() (*ImportDocumentReq, error) {
+	m := new(ImportDocumentReq)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
 }
-func (UnimplementedDocsServiceServer) RestoreVersion(context.Context, *RestoreVersionReq) (*RestoreVersionRes, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method RestoreVersion not implemented")
+
+func _DocsService_UploadAttachment_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(DocsServiceServer).UploadAttachment(&docsServiceUploadAttachmentServer{ServerStream: stream})
 }
-func (UnimplementedDocsServiceServer) DownloadDocument(context.Context, *DownloadDocumentReq) (*DownloadDocumentRes, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DownloadDocument not implemented")
+
+type DocsService_UploadAttachmentServer interface {
+	SendAndClose(*UploadAttachmentRes) error
+	Recv() (*UploadAttachmentReq, error)
+	grpc.ServerStream
 }
-func (UnimplementedDocsServiceServer) mustEmbedUnimplementedDocsServiceServer() {}
 
-// UnsafeDocsServiceServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to DocsServiceServer will
-// result in compilation errors.
-type UnsafeDocsServiceServer interface {
-	mustEmbedUnimplementedDocsServiceServer()
+type docsServiceUploadAttachmentServer struct {
+	grpc.ServerStream
 }
 
-func RegisterDocsServiceServer(s grpc.ServiceRegistrar, srv DocsServiceServer) {
-	s.RegisterService(&DocsService_ServiceDesc, srv)
+func (x *docsServiceUploadAttachmentServer) SendAndClose(m *UploadAttachmentRes) error {
+	return x.ServerStream.SendMsg(m)
 }
 
-func _DocsService_CreateDocument_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateDocumentReq)
+func (x *docsServiceUploadAttachmentServer) Recv() (*UploadAttachmentReq, error) {
+	m := new(UploadAttachmentReq)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _DocsService_DownloadAttachment_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DownloadAttachmentReq)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DocsServiceServer).DownloadAttachment(m, &docsServiceDownloadAttachmentServer{ServerStream: stream})
+}
+
+type DocsService_DownloadAttachmentServer interface {
+	Send(*AttachmentChunk) error
+	grpc.ServerStream
+}
+
+type docsServiceDownloadAttachmentServer struct {
+	grpc.ServerStream
+}
+
+func (x *docsServiceDownloadAttachmentServer) Send(m *AttachmentChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _DocsService_ListAttachments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAttachmentsReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(DocsServiceServer).CreateDocument(ctx, in)
+		return srv.(DocsServiceServer).ListAttachments(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: DocsService_CreateDocument_FullMethodName,
+		FullMethod: DocsService_ListAttachments_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(DocsServiceServer).CreateDocument(ctx, req.(*CreateDocumentReq))
+		return srv.(DocsServiceServer).ListAttachments(ctx, req.(*ListAttachmentsReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _DocsService_GetDocument_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetDocumentReq)
+func _DocsService_PurgeDocument_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeDocumentReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(DocsServiceServer).GetDocument(ctx, in)
+		return srv.(DocsServiceServer).PurgeDocument(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: DocsService_GetDocument_FullMethodName,
+		FullMethod: DocsService_PurgeDocument_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(DocsServiceServer).GetDocument(ctx, req.(*GetDocumentReq))
+		return srv.(DocsServiceServer).PurgeDocument(ctx, req.(*PurgeDocumentReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _DocsService_GetAllDocuments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetAllDocumentsReq)
+func _DocsService_StreamDocument_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamDocumentReq)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DocsServiceServer).StreamDocument(m, &docsServiceStreamDocumentServer{ServerStream: stream})
+}
+
+type DocsService_StreamDocumentServer interface {
+	Send(*DocumentChunk) error
+	grpc.ServerStream
+}
+
+type docsServiceStreamDocumentServer struct {
+	grpc.ServerStream
+}
+
+func (x *docsServiceStreamDocumentServer) Send(m *DocumentChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _DocsService_BulkDocuments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BulkDocumentsReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(DocsServiceServer).GetAllDocuments(ctx, in)
+		return srv.(DocsServiceServer).BulkDocuments(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: DocsService_GetAllDocuments_FullMethodName,
+		FullMethod: DocsService_BulkDocuments_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(DocsServiceServer).GetAllDocuments(ctx, req.(*GetAllDocumentsReq))
+		return srv.(DocsServiceServer).BulkDocuments(ctx, req.(*BulkDocumentsReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _DocsService_UpdateDocument_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UpdateDocumentReq)
+func _DocsService_CreateWorkspace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateWorkspaceReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(DocsServiceServer).UpdateDocument(ctx, in)
+		return srv.(DocsServiceServer).CreateWorkspace(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: DocsService_UpdateDocument_FullMethodName,
+		FullMethod: DocsService_CreateWorkspace_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(DocsServiceServer).UpdateDocument(ctx, req.(*UpdateDocumentReq))
+		return srv.(DocsServiceServer).CreateWorkspace(ctx, req.(*CreateWorkspaceReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _DocsService_DeleteDocument_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DeleteDocumentReq)
+func _DocsService_AddWorkspaceMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddWorkspaceMemberReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(DocsServiceServer).DeleteDocument(ctx, in)
+		return srv.(DocsServiceServer).AddWorkspaceMember(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: DocsService_DeleteDocument_FullMethodName,
+		FullMethod: DocsService_AddWorkspaceMember_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(DocsServiceServer).DeleteDocument(ctx, req.(*DeleteDocumentReq))
+		return srv.(DocsServiceServer).AddWorkspaceMember(ctx, req.(*AddWorkspaceMemberReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _DocsService_ShareDocument_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ShareDocumentReq)
+func _DocsService_RemoveWorkspaceMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveWorkspaceMemberReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(DocsServiceServer).ShareDocument(ctx, in)
+		return srv.(DocsServiceServer).RemoveWorkspaceMember(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: DocsService_ShareDocument_FullMethodName,
+		FullMethod: DocsService_RemoveWorkspaceMember_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(DocsServiceServer).ShareDocument(ctx, req.(*ShareDocumentReq))
+		return srv.(DocsServiceServer).RemoveWorkspaceMember(ctx, req.(*RemoveWorkspaceMemberReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _DocsService_SearchDocument_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SearchDocumentReq)
+func _DocsService_ListWorkspaces_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWorkspacesReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(DocsServiceServer).SearchDocument(ctx, in)
+		return srv.(DocsServiceServer).ListWorkspaces(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: DocsService_SearchDocument_FullMethodName,
+		FullMethod: DocsService_ListWorkspaces_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(DocsServiceServer).SearchDocument(ctx, req.(*SearchDocumentReq))
+		return srv.(DocsServiceServer).ListWorkspaces(ctx, req.(*ListWorkspacesReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _DocsService_GetAllVersions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetAllVersionsReq)
+func _DocsService_ListWorkspaceDocuments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWorkspaceDocumentsReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(DocsServiceServer).GetAllVersions(ctx, in)
+		return srv.(DocsServiceServer).ListWorkspaceDocuments(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: DocsService_GetAllVersions_FullMethodName,
+		FullMethod: DocsService_ListWorkspaceDocuments_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(DocsServiceServer).GetAllVersions(ctx, req.(*GetAllVersionsReq))
+		return srv.(DocsServiceServer).ListWorkspaceDocuments(ctx, req.(*ListWorkspaceDocumentsReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _DocsService_RestoreVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RestoreVersionReq)
+func _DocsService_GetUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUsageReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(DocsServiceServer).RestoreVersion(ctx, in)
+		return srv.(DocsServiceServer).GetUsage(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: DocsService_RestoreVersion_FullMethodName,
+		FullMethod: DocsService_GetUsage_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(DocsServiceServer).RestoreVersion(ctx, req.(*RestoreVersionReq))
+		return srv.(DocsServiceServer).GetUsage(ctx, req.(*GetUsageReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _DocsService_DownloadDocument_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DownloadDocumentReq)
+func _DocsService_SaveVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SaveVersionReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(DocsServiceServer).DownloadDocument(ctx, in)
+		return srv.(DocsServiceServer).SaveVersion(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: DocsService_DownloadDocument_FullMethodName,
+		FullMethod: DocsService_SaveVersion_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(DocsServiceServer).DownloadDocument(ctx, req.(*DownloadDocumentReq))
+		return srv.(DocsServiceServer).SaveVersion(ctx, req.(*SaveVersionReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -446,7 +2222,205 @@ var DocsService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DownloadDocument",
 			Handler:    _DocsService_DownloadDocument_Handler,
 		},
+		{
+			MethodName: "StarDocument",
+			Handler:    _DocsService_StarDocument_Handler,
+		},
+		{
+			MethodName: "UnstarDocument",
+			Handler:    _DocsService_UnstarDocument_Handler,
+		},
+		{
+			MethodName: "ListStarredDocuments",
+			Handler:    _DocsService_ListStarredDocuments_Handler,
+		},
+		{
+			MethodName: "ListRecentDocuments",
+			Handler:    _DocsService_ListRecentDocuments_Handler,
+		},
+		{
+			MethodName: "AddComment",
+			Handler:    _DocsService_AddComment_Handler,
+		},
+		{
+			MethodName: "ReplyComment",
+			Handler:    _DocsService_ReplyComment_Handler,
+		},
+		{
+			MethodName: "ResolveComment",
+			Handler:    _DocsService_ResolveComment_Handler,
+		},
+		{
+			MethodName: "ReopenComment",
+			Handler:    _DocsService_ReopenComment_Handler,
+		},
+		{
+			MethodName: "ListComments",
+			Handler:    _DocsService_ListComments_Handler,
+		},
+		{
+			MethodName: "CreateSuggestion",
+			Handler:    _DocsService_CreateSuggestion_Handler,
+		},
+		{
+			MethodName: "ListSuggestions",
+			Handler:    _DocsService_ListSuggestions_Handler,
+		},
+		{
+			MethodName: "AcceptSuggestion",
+			Handler:    _DocsService_AcceptSuggestion_Handler,
+		},
+		{
+			MethodName: "RejectSuggestion",
+			Handler:    _DocsService_RejectSuggestion_Handler,
+		},
+		{
+			MethodName: "SaveAsTemplate",
+			Handler:    _DocsService_SaveAsTemplate_Handler,
+		},
+		{
+			MethodName: "ListTemplates",
+			Handler:    _DocsService_ListTemplates_Handler,
+		},
+		{
+			MethodName: "CopyDocument",
+			Handler:    _DocsService_CopyDocument_Handler,
+		},
+		{
+			MethodName: "TransferOwnership",
+			Handler:    _DocsService_TransferOwnership_Handler,
+		},
+		{
+			MethodName: "CreateShareLink",
+			Handler:    _DocsService_CreateShareLink_Handler,
+		},
+		{
+			MethodName: "RevokeShareLink",
+			Handler:    _DocsService_RevokeShareLink_Handler,
+		},
+		{
+			MethodName: "ListShareLinks",
+			Handler:    _DocsService_ListShareLinks_Handler,
+		},
+		{
+			MethodName: "ResolveShareLink",
+			Handler:    _DocsService_ResolveShareLink_Handler,
+		},
+		{
+			MethodName: "UnshareDocument",
+			Handler:    _DocsService_UnshareDocument_Handler,
+		},
+		{
+			MethodName: "ListCollaborators",
+			Handler:    _DocsService_ListCollaborators_Handler,
+		},
+		{
+			MethodName: "RequestAccess",
+			Handler:    _DocsService_RequestAccess_Handler,
+		},
+		{
+			MethodName: "ListAccessRequests",
+			Handler:    _DocsService_ListAccessRequests_Handler,
+		},
+		{
+			MethodName: "ApproveAccessRequest",
+			Handler:    _DocsService_ApproveAccessRequest_Handler,
+		},
+		{
+			MethodName: "DenyAccessRequest",
+			Handler:    _DocsService_DenyAccessRequest_Handler,
+		},
+		{
+			MethodName: "ListAuditLog",
+			Handler:    _DocsService_ListAuditLog_Handler,
+		},
+		{
+			MethodName: "CreateWebhook",
+			Handler:    _DocsService_CreateWebhook_Handler,
+		},
+		{
+			MethodName: "DeleteWebhook",
+			Handler:    _DocsService_DeleteWebhook_Handler,
+		},
+		{
+			MethodName: "ListWebhooks",
+			Handler:    _DocsService_ListWebhooks_Handler,
+		},
+		{
+			MethodName: "ListWebhookDeliveries",
+			Handler:    _DocsService_ListWebhookDeliveries_Handler,
+		},
+		{
+			MethodName: "RetryWebhookDelivery",
+			Handler:    _DocsService_RetryWebhookDelivery_Handler,
+		},
+		{
+			MethodName: "ListAttachments",
+			Handler:    _DocsService_ListAttachments_Handler,
+		},
+		{
+			MethodName: "PurgeDocument",
+			Handler:    _DocsService_PurgeDocument_Handler,
+		},
+		{
+			MethodName: "BulkDocuments",
+			Handler:    _DocsService_BulkDocuments_Handler,
+		},
+		{
+			MethodName: "CreateWorkspace",
+			Handler:    _DocsService_CreateWorkspace_Handler,
+		},
+		{
+			MethodName: "AddWorkspaceMember",
+			Handler:    _DocsService_AddWorkspaceMember_Handler,
+		},
+		{
+			MethodName: "RemoveWorkspaceMember",
+			Handler:    _DocsService_RemoveWorkspaceMember_Handler,
+		},
+		{
+			MethodName: "ListWorkspaces",
+			Handler:    _DocsService_ListWorkspaces_Handler,
+		},
+		{
+			MethodName: "ListWorkspaceDocuments",
+			Handler:    _DocsService_ListWorkspaceDocuments_Handler,
+		},
+		{
+			MethodName: "GetUsage",
+			Handler:    _DocsService_GetUsage_Handler,
+		},
+		{
+			MethodName: "SaveVersion",
+			Handler:    _DocsService_SaveVersion_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchDocument",
+			Handler:       _DocsService_WatchDocument_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ImportDocument",
+			Handler:       _DocsService_ImportDocument_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "UploadAttachment",
+			Handler:       _DocsService_UploadAttachment_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "DownloadAttachment",
+			Handler:       _DocsService_DownloadAttachment_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamDocument",
+			Handler:       _DocsService_StreamDocument_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "Google_Docs_proto/doccs/doccs.proto",
 }
@@ -1556,7 +1556,7 @@ var file_user_proto_rawDesc = []byte{
 	0x49, 0x64, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02,
 	0x69, 0x64, 0x22, 0x27, 0x0a, 0x0b, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72,
 	0x72, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x32, 0xf4, 0x05, 0x0a, 0x0b,
+	0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x32, 0xa4, 0x06, 0x0a, 0x0b,
 	0x55, 0x73, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x30, 0x0a, 0x08, 0x52,
 	0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x12, 0x11, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x52,
 	0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x52, 0x65, 0x71, 0x1a, 0x11, 0x2e, 0x75, 0x73, 0x65,
@@ -1572,6 +1572,9 @@ var file_user_proto_rawDesc = []byte{
 	0x47, 0x65, 0x74, 0x55, 0x53, 0x65, 0x72, 0x42, 0x79, 0x45, 0x6d, 0x61, 0x69, 0x6c, 0x12, 0x17,
 	0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x47, 0x65, 0x74, 0x55, 0x53, 0x65, 0x72, 0x42, 0x79, 0x45,
 	0x6d, 0x61, 0x69, 0x6c, 0x52, 0x65, 0x71, 0x1a, 0x15, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x47,
+	0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2e,
+	0x0a, 0x07, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x12, 0x0c, 0x2e, 0x75, 0x73, 0x65, 0x72,
+	0x2e, 0x55, 0x73, 0x65, 0x72, 0x49, 0x64, 0x1a, 0x15, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x47,
 	0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x42,
 	0x0a, 0x0e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64,
 	0x12, 0x17, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x61,
@@ -1657,28 +1660,30 @@ var file_user_proto_depIdxs = []int32{
 	11, // 4: user.UserService.Login:input_type -> user.LoginReq
 	7,  // 5: user.UserService.ConfirmationRegister:input_type -> user.ConfirmationRegisterReq
 	15, // 6: user.UserService.GetUSerByEmail:input_type -> user.GetUSerByEmailReq
-	17, // 7: user.UserService.UpdatePassword:input_type -> user.UpdatePasswordReq
-	19, // 8: user.UserService.ResetPassword:input_type -> user.ResetPasswordReq
-	13, // 9: user.UserService.ConfirmationPassword:input_type -> user.ConfirmationReq
-	22, // 10: user.UserService.UpdateUser:input_type -> user.UpdateUserRequest
-	24, // 11: user.UserService.DeleteUser:input_type -> user.UserId
-	1,  // 12: user.UserService.UpdateRole:input_type -> user.UpdateRoleReq
-	4,  // 13: user.UserService.ProfileImage:input_type -> user.ImageReq
-	3,  // 14: user.UserService.StoreRefreshToken:input_type -> user.StoreRefreshTokenReq
-	10, // 15: user.UserService.Register:output_type -> user.RegisterRes
-	12, // 16: user.UserService.Login:output_type -> user.LoginRes
-	8,  // 17: user.UserService.ConfirmationRegister:output_type -> user.ConfirmationRegisterRes
-	16, // 18: user.UserService.GetUSerByEmail:output_type -> user.GetUserResponse
-	18, // 19: user.UserService.UpdatePassword:output_type -> user.UpdatePasswordRes
-	20, // 20: user.UserService.ResetPassword:output_type -> user.ResetPasswordRes
-	21, // 21: user.UserService.ConfirmationPassword:output_type -> user.ConfirmationResponse
-	23, // 22: user.UserService.UpdateUser:output_type -> user.UpdateUserRespose
-	25, // 23: user.UserService.DeleteUser:output_type -> user.DeleteUserr
-	0,  // 24: user.UserService.UpdateRole:output_type -> user.UpdateRoleRes
-	5,  // 25: user.UserService.ProfileImage:output_type -> user.ImageRes
-	2,  // 26: user.UserService.StoreRefreshToken:output_type -> user.StoreRefReshTokenRes
-	15, // [15:27] is the sub-list for method output_type
-	3,  // [3:15] is the sub-list for method input_type
+	24, // 7: user.UserService.GetUser:input_type -> user.UserId
+	17, // 8: user.UserService.UpdatePassword:input_type -> user.UpdatePasswordReq
+	19, // 9: user.UserService.ResetPassword:input_type -> user.ResetPasswordReq
+	13, // 10: user.UserService.ConfirmationPassword:input_type -> user.ConfirmationReq
+	22, // 11: user.UserService.UpdateUser:input_type -> user.UpdateUserRequest
+	24, // 12: user.UserService.DeleteUser:input_type -> user.UserId
+	1,  // 13: user.UserService.UpdateRole:input_type -> user.UpdateRoleReq
+	4,  // 14: user.UserService.ProfileImage:input_type -> user.ImageReq
+	3,  // 15: user.UserService.StoreRefreshToken:input_type -> user.StoreRefreshTokenReq
+	10, // 16: user.UserService.Register:output_type -> user.RegisterRes
+	12, // 17: user.UserService.Login:output_type -> user.LoginRes
+	8,  // 18: user.UserService.ConfirmationRegister:output_type -> user.ConfirmationRegisterRes
+	16, // 19: user.UserService.GetUSerByEmail:output_type -> user.GetUserResponse
+	16, // 20: user.UserService.GetUser:output_type -> user.GetUserResponse
+	18, // 21: user.UserService.UpdatePassword:output_type -> user.UpdatePasswordRes
+	20, // 22: user.UserService.ResetPassword:output_type -> user.ResetPasswordRes
+	21, // 23: user.UserService.ConfirmationPassword:output_type -> user.ConfirmationResponse
+	23, // 24: user.UserService.UpdateUser:output_type -> user.UpdateUserRespose
+	25, // 25: user.UserService.DeleteUser:output_type -> user.DeleteUserr
+	0,  // 26: user.UserService.UpdateRole:output_type -> user.UpdateRoleRes
+	5,  // 27: user.UserService.ProfileImage:output_type -> user.ImageRes
+	2,  // 28: user.UserService.StoreRefreshToken:output_type -> user.StoreRefReshTokenRes
+	16, // [16:29] is the sub-list for method output_type
+	3,  // [3:16] is the sub-list for method input_type
 	3,  // [3:3] is the sub-list for extension type_name
 	3,  // [3:3] is the sub-list for extension extendee
 	0,  // [0:3] is the sub-list for field type_name
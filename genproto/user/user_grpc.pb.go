@@ -23,6 +23,7 @@ const (
 	UserService_Login_FullMethodName                = "/user.UserService/Login"
 	UserService_ConfirmationRegister_FullMethodName = "/user.UserService/ConfirmationRegister"
 	UserService_GetUSerByEmail_FullMethodName       = "/user.UserService/GetUSerByEmail"
+	UserService_GetUser_FullMethodName              = "/user.UserService/GetUser"
 	UserService_UpdatePassword_FullMethodName       = "/user.UserService/UpdatePassword"
 	UserService_ResetPassword_FullMethodName        = "/user.UserService/ResetPassword"
 	UserService_ConfirmationPassword_FullMethodName = "/user.UserService/ConfirmationPassword"
@@ -41,6 +42,7 @@ type UserServiceClient interface {
 	Login(ctx context.Context, in *LoginReq, opts ...grpc.CallOption) (*LoginRes, error)
 	ConfirmationRegister(ctx context.Context, in *ConfirmationRegisterReq, opts ...grpc.CallOption) (*ConfirmationRegisterRes, error)
 	GetUSerByEmail(ctx context.Context, in *GetUSerByEmailReq, opts ...grpc.CallOption) (*GetUserResponse, error)
+	GetUser(ctx context.Context, in *UserId, opts ...grpc.CallOption) (*GetUserResponse, error)
 	UpdatePassword(ctx context.Context, in *UpdatePasswordReq, opts ...grpc.CallOption) (*UpdatePasswordRes, error)
 	ResetPassword(ctx context.Context, in *ResetPasswordReq, opts ...grpc.CallOption) (*ResetPasswordRes, error)
 	ConfirmationPassword(ctx context.Context, in *ConfirmationReq, opts ...grpc.CallOption) (*ConfirmationResponse, error)
@@ -99,6 +101,16 @@ func (c *userServiceClient) GetUSerByEmail(ctx context.Context, in *GetUSerByEma
 	return out, nil
 }
 
+func (c *userServiceClient) GetUser(ctx context.Context, in *UserId, opts ...grpc.CallOption) (*GetUserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetUserResponse)
+	err := c.cc.Invoke(ctx, UserService_GetUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *userServiceClient) UpdatePassword(ctx context.Context, in *UpdatePasswordReq, opts ...grpc.CallOption) (*UpdatePasswordRes, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(UpdatePasswordRes)
@@ -187,6 +199,7 @@ type UserServiceServer interface {
 	Login(context.Context, *LoginReq) (*LoginRes, error)
 	ConfirmationRegister(context.Context, *ConfirmationRegisterReq) (*ConfirmationRegisterRes, error)
 	GetUSerByEmail(context.Context, *GetUSerByEmailReq) (*GetUserResponse, error)
+	GetUser(context.Context, *UserId) (*GetUserResponse, error)
 	UpdatePassword(context.Context, *UpdatePasswordReq) (*UpdatePasswordRes, error)
 	ResetPassword(context.Context, *ResetPasswordReq) (*ResetPasswordRes, error)
 	ConfirmationPassword(context.Context, *ConfirmationReq) (*ConfirmationResponse, error)
@@ -214,6 +227,9 @@ func (UnimplementedUserServiceServer) ConfirmationRegister(context.Context, *Con
 func (UnimplementedUserServiceServer) GetUSerByEmail(context.Context, *GetUSerByEmailReq) (*GetUserResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetUSerByEmail not implemented")
 }
+func (UnimplementedUserServiceServer) GetUser(context.Context, *UserId) (*GetUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUser not implemented")
+}
 func (UnimplementedUserServiceServer) UpdatePassword(context.Context, *UpdatePasswordReq) (*UpdatePasswordRes, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdatePassword not implemented")
 }
@@ -323,6 +339,24 @@ func _UserService_GetUSerByEmail_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _UserService_GetUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserId)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_GetUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetUser(ctx, req.(*UserId))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _UserService_UpdatePassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(UpdatePasswordReq)
 	if err := dec(in); err != nil {
@@ -490,6 +524,10 @@ var UserService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetUSerByEmail",
 			Handler:    _UserService_GetUSerByEmail_Handler,
 		},
+		{
+			MethodName: "GetUser",
+			Handler:    _UserService_GetUser_Handler,
+		},
 		{
 			MethodName: "UpdatePassword",
 			Handler:    _UserService_UpdatePassword_Handler,
@@ -0,0 +1,221 @@
+// Package apperr holds the domain errors of the service. Every error knows
+// its gRPC code and a stable reason, and turns into a status carrying
+// ErrorInfo, BadRequest field violations and a message in the caller's
+// language.
+package apperr
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Domain is the ErrorInfo domain of every error of this service.
+const Domain = "doccs"
+
+// Reasons are stable identifiers clients may switch on; the messages are not.
+const (
+	ReasonNotFound      = "NOT_FOUND"
+	ReasonMissingField  = "MISSING_FIELD"
+	ReasonInvalidField  = "INVALID_FIELD"
+	ReasonAlreadyExists = "ALREADY_EXISTS"
+	ReasonNoAccess      = "NO_ACCESS"
+	ReasonNotAllowed    = "NOT_ALLOWED"
+	ReasonConflict      = "CONFLICT"
+	ReasonQuotaExceeded = "QUOTA_EXCEEDED"
+	ReasonRateLimited   = "RATE_LIMITED"
+	ReasonAborted       = "ABORTED"
+	ReasonTooLarge      = "TOO_LARGE"
+)
+
+// Error is a domain error. Message is the detailed English text meant for
+// logs and developers; the localized message built from Reason and Metadata
+// is meant for end users.
+type Error struct {
+	Code     codes.Code
+	Reason   string
+	Message  string
+	Metadata map[string]string
+	// Field names the request field at fault, if any, as in the .proto file.
+	Field string
+	cause error
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Wrap records cause as the error underneath e, for errors.Is and As.
+func (e *Error) Wrap(cause error) *Error {
+	e.cause = cause
+	return e
+}
+
+// GRPCStatus lets grpc and status.Code see the code of e, also when it is
+// wrapped. The message is left unlocalized.
+func (e *Error) GRPCStatus() *status.Status {
+	return e.Status("")
+}
+
+// Status converts e into a status with its details. A locale adds a
+// LocalizedMessage in that language; unknown locales fall back to English.
+func (e *Error) Status(locale string) *status.Status {
+	st := status.New(e.Code, e.Message)
+
+	if s, err := st.WithDetails(&errdetails.ErrorInfo{Reason: e.Reason, Domain: Domain, Metadata: e.Metadata}); err == nil {
+		st = s
+	}
+	if e.Field != "" {
+		if s, err := st.WithDetails(&errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: e.Field, Description: e.Message}},
+		}); err == nil {
+			st = s
+		}
+	}
+	if locale != "" {
+		lang, msg := localize(locale, e)
+		if s, err := st.WithDetails(&errdetails.LocalizedMessage{Locale: lang, Message: msg}); err == nil {
+			st = s
+		}
+	}
+	return st
+}
+
+// NotFound reports a missing resource, e.g. NotFound("document", title).
+func NotFound(resource, id string) *Error {
+	return &Error{
+		Code:     codes.NotFound,
+		Reason:   ReasonNotFound,
+		Message:  fmt.Sprintf("%s not found", named(resource, id)),
+		Metadata: map[string]string{"resource": resource, "id": id},
+	}
+}
+
+// Missing reports a required request field that was left empty.
+func Missing(field string) *Error {
+	return &Error{
+		Code:     codes.InvalidArgument,
+		Reason:   ReasonMissingField,
+		Message:  fmt.Sprintf("%s is not set", field),
+		Metadata: map[string]string{"field": field},
+		Field:    field,
+	}
+}
+
+// Invalid reports a request field with a value that is not accepted.
+func Invalid(field, format string, args ...interface{}) *Error {
+	return &Error{
+		Code:     codes.InvalidArgument,
+		Reason:   ReasonInvalidField,
+		Message:  fmt.Sprintf(format, args...),
+		Metadata: map[string]string{"field": field},
+		Field:    field,
+	}
+}
+
+// AlreadyExists reports a resource that cannot be created twice.
+func AlreadyExists(resource, id string) *Error {
+	return &Error{
+		Code:     codes.AlreadyExists,
+		Reason:   ReasonAlreadyExists,
+		Message:  fmt.Sprintf("%s already exists", named(resource, id)),
+		Metadata: map[string]string{"resource": resource, "id": id},
+	}
+}
+
+// NoAccess reports a user who may not see the resource at all.
+func NoAccess(userId, resource, id string) *Error {
+	return &Error{
+		Code:     codes.PermissionDenied,
+		Reason:   ReasonNoAccess,
+		Message:  fmt.Sprintf("userId '%s' has no access to %s", userId, named(resource, id)),
+		Metadata: map[string]string{"userId": userId, "resource": resource, "id": id},
+	}
+}
+
+// NotAllowed reports a user whose role does not allow the action.
+func NotAllowed(resource, id, format string, args ...interface{}) *Error {
+	return &Error{
+		Code:     codes.PermissionDenied,
+		Reason:   ReasonNotAllowed,
+		Message:  fmt.Sprintf(format, args...),
+		Metadata: map[string]string{"resource": resource, "id": id},
+	}
+}
+
+// Conflict reports an action the resource is in the wrong state for.
+func Conflict(resource, id, format string, args ...interface{}) *Error {
+	return &Error{
+		Code:     codes.FailedPrecondition,
+		Reason:   ReasonConflict,
+		Message:  fmt.Sprintf(format, args...),
+		Metadata: map[string]string{"resource": resource, "id": id},
+	}
+}
+
+// QuotaExceeded reports a write that does not fit into a storage quota.
+func QuotaExceeded(format string, args ...interface{}) *Error {
+	return &Error{
+		Code:    codes.ResourceExhausted,
+		Reason:  ReasonQuotaExceeded,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// RateLimited reports a caller who made too many calls of method and may
+// retry after retryAfter seconds.
+func RateLimited(method string, retryAfter int) *Error {
+	return &Error{
+		Code:     codes.ResourceExhausted,
+		Reason:   ReasonRateLimited,
+		Message:  fmt.Sprintf("too many %s calls, retry in %d seconds", method, retryAfter),
+		Metadata: map[string]string{"method": method, "retryAfter": strconv.Itoa(retryAfter)},
+	}
+}
+
+// Aborted reports a call that ran into another one on the same resource and
+// may be retried as is.
+func Aborted(resource, id, format string, args ...interface{}) *Error {
+	return &Error{
+		Code:     codes.Aborted,
+		Reason:   ReasonAborted,
+		Message:  fmt.Sprintf(format, args...),
+		Metadata: map[string]string{"resource": resource, "id": id},
+	}
+}
+
+// TooLarge reports a resource that does not fit into the response.
+func TooLarge(resource, id, format string, args ...interface{}) *Error {
+	return &Error{
+		Code:     codes.ResourceExhausted,
+		Reason:   ReasonTooLarge,
+		Message:  fmt.Sprintf(format, args...),
+		Metadata: map[string]string{"resource": resource, "id": id},
+	}
+}
+
+// named quotes id after resource. Secrets such as share link tokens are
+// passed as an empty id and left out.
+func named(resource, id string) string {
+	if id == "" {
+		return resource
+	}
+	return fmt.Sprintf("%s '%s'", resource, id)
+}
+
+// ReasonOf returns the reason of the domain error in err's chain, or "".
+func ReasonOf(err error) string {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Reason
+	}
+	return ""
+}
@@ -0,0 +1,80 @@
+package apperr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestStatus tests the code and the details of a converted error.
+func TestStatus(t *testing.T) {
+	st := Invalid("offset", "offset %d is outside the document", 7).Status("ru-RU,en;q=0.5")
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	assert.Equal(t, "offset 7 is outside the document", st.Message())
+
+	var info *errdetails.ErrorInfo
+	var bad *errdetails.BadRequest
+	var local *errdetails.LocalizedMessage
+	for _, d := range st.Details() {
+		switch d := d.(type) {
+		case *errdetails.ErrorInfo:
+			info = d
+		case *errdetails.BadRequest:
+			bad = d
+		case *errdetails.LocalizedMessage:
+			local = d
+		}
+	}
+	if assert.NotNil(t, info) {
+		assert.Equal(t, ReasonInvalidField, info.Reason)
+		assert.Equal(t, Domain, info.Domain)
+	}
+	if assert.NotNil(t, bad) && assert.Len(t, bad.FieldViolations, 1) {
+		assert.Equal(t, "offset", bad.FieldViolations[0].Field)
+	}
+	if assert.NotNil(t, local) {
+		assert.Equal(t, "ru", local.Locale)
+		assert.Equal(t, "Поле 'offset' имеет недопустимое значение", local.Message)
+	}
+
+	assert.Len(t, NotFound("document", "a").Status("").Details(), 1)
+}
+
+// TestWrapped tests that the code survives wrapping and Wrap keeps the cause.
+func TestWrapped(t *testing.T) {
+	cause := errors.New("quota exceeded")
+	err := fmt.Errorf("saving: %w", QuotaExceeded("%v: 3 of 3 documents", cause).Wrap(cause))
+
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.True(t, errors.Is(err, cause))
+	assert.Equal(t, ReasonQuotaExceeded, ReasonOf(err))
+	assert.Equal(t, "", ReasonOf(cause))
+}
+
+// TestLocalize tests the message catalog and the language negotiation.
+func TestLocalize(t *testing.T) {
+	tests := []struct {
+		err  *Error
+		lang string
+		want string
+	}{
+		{NotFound("document", "Plan"), "uz", "Hujjat 'Plan' topilmadi"},
+		{NotFound("document", "Plan"), "fr, uz-Latn;q=0.7", "Hujjat 'Plan' topilmadi"},
+		{NotFound("share link", ""), "en", "Share link was not found"},
+		{NoAccess("u1", "workspace", "w1"), "ru", "У вас нет доступа к ресурсу рабочее пространство 'w1'"},
+		{Missing("user_id"), "", "Field 'user_id' is required"},
+		{Missing("user_id"), "uz", "'user_id' maydoni to'ldirilishi shart"},
+		{RateLimited("UpdateDocument", 3), "en", "Too many requests, try again in 3 seconds"},
+		{Aborted("idempotency key", "", "in progress"), "ru", "Ключ идемпотентности занят другим запросом, повторите попытку"},
+		{TooLarge("document", "Plan", "too large"), "uz", "Hujjat 'Plan' bu so'rov uchun juda katta"},
+		{&Error{Reason: "UNKNOWN", Message: "as is"}, "uz", "as is"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Localize(tt.err, tt.lang), tt.want)
+	}
+}
@@ -0,0 +1,143 @@
+package apperr
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultLocale is used when the caller asks for no supported language.
+const DefaultLocale = "en"
+
+// messages holds the user facing message of every reason. Every {key} is
+// replaced from the metadata of the error, {resource} in the language of
+// the message.
+var messages = map[string]map[string]string{
+	"en": {
+		ReasonNotFound:      "{resource} '{id}' was not found",
+		ReasonMissingField:  "field '{field}' is required",
+		ReasonInvalidField:  "field '{field}' has an invalid value",
+		ReasonAlreadyExists: "{resource} '{id}' already exists",
+		ReasonNoAccess:      "you have no access to {resource} '{id}'",
+		ReasonNotAllowed:    "you are not allowed to do this with {resource} '{id}'",
+		ReasonConflict:      "{resource} '{id}' is not in a state that allows this",
+		ReasonQuotaExceeded: "the storage quota is exceeded",
+		ReasonRateLimited:   "too many requests, try again in {retryAfter} seconds",
+		ReasonAborted:       "{resource} '{id}' is in use by another request, try again",
+		ReasonTooLarge:      "{resource} '{id}' is too large for this request",
+	},
+	"uz": {
+		ReasonNotFound:      "{resource} '{id}' topilmadi",
+		ReasonMissingField:  "'{field}' maydoni to'ldirilishi shart",
+		ReasonInvalidField:  "'{field}' maydonining qiymati noto'g'ri",
+		ReasonAlreadyExists: "{resource} '{id}' allaqachon mavjud",
+		ReasonNoAccess:      "{resource} '{id}' ga ruxsatingiz yo'q",
+		ReasonNotAllowed:    "{resource} '{id}' bilan bu amalni bajarishga ruxsatingiz yo'q",
+		ReasonConflict:      "{resource} '{id}' hozirgi holatida bu amalni bajarib bo'lmaydi",
+		ReasonQuotaExceeded: "saqlash hajmi chegarasidan oshib ketdi",
+		ReasonRateLimited:   "so'rovlar juda ko'p, {retryAfter} soniyadan keyin qayta urinib ko'ring",
+		ReasonAborted:       "{resource} '{id}' boshqa so'rov bilan band, qayta urinib ko'ring",
+		ReasonTooLarge:      "{resource} '{id}' bu so'rov uchun juda katta",
+	},
+	"ru": {
+		ReasonNotFound:      "{resource} '{id}' не найден",
+		ReasonMissingField:  "поле '{field}' обязательно",
+		ReasonInvalidField:  "поле '{field}' имеет недопустимое значение",
+		ReasonAlreadyExists: "{resource} '{id}' уже существует",
+		ReasonNoAccess:      "у вас нет доступа к ресурсу {resource} '{id}'",
+		ReasonNotAllowed:    "вам не разрешено это действие с ресурсом {resource} '{id}'",
+		ReasonConflict:      "{resource} '{id}' в текущем состоянии не допускает это действие",
+		ReasonQuotaExceeded: "превышена квота хранилища",
+		ReasonRateLimited:   "слишком много запросов, повторите через {retryAfter} с",
+		ReasonAborted:       "{resource} '{id}' занят другим запросом, повторите попытку",
+		ReasonTooLarge:      "{resource} '{id}' слишком велик для этого запроса",
+	},
+}
+
+// resources translates the resource names used by the constructors.
+var resources = map[string]map[string]string{
+	"uz": {
+		"document":         "hujjat",
+		"version":          "versiya",
+		"attachment":       "ilova",
+		"comment":          "izoh",
+		"suggestion":       "taklif",
+		"template":         "shablon",
+		"workspace":        "ish maydoni",
+		"webhook":          "webhook",
+		"webhook delivery": "webhook yetkazmasi",
+		"share link":       "ulashish havolasi",
+		"access request":   "ruxsat so'rovi",
+		"user":             "foydalanuvchi",
+		"member":           "a'zo",
+		"idempotency key":  "idempotentlik kaliti",
+	},
+	"ru": {
+		"document":         "документ",
+		"version":          "версия",
+		"attachment":       "вложение",
+		"comment":          "комментарий",
+		"suggestion":       "предложение",
+		"template":         "шаблон",
+		"workspace":        "рабочее пространство",
+		"webhook":          "вебхук",
+		"webhook delivery": "доставка вебхука",
+		"share link":       "ссылка доступа",
+		"access request":   "запрос доступа",
+		"user":             "пользователь",
+		"member":           "участник",
+		"idempotency key":  "ключ идемпотентности",
+	},
+}
+
+// Locale picks the first supported language of an Accept-Language value,
+// e.g. "uz-UZ,ru;q=0.8" gives "uz".
+func Locale(acceptLanguage string) string {
+	for _, tag := range strings.Split(acceptLanguage, ",") {
+		tag, _, _ = strings.Cut(tag, ";")
+		lang, _, _ := strings.Cut(strings.TrimSpace(tag), "-")
+		lang = strings.ToLower(lang)
+		if _, ok := messages[lang]; ok {
+			return lang
+		}
+	}
+	return DefaultLocale
+}
+
+// Localize returns the message of e in the language of acceptLanguage.
+func Localize(e *Error, acceptLanguage string) string {
+	_, msg := localize(acceptLanguage, e)
+	return msg
+}
+
+func localize(acceptLanguage string, e *Error) (string, string) {
+	lang := Locale(acceptLanguage)
+	template, ok := messages[lang][e.Reason]
+	if !ok {
+		return lang, e.Message
+	}
+
+	resource := e.Metadata["resource"]
+	if name, ok := resources[lang][resource]; ok {
+		resource = name
+	}
+	if e.Metadata["id"] == "" {
+		template = strings.ReplaceAll(template, " '{id}'", "")
+	}
+	pairs := []string{"{resource}", resource, "{id}", e.Metadata["id"], "{field}", e.Metadata["field"]}
+	for k, v := range e.Metadata {
+		if k != "resource" {
+			pairs = append(pairs, "{"+k+"}", v)
+		}
+	}
+	msg := strings.NewReplacer(pairs...).Replace(template)
+	return lang, capitalize(msg)
+}
+
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return s
+	}
+	return string(unicode.ToUpper(r)) + s[size:]
+}
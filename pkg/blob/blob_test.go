@@ -0,0 +1,86 @@
+package blob
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testStore runs the behaviour every Store must have.
+func testStore(t *testing.T, s Store) {
+	ctx := context.Background()
+
+	n, err := s.Put(ctx, "a1", strings.NewReader("hello"))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), n)
+
+	r, err := s.Get(ctx, "a1")
+	assert.NoError(t, err)
+	data, _ := io.ReadAll(r)
+	r.Close()
+	assert.Equal(t, "hello", string(data))
+
+	assert.NoError(t, s.Delete(ctx, "a1"))
+	assert.NoError(t, s.Delete(ctx, "a1"))
+	_, err = s.Get(ctx, "a1")
+	assert.Equal(t, ErrNotFound, err)
+
+	_, err = s.Put(ctx, "../escape", strings.NewReader("x"))
+	assert.Error(t, err)
+}
+
+// TestLocalStore tests the file system store.
+func TestLocalStore(t *testing.T) {
+	s, err := NewLocalStore(t.TempDir())
+	assert.NoError(t, err)
+	testStore(t, s)
+}
+
+// TestS3Store tests the S3 store against a local stand-in that checks the
+// signature header and the payload hash.
+func TestS3Store(t *testing.T) {
+	var mu sync.Mutex
+	objects := map[string][]byte{}
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=key/") ||
+			!strings.Contains(r.Header.Get("Authorization"), "/us-east-1/s3/aws4_request") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		sum := sha256.Sum256(body)
+		if r.Header.Get("x-amz-content-sha256") != hex.EncodeToString(sum[:]) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodPut:
+			objects[r.URL.Path] = body
+		case http.MethodGet:
+			data, ok := objects[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write(data)
+		case http.MethodDelete:
+			delete(objects, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer stub.Close()
+
+	s := NewS3Store(S3Config{Endpoint: stub.URL, Region: "us-east-1", Bucket: "docs", AccessKey: "key", SecretKey: "secret"}, stub.Client())
+	testStore(t, s)
+}
@@ -0,0 +1,56 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type gridFSStore struct {
+	bucket *gridfs.Bucket
+}
+
+// NewGridFSStore keeps objects in the GridFS bucket name of db, using the key
+// as file id.
+func NewGridFSStore(db *mongo.Database, name string) (Store, error) {
+	bucket, err := gridfs.NewBucket(db, options.GridFSBucket().SetName(name))
+	if err != nil {
+		return nil, err
+	}
+	return &gridFSStore{bucket: bucket}, nil
+}
+
+func (s *gridFSStore) Put(ctx context.Context, key string, r io.Reader) (int64, error) {
+	if !validKey(key) {
+		return 0, fmt.Errorf("blob key '%s' is not valid", key)
+	}
+	counter := &countingReader{r: r}
+	if err := s.bucket.UploadFromStreamWithID(key, key, counter); err != nil {
+		return 0, err
+	}
+	return counter.n, nil
+}
+
+func (s *gridFSStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	stream, err := s.bucket.OpenDownloadStream(key)
+	if errors.Is(err, gridfs.ErrFileNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return stream, nil
+}
+
+func (s *gridFSStore) Delete(ctx context.Context, key string) error {
+	err := s.bucket.DeleteContext(ctx, key)
+	if errors.Is(err, gridfs.ErrFileNotFound) {
+		return nil
+	}
+	return err
+}
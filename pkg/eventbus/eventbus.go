@@ -0,0 +1,55 @@
+// Package eventbus is an in-process publish/subscribe bus.
+package eventbus
+
+import "sync"
+
+// subscriberBuffer is how many events a subscriber may fall behind before
+// further events are dropped for it.
+const subscriberBuffer = 64
+
+// Bus fans every published event out to all current subscribers. Publish never
+// blocks; a subscriber that does not keep up misses events.
+type Bus[T any] struct {
+	mu   sync.RWMutex
+	next int
+	subs map[int]chan T
+}
+
+func New[T any]() *Bus[T] {
+	return &Bus[T]{subs: map[int]chan T{}}
+}
+
+// Subscribe returns a channel of events published from now on and a function
+// that ends the subscription and closes the channel.
+func (b *Bus[T]) Subscribe() (<-chan T, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.next
+	b.next++
+	ch := make(chan T, subscriberBuffer)
+	b.subs[id] = ch
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			delete(b.subs, id)
+			close(ch)
+		})
+	}
+}
+
+// Publish delivers event to every subscriber with room in its buffer.
+func (b *Bus[T]) Publish(event T) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, ch := range b.subs {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package eventbus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestBus tests fan-out to subscribers and unsubscribing.
+func TestBus(t *testing.T) {
+	bus := New[string]()
+	a, cancelA := bus.Subscribe()
+	b, cancelB := bus.Subscribe()
+	defer cancelB()
+
+	bus.Publish("updated")
+	assert.Equal(t, "updated", <-a)
+	assert.Equal(t, "updated", <-b)
+
+	cancelA()
+	cancelA()
+	_, open := <-a
+	assert.False(t, open)
+
+	bus.Publish("deleted")
+	assert.Equal(t, "deleted", <-b)
+}
+
+// TestBusDropsWhenFull tests that a slow subscriber does not block publishers.
+func TestBusDropsWhenFull(t *testing.T) {
+	bus := New[int]()
+	ch, cancel := bus.Subscribe()
+	defer cancel()
+
+	for i := 0; i < subscriberBuffer+10; i++ {
+		bus.Publish(i)
+	}
+	assert.Len(t, ch, subscriberBuffer)
+}
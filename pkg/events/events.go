@@ -0,0 +1,30 @@
+// Package events publishes document lifecycle events to a message broker.
+package events
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Event is the broker message. Id is stable across redeliveries, so
+// consumers can drop duplicates.
+type Event struct {
+	Id        string `json:"id"`
+	Kind      string `json:"kind"`
+	DocsId    string `json:"docsId"`
+	Title     string `json:"title"`
+	AuthorId  string `json:"authorId,omitempty"`
+	Actor     string `json:"actor,omitempty"`
+	CreatedAt string `json:"createdAt"`
+}
+
+// Publisher hands events to a broker. Publish returns only once the broker
+// has accepted the event.
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+	Close() error
+}
+
+func (e Event) encode() ([]byte, error) {
+	return json.Marshal(e)
+}
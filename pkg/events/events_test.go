@@ -0,0 +1,26 @@
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMemoryPublisher tests that published events are kept in order.
+func TestMemoryPublisher(t *testing.T) {
+	p := NewMemoryPublisher()
+	assert.NoError(t, p.Publish(context.Background(), Event{Id: "1", Kind: "created"}))
+	assert.NoError(t, p.Publish(context.Background(), Event{Id: "2", Kind: "updated"}))
+
+	got := p.Events()
+	assert.Len(t, got, 2)
+	assert.Equal(t, "updated", got[1].Kind)
+}
+
+// TestEncode tests the JSON shape consumers rely on.
+func TestEncode(t *testing.T) {
+	data, err := Event{Id: "1", Kind: "deleted", DocsId: "d", Title: "Plan", CreatedAt: "2024-01-01T00:00:00Z"}.encode()
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"id":"1","kind":"deleted","docsId":"d","title":"Plan","createdAt":"2024-01-01T00:00:00Z"}`, string(data))
+}
@@ -0,0 +1,38 @@
+package events
+
+import (
+	"context"
+
+	"github.com/segmentio/kafka-go"
+)
+
+type kafkaPublisher struct {
+	writer *kafka.Writer
+}
+
+// NewKafkaPublisher writes events to topic. Messages are keyed by docsId, so
+// the events of one document stay in order within a partition.
+func NewKafkaPublisher(brokers []string, topic string) Publisher {
+	return &kafkaPublisher{writer: &kafka.Writer{
+		Addr:         kafka.TCP(brokers...),
+		Topic:        topic,
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+	}}
+}
+
+func (p *kafkaPublisher) Publish(ctx context.Context, event Event) error {
+	value, err := event.encode()
+	if err != nil {
+		return err
+	}
+	return p.writer.WriteMessages(ctx, kafka.Message{
+		Key:     []byte(event.DocsId),
+		Value:   value,
+		Headers: []kafka.Header{{Key: "kind", Value: []byte(event.Kind)}},
+	})
+}
+
+func (p *kafkaPublisher) Close() error {
+	return p.writer.Close()
+}
@@ -0,0 +1,46 @@
+package events
+
+import (
+	"context"
+	"errors"
+
+	"github.com/nats-io/nats.go"
+)
+
+type natsPublisher struct {
+	conn    *nats.Conn
+	subject string
+}
+
+// NewNATSPublisher publishes every event on subject followed by its kind,
+// e.g. "docs.events.updated". The server does not need to be up yet; events
+// wait in the outbox until it is.
+func NewNATSPublisher(url, subject string) (Publisher, error) {
+	conn, err := nats.Connect(url,
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1),
+		nats.ReconnectBufSize(-1))
+	if err != nil {
+		return nil, err
+	}
+	return &natsPublisher{conn: conn, subject: subject}, nil
+}
+
+func (p *natsPublisher) Publish(ctx context.Context, event Event) error {
+	if !p.conn.IsConnected() {
+		return errors.New("nats: not connected")
+	}
+	data, err := event.encode()
+	if err != nil {
+		return err
+	}
+	if err := p.conn.Publish(p.subject+"."+event.Kind, data); err != nil {
+		return err
+	}
+	return p.conn.FlushWithContext(ctx)
+}
+
+func (p *natsPublisher) Close() error {
+	p.conn.Close()
+	return nil
+}
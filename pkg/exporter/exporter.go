@@ -0,0 +1,161 @@
+// Package exporter renders rich-text documents as downloadable files.
+package exporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"strings"
+
+	"mainService/pkg/richtext"
+)
+
+const (
+	Text     = "text"
+	JSON     = "json"
+	Markdown = "markdown"
+	HTML     = "html"
+)
+
+// Supported reports whether Export knows format.
+func Supported(format string) bool {
+	switch format {
+	case "", Text, JSON, Markdown, HTML:
+		return true
+	}
+	return false
+}
+
+// Export renders doc in format and returns the file with its content type.
+// An empty format means plain text.
+func Export(doc *richtext.Document, format string) ([]byte, string, error) {
+	switch format {
+	case "", Text:
+		return []byte(richtext.ToPlain(doc)), "text/plain; charset=utf-8", nil
+	case JSON:
+		data, err := json.Marshal(doc)
+		return data, "application/json", err
+	case Markdown:
+		return []byte(markdown(doc)), "text/markdown; charset=utf-8", nil
+	case HTML:
+		return []byte(htmlPage(doc)), "text/html; charset=utf-8", nil
+	}
+	return nil, "", fmt.Errorf("export format '%s' is not supported", format)
+}
+
+func markdown(doc *richtext.Document) string {
+	blocks := make([]string, 0, len(doc.Blocks))
+	for _, b := range doc.Blocks {
+		switch b.Type {
+		case richtext.Paragraph:
+			blocks = append(blocks, mdInlines(b.Inlines))
+		case richtext.Heading:
+			blocks = append(blocks, strings.Repeat("#", b.Level)+" "+mdInlines(b.Inlines))
+		case richtext.List:
+			lines := make([]string, len(b.Items))
+			for i, item := range b.Items {
+				marker := "-"
+				if b.Ordered {
+					marker = fmt.Sprintf("%d.", i+1)
+				}
+				lines[i] = marker + " " + mdInlines(item)
+			}
+			blocks = append(blocks, strings.Join(lines, "\n"))
+		case richtext.Table:
+			var lines []string
+			for i, row := range b.Rows {
+				cells := make([]string, len(row))
+				for j, cell := range row {
+					cells[j] = strings.ReplaceAll(mdInlines(cell), "|", `\|`)
+				}
+				lines = append(lines, "| "+strings.Join(cells, " | ")+" |")
+				if i == 0 {
+					lines = append(lines, "|"+strings.Repeat(" --- |", len(row)))
+				}
+			}
+			blocks = append(blocks, strings.Join(lines, "\n"))
+		case richtext.Image:
+			blocks = append(blocks, "!["+mdEscape(b.Alt)+"]("+b.Src+")")
+		}
+	}
+	return strings.Join(blocks, "\n\n") + "\n"
+}
+
+func mdInlines(inlines []richtext.Inline) string {
+	var sb strings.Builder
+	for _, in := range inlines {
+		text := mdEscape(in.Text)
+		if in.Italic {
+			text = "*" + text + "*"
+		}
+		if in.Bold {
+			text = "**" + text + "**"
+		}
+		if in.Link != "" {
+			text = "[" + text + "](" + in.Link + ")"
+		}
+		sb.WriteString(text)
+	}
+	return sb.String()
+}
+
+var mdEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "`", "\\`")
+
+func mdEscape(s string) string {
+	return mdEscaper.Replace(s)
+}
+
+func htmlPage(doc *richtext.Document) string {
+	var sb strings.Builder
+	sb.WriteString("<!DOCTYPE html>\n<html><head><meta charset=\"utf-8\"></head><body>\n")
+	for _, b := range doc.Blocks {
+		switch b.Type {
+		case richtext.Paragraph:
+			sb.WriteString("<p>" + htmlInlines(b.Inlines) + "</p>\n")
+		case richtext.Heading:
+			fmt.Fprintf(&sb, "<h%d>%s</h%d>\n", b.Level, htmlInlines(b.Inlines), b.Level)
+		case richtext.List:
+			tag := "ul"
+			if b.Ordered {
+				tag = "ol"
+			}
+			sb.WriteString("<" + tag + ">")
+			for _, item := range b.Items {
+				sb.WriteString("<li>" + htmlInlines(item) + "</li>")
+			}
+			sb.WriteString("</" + tag + ">\n")
+		case richtext.Table:
+			sb.WriteString("<table>")
+			for _, row := range b.Rows {
+				sb.WriteString("<tr>")
+				for _, cell := range row {
+					sb.WriteString("<td>" + htmlInlines(cell) + "</td>")
+				}
+				sb.WriteString("</tr>")
+			}
+			sb.WriteString("</table>\n")
+		case richtext.Image:
+			sb.WriteString(`<img src="` + html.EscapeString(b.Src) + `" alt="` + html.EscapeString(b.Alt) + "\">\n")
+		}
+	}
+	sb.WriteString("</body></html>\n")
+	return sb.String()
+}
+
+func htmlInlines(inlines []richtext.Inline) string {
+	var sb strings.Builder
+	for _, in := range inlines {
+		text := strings.ReplaceAll(html.EscapeString(in.Text), "\n", "<br>")
+		if in.Italic {
+			text = "<em>" + text + "</em>"
+		}
+		if in.Bold {
+			text = "<strong>" + text + "</strong>"
+		}
+		if in.Link != "" {
+			text = `<a href="` + html.EscapeString(in.Link) + `">` + text + "</a>"
+		}
+		sb.WriteString(text)
+	}
+	return sb.String()
+}
@@ -0,0 +1,43 @@
+package exporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"mainService/pkg/importer"
+	"mainService/pkg/richtext"
+)
+
+var sample = &richtext.Document{Version: richtext.SchemaVersion, Blocks: []richtext.Block{
+	{Type: richtext.Heading, Level: 1, Inlines: []richtext.Inline{{Text: "Plan"}}},
+	{Type: richtext.Paragraph, Inlines: []richtext.Inline{{Text: "a "}, {Text: "b", Bold: true}, {Text: " c", Link: "https://example.com"}}},
+	{Type: richtext.List, Items: [][]richtext.Inline{{{Text: "one"}}, {{Text: "two"}}}},
+	{Type: richtext.Table, Rows: [][]richtext.Cell{{{{Text: "x"}}, {{Text: "y"}}}, {{{Text: "1"}}, {{Text: "2"}}}}},
+	{Type: richtext.Image, Src: "attachment:a1", Alt: "chart"},
+}}
+
+// TestMarkdownRoundTrip tests that exported Markdown imports back to the same tree.
+func TestMarkdownRoundTrip(t *testing.T) {
+	data, _, err := Export(sample, Markdown)
+	assert.NoError(t, err)
+
+	doc, err := importer.Convert(importer.Markdown, data)
+	assert.NoError(t, err)
+	assert.Equal(t, richtext.ToPlain(sample), richtext.ToPlain(doc))
+	assert.Equal(t, sample.Blocks[1].Inlines[1], doc.Blocks[1].Inlines[1])
+}
+
+// TestHTML tests escaping in the HTML export.
+func TestHTML(t *testing.T) {
+	doc := &richtext.Document{Version: richtext.SchemaVersion, Blocks: []richtext.Block{
+		{Type: richtext.Paragraph, Inlines: []richtext.Inline{{Text: "<script>", Italic: true}}},
+	}}
+	data, contentType, err := Export(doc, HTML)
+	assert.NoError(t, err)
+	assert.Equal(t, "text/html; charset=utf-8", contentType)
+	assert.Contains(t, string(data), "<p><em>&lt;script&gt;</em></p>")
+
+	_, _, err = Export(doc, "pdf")
+	assert.Error(t, err)
+}
@@ -0,0 +1,237 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"mainService/pkg/richtext"
+)
+
+// maxDocxPart bounds how much of a single part of the archive is read, as a
+// guard against zip bombs.
+const maxDocxPart = 64 << 20
+
+type docxParagraph struct {
+	style   string
+	list    bool
+	inlines []richtext.Inline
+}
+
+// parseDOCX reads word/document.xml. Paragraph styles give headings, numbered
+// paragraphs become list items, and runs keep bold, italic and hyperlinks.
+// Embedded pictures are skipped.
+func parseDOCX(data []byte) (*richtext.Document, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("docx file is not a valid archive: %v", err)
+	}
+
+	body, err := readZipPart(zr, "word/document.xml")
+	if err != nil {
+		return nil, err
+	}
+	rels := map[string]string{}
+	if relsXML, err := readZipPart(zr, "word/_rels/document.xml.rels"); err == nil {
+		rels = parseDocxRels(relsXML)
+	}
+
+	doc := &richtext.Document{Version: richtext.SchemaVersion}
+	var para *docxParagraph
+	var bold, italic bool
+	var link string
+
+	var tableDepth int
+	var rows [][]richtext.Cell
+	var row []richtext.Cell
+	var cell []richtext.Inline
+
+	addText := func(text string) {
+		if para != nil {
+			para.inlines = appendInline(para.inlines, richtext.Inline{Text: text, Bold: bold, Italic: italic, Link: link})
+		}
+	}
+
+	d := xml.NewDecoder(bytes.NewReader(body))
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("docx document is malformed: %v", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "p":
+				para = &docxParagraph{}
+			case "pStyle":
+				if para != nil {
+					para.style = xmlAttr(t, "val")
+				}
+			case "numPr":
+				if para != nil {
+					para.list = true
+				}
+			case "hyperlink":
+				link = safeLink(rels[xmlAttr(t, "id")])
+			case "r":
+				bold, italic = false, false
+			case "b":
+				bold = xmlOn(t)
+			case "i":
+				italic = xmlOn(t)
+			case "t":
+				var text string
+				if err := d.DecodeElement(&text, &t); err != nil {
+					return nil, fmt.Errorf("docx document is malformed: %v", err)
+				}
+				addText(text)
+			case "tab":
+				addText("\t")
+			case "br", "cr":
+				addText("\n")
+			case "tbl":
+				tableDepth++
+				if tableDepth == 1 {
+					rows = nil
+				}
+			case "tr":
+				if tableDepth == 1 {
+					row = nil
+				}
+			case "tc":
+				if tableDepth == 1 {
+					cell = nil
+				}
+			}
+
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "hyperlink":
+				link = ""
+			case "p":
+				if para == nil {
+					continue
+				}
+				if tableDepth > 0 {
+					if len(cell) > 0 && len(para.inlines) > 0 {
+						cell = appendInline(cell, richtext.Inline{Text: "\n"})
+					}
+					for _, in := range para.inlines {
+						cell = appendInline(cell, in)
+					}
+				} else {
+					doc.Blocks = appendDocxParagraph(doc.Blocks, para)
+				}
+				para = nil
+			case "tc":
+				if tableDepth == 1 {
+					row = append(row, cell)
+				}
+			case "tr":
+				if tableDepth == 1 && len(row) > 0 {
+					rows = append(rows, row)
+				}
+			case "tbl":
+				tableDepth--
+				if tableDepth == 0 && len(rows) > 0 {
+					doc.Blocks = append(doc.Blocks, richtext.Block{Type: richtext.Table, Rows: padRows(rows)})
+				}
+			}
+		}
+	}
+
+	return doc, nil
+}
+
+func appendDocxParagraph(blocks []richtext.Block, p *docxParagraph) []richtext.Block {
+	if level := docxHeadingLevel(p.style); level > 0 {
+		return append(blocks, richtext.Block{Type: richtext.Heading, Level: level, Inlines: p.inlines})
+	}
+	if len(p.inlines) == 0 {
+		return blocks
+	}
+	if p.list {
+		if n := len(blocks); n > 0 && blocks[n-1].Type == richtext.List {
+			blocks[n-1].Items = append(blocks[n-1].Items, p.inlines)
+			return blocks
+		}
+		return append(blocks, richtext.Block{Type: richtext.List, Items: [][]richtext.Inline{p.inlines}})
+	}
+	return append(blocks, richtext.Block{Type: richtext.Paragraph, Inlines: p.inlines})
+}
+
+// docxHeadingLevel maps the built-in style ids "Title" and "Heading1" to
+// "Heading6" to a heading level, or returns 0.
+func docxHeadingLevel(style string) int {
+	if style == "Title" {
+		return 1
+	}
+	if !strings.HasPrefix(style, "Heading") {
+		return 0
+	}
+	level, err := strconv.Atoi(strings.TrimPrefix(style, "Heading"))
+	if err != nil || level < 1 || level > 6 {
+		return 0
+	}
+	return level
+}
+
+func parseDocxRels(data []byte) map[string]string {
+	var rels struct {
+		Relationships []struct {
+			Id     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	res := map[string]string{}
+	if err := xml.Unmarshal(data, &rels); err != nil {
+		return res
+	}
+	for _, r := range rels.Relationships {
+		res[r.Id] = r.Target
+	}
+	return res
+}
+
+func readZipPart(zr *zip.Reader, name string) ([]byte, error) {
+	f, err := zr.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("docx file has no %s", name)
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(f, maxDocxPart+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxDocxPart {
+		return nil, fmt.Errorf("docx part %s is too large", name)
+	}
+	return data, nil
+}
+
+func xmlAttr(t xml.StartElement, local string) string {
+	for _, a := range t.Attr {
+		if a.Name.Local == local {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// xmlOn reads an on/off property such as <w:b/> or <w:b w:val="0"/>.
+func xmlOn(t xml.StartElement) bool {
+	switch xmlAttr(t, "val") {
+	case "0", "false", "off":
+		return false
+	}
+	return true
+}
@@ -0,0 +1,224 @@
+package importer
+
+import (
+	"bytes"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"mainService/pkg/richtext"
+)
+
+type htmlStyle struct {
+	bold, italic bool
+	link         string
+}
+
+type htmlConverter struct {
+	blocks  []richtext.Block
+	inlines []richtext.Inline
+}
+
+// parseHTML keeps the structure of the body: headings, paragraphs, lists,
+// tables, images and bold, italic and link runs. Other markup is unwrapped.
+func parseHTML(data []byte) (*richtext.Document, error) {
+	root, err := html.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	c := &htmlConverter{}
+	c.walk(root, htmlStyle{})
+	c.flush()
+	return &richtext.Document{Version: richtext.SchemaVersion, Blocks: c.blocks}, nil
+}
+
+func (c *htmlConverter) walk(n *html.Node, style htmlStyle) {
+	switch n.Type {
+	case html.TextNode:
+		c.inlines = appendInline(c.inlines, richtext.Inline{
+			Text:   collapseSpace(n.Data),
+			Bold:   style.bold,
+			Italic: style.italic,
+			Link:   style.link,
+		})
+		return
+	case html.ElementNode:
+	default:
+		c.children(n, style)
+		return
+	}
+
+	switch n.DataAtom {
+	case atom.Head, atom.Script, atom.Style, atom.Noscript, atom.Template:
+		return
+	case atom.B, atom.Strong:
+		style.bold = true
+	case atom.I, atom.Em:
+		style.italic = true
+	case atom.A:
+		style.link = safeLink(attr(n, "href"))
+	case atom.Br:
+		c.inlines = appendInline(c.inlines, richtext.Inline{Text: "\n", Bold: style.bold, Italic: style.italic})
+		return
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		c.flush()
+		c.blocks = append(c.blocks, richtext.Block{
+			Type:    richtext.Heading,
+			Level:   int(n.Data[1] - '0'),
+			Inlines: trimInlines(c.collect(n, style)),
+		})
+		return
+	case atom.Ul, atom.Ol:
+		c.flush()
+		list := richtext.Block{Type: richtext.List, Ordered: n.DataAtom == atom.Ol}
+		for li := n.FirstChild; li != nil; li = li.NextSibling {
+			if li.Type == html.ElementNode && li.DataAtom == atom.Li {
+				list.Items = append(list.Items, trimInlines(c.collect(li, style)))
+			}
+		}
+		if len(list.Items) > 0 {
+			c.blocks = append(c.blocks, list)
+		}
+		return
+	case atom.Table:
+		c.flush()
+		var rows [][]richtext.Cell
+		eachElement(n, atom.Tr, func(tr *html.Node) {
+			var row []richtext.Cell
+			for td := tr.FirstChild; td != nil; td = td.NextSibling {
+				if td.Type == html.ElementNode && (td.DataAtom == atom.Td || td.DataAtom == atom.Th) {
+					row = append(row, trimInlines(c.collect(td, style)))
+				}
+			}
+			if len(row) > 0 {
+				rows = append(rows, row)
+			}
+		})
+		if len(rows) > 0 {
+			c.blocks = append(c.blocks, richtext.Block{Type: richtext.Table, Rows: padRows(rows)})
+		}
+		return
+	case atom.Img:
+		if src := attr(n, "src"); src != "" {
+			c.flush()
+			c.blocks = append(c.blocks, richtext.Block{Type: richtext.Image, Src: src, Alt: attr(n, "alt")})
+		}
+		return
+	case atom.Pre:
+		c.flush()
+		c.blocks = append(c.blocks, richtext.Block{
+			Type:    richtext.Paragraph,
+			Inlines: []richtext.Inline{{Text: textContent(n)}},
+		})
+		return
+	case atom.P, atom.Div, atom.Section, atom.Article, atom.Blockquote, atom.Header, atom.Footer, atom.Main, atom.Li, atom.Hr:
+		c.flush()
+		c.children(n, style)
+		c.flush()
+		return
+	}
+
+	c.children(n, style)
+}
+
+func (c *htmlConverter) children(n *html.Node, style htmlStyle) {
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		c.walk(child, style)
+	}
+}
+
+// collect returns the inline content of n. Block markup inside it, such as a
+// nested list, is flattened into the text.
+func (c *htmlConverter) collect(n *html.Node, style htmlStyle) []richtext.Inline {
+	sub := &htmlConverter{}
+	sub.children(n, style)
+	inlines := sub.inlines
+	for _, b := range sub.blocks {
+		text := richtext.ToPlain(&richtext.Document{Blocks: []richtext.Block{b}})
+		inlines = appendInline(inlines, richtext.Inline{Text: " " + text})
+	}
+	return inlines
+}
+
+func (c *htmlConverter) flush() {
+	inlines := trimInlines(c.inlines)
+	c.inlines = nil
+	if len(inlines) > 0 {
+		c.blocks = append(c.blocks, richtext.Block{Type: richtext.Paragraph, Inlines: inlines})
+	}
+}
+
+// trimInlines strips the whitespace at both ends of a run list.
+func trimInlines(inlines []richtext.Inline) []richtext.Inline {
+	for len(inlines) > 0 {
+		inlines[0].Text = strings.TrimLeft(inlines[0].Text, " \n")
+		if inlines[0].Text != "" {
+			break
+		}
+		inlines = inlines[1:]
+	}
+	for n := len(inlines); n > 0; n = len(inlines) {
+		inlines[n-1].Text = strings.TrimRight(inlines[n-1].Text, " \n")
+		if inlines[n-1].Text != "" {
+			break
+		}
+		inlines = inlines[:n-1]
+	}
+	return inlines
+}
+
+func collapseSpace(s string) string {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		if s == "" {
+			return ""
+		}
+		return " "
+	}
+	res := strings.Join(fields, " ")
+	if strings.TrimLeft(s[:1], " \t\n\r\f") == "" {
+		res = " " + res
+	}
+	if strings.TrimRight(s[len(s)-1:], " \t\n\r\f") == "" {
+		res += " "
+	}
+	return res
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func textContent(n *html.Node) string {
+	var sb strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			sb.WriteString(n.Data)
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(n)
+	return strings.Trim(sb.String(), "\n")
+}
+
+// eachElement calls fn for every descendant of n with the given tag, without
+// descending into matches.
+func eachElement(n *html.Node, a atom.Atom, fn func(*html.Node)) {
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && child.DataAtom == a {
+			fn(child)
+			continue
+		}
+		eachElement(child, a, fn)
+	}
+}
@@ -0,0 +1,137 @@
+// Package importer converts uploaded files into the rich-text model.
+package importer
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"unicode/utf8"
+
+	"mainService/pkg/richtext"
+)
+
+const (
+	Markdown = "markdown"
+	HTML     = "html"
+	Text     = "text"
+	DOCX     = "docx"
+)
+
+// Detect picks the format from the file extension and falls back to looking
+// at the content. It returns "" for files it can not import.
+func Detect(filename string, data []byte) string {
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".md", ".markdown":
+		return Markdown
+	case ".html", ".htm":
+		return HTML
+	case ".txt", ".text":
+		return Text
+	case ".docx":
+		return DOCX
+	}
+
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		return DOCX
+	}
+	if strings.HasPrefix(http.DetectContentType(data), "text/html") {
+		return HTML
+	}
+	if utf8.Valid(data) {
+		return Text
+	}
+	return ""
+}
+
+// Convert parses data in the given format. The result is valid rich text.
+func Convert(format string, data []byte) (*richtext.Document, error) {
+	var doc *richtext.Document
+	var err error
+
+	switch format {
+	case Text:
+		if !utf8.Valid(data) {
+			return nil, fmt.Errorf("text file is not valid UTF-8")
+		}
+		doc = richtext.FromPlain(normalizeNewlines(string(data)))
+	case Markdown:
+		doc = parseMarkdown(normalizeNewlines(string(data)))
+	case HTML:
+		doc, err = parseHTML(data)
+	case DOCX:
+		doc, err = parseDOCX(data)
+	default:
+		return nil, fmt.Errorf("format '%s' is not supported", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := richtext.Validate(doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// Title derives a document title from an uploaded file name.
+func Title(filename string) string {
+	base := path.Base(strings.ReplaceAll(filename, "\\", "/"))
+	title := strings.TrimSpace(strings.TrimSuffix(base, path.Ext(base)))
+	if title == "" || title == "." || title == "/" {
+		return "Untitled"
+	}
+	return title
+}
+
+func normalizeNewlines(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "\r\n", "\n"), "\r", "\n")
+}
+
+// safeLink drops link targets the rich-text model does not accept, so a
+// stray javascript: link does not fail the whole import.
+func safeLink(link string) string {
+	u, err := url.Parse(strings.TrimSpace(link))
+	if err != nil {
+		return ""
+	}
+	switch u.Scheme {
+	case "http", "https", "mailto":
+		return u.String()
+	}
+	return ""
+}
+
+// appendInline adds in to inlines, merging it into the previous run when the
+// formatting is the same.
+func appendInline(inlines []richtext.Inline, in richtext.Inline) []richtext.Inline {
+	if in.Text == "" {
+		return inlines
+	}
+	if n := len(inlines); n > 0 {
+		last := &inlines[n-1]
+		if last.Bold == in.Bold && last.Italic == in.Italic && last.Link == in.Link {
+			last.Text += in.Text
+			return inlines
+		}
+	}
+	return append(inlines, in)
+}
+
+// padRows gives every row of a table the same number of cells.
+func padRows(rows [][]richtext.Cell) [][]richtext.Cell {
+	width := 0
+	for _, row := range rows {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+	for i := range rows {
+		for len(rows[i]) < width {
+			rows[i] = append(rows[i], richtext.Cell{})
+		}
+	}
+	return rows
+}
@@ -0,0 +1,110 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"mainService/pkg/richtext"
+)
+
+// TestDetect tests detection by extension and by content.
+func TestDetect(t *testing.T) {
+	assert.Equal(t, Markdown, Detect("notes.MD", nil))
+	assert.Equal(t, DOCX, Detect("upload", []byte("PK\x03\x04rest")))
+	assert.Equal(t, HTML, Detect("upload", []byte("<!DOCTYPE html><html><body>x</body></html>")))
+	assert.Equal(t, Text, Detect("upload", []byte("just text")))
+	assert.Equal(t, "", Detect("upload", []byte{0xff, 0xfe, 0x00}))
+}
+
+// TestTitle tests titles derived from file names.
+func TestTitle(t *testing.T) {
+	assert.Equal(t, "Q3 plan", Title("Q3 plan.docx"))
+	assert.Equal(t, "notes", Title(`C:\Users\me\notes.md`))
+	assert.Equal(t, "Untitled", Title(".md"))
+}
+
+// TestMarkdown tests the supported Markdown subset.
+func TestMarkdown(t *testing.T) {
+	src := "# Plan\n\nSome **bold** and _italic_ with a [link](https://example.com) in snake_case_name.\n\n" +
+		"- one\n- two\n\n1. first\n\n| a | b |\n|---|---|\n| 1 | 2 |\n\n![chart](blob-1)\n\n[bad](javascript:alert(1))"
+	doc, err := Convert(Markdown, []byte(src))
+	assert.NoError(t, err)
+
+	types := []string{}
+	for _, b := range doc.Blocks {
+		types = append(types, b.Type)
+	}
+	assert.Equal(t, []string{"heading", "paragraph", "list", "list", "table", "image", "paragraph"}, types)
+
+	assert.Equal(t, []richtext.Inline{
+		{Text: "Some "},
+		{Text: "bold", Bold: true},
+		{Text: " and "},
+		{Text: "italic", Italic: true},
+		{Text: " with a "},
+		{Text: "link", Link: "https://example.com"},
+		{Text: " in snake_case_name."},
+	}, doc.Blocks[1].Inlines)
+	assert.True(t, doc.Blocks[3].Ordered)
+	assert.Len(t, doc.Blocks[4].Rows, 2)
+	assert.Equal(t, "", doc.Blocks[6].Inlines[0].Link)
+}
+
+// TestHTML tests conversion of common HTML markup.
+func TestHTML(t *testing.T) {
+	src := `<html><head><title>x</title><style>p{}</style></head><body>
+		<h2>Plan</h2>
+		<p>Hello <b>bold</b> <a href="https://example.com"><i>link</i></a></p>
+		<ul><li>one</li><li>two</li></ul>
+		<table><tr><th>a</th><th>b</th></tr><tr><td>1</td></tr></table>
+		<img src="https://example.com/a.png" alt="pic">
+		<script>alert(1)</script>
+	</body></html>`
+	doc, err := Convert(HTML, []byte(src))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "Plan\n\nHello bold link\n\n- one\n- two\n\na\tb\n1\t\n\n[image: pic]", richtext.ToPlain(doc))
+	assert.Equal(t, 2, doc.Blocks[0].Level)
+	assert.Equal(t, richtext.Inline{Text: "link", Italic: true, Link: "https://example.com"}, doc.Blocks[1].Inlines[3])
+}
+
+// TestDOCX tests conversion of a minimal Word document.
+func TestDOCX(t *testing.T) {
+	document := `<?xml version="1.0" encoding="UTF-8"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<w:body>
+<w:p><w:pPr><w:pStyle w:val="Heading1"/></w:pPr><w:r><w:t>Plan</w:t></w:r></w:p>
+<w:p><w:r><w:t xml:space="preserve">Hello </w:t></w:r><w:r><w:rPr><w:b/></w:rPr><w:t>bold</w:t></w:r><w:hyperlink r:id="rId1"><w:r><w:t> site</w:t></w:r></w:hyperlink></w:p>
+<w:p><w:pPr><w:numPr><w:ilvl w:val="0"/></w:numPr></w:pPr><w:r><w:t>one</w:t></w:r></w:p>
+<w:p><w:pPr><w:numPr><w:ilvl w:val="0"/></w:numPr></w:pPr><w:r><w:t>two</w:t></w:r></w:p>
+<w:tbl><w:tr><w:tc><w:p><w:r><w:t>a</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>b</w:t></w:r></w:p></w:tc></w:tr></w:tbl>
+</w:body></w:document>`
+	rels := `<?xml version="1.0" encoding="UTF-8"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink" Target="https://example.com" TargetMode="External"/>
+</Relationships>`
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range map[string]string{"word/document.xml": document, "word/_rels/document.xml.rels": rels} {
+		w, err := zw.Create(name)
+		assert.NoError(t, err)
+		w.Write([]byte(content))
+	}
+	assert.NoError(t, zw.Close())
+
+	doc, err := Convert(Detect("plan.docx", buf.Bytes()), buf.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, "Plan\n\nHello bold site\n\n- one\n- two\n\na\tb", richtext.ToPlain(doc))
+	assert.Equal(t, []richtext.Inline{
+		{Text: "Hello "},
+		{Text: "bold", Bold: true},
+		{Text: " site", Link: "https://example.com"},
+	}, doc.Blocks[1].Inlines)
+
+	_, err = Convert(DOCX, []byte("PK\x03\x04broken"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,217 @@
+package importer
+
+import (
+	"regexp"
+	"strings"
+
+	"mainService/pkg/richtext"
+)
+
+var (
+	mdHeading   = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	mdBullet    = regexp.MustCompile(`^\s*[-*+]\s+(.*)$`)
+	mdNumbered  = regexp.MustCompile(`^\s*\d+[.)]\s+(.*)$`)
+	mdImage     = regexp.MustCompile(`^!\[([^\]]*)\]\(([^)\s]+)(?:\s+"[^"]*")?\)\s*$`)
+	mdSeparator = regexp.MustCompile(`^\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+)
+
+// parseMarkdown understands the common subset of Markdown: ATX headings,
+// bullet and numbered lists, pipe tables, images on their own line, fenced
+// code and **bold**, *italic* and [links](url) inside text.
+func parseMarkdown(src string) *richtext.Document {
+	doc := &richtext.Document{Version: richtext.SchemaVersion}
+	var para []string
+	var list *richtext.Block
+	var table [][]richtext.Cell
+
+	flush := func() {
+		if len(para) > 0 {
+			doc.Blocks = append(doc.Blocks, richtext.Block{
+				Type:    richtext.Paragraph,
+				Inlines: parseInlines(strings.Join(para, "\n")),
+			})
+			para = nil
+		}
+		if list != nil {
+			doc.Blocks = append(doc.Blocks, *list)
+			list = nil
+		}
+		if table != nil {
+			doc.Blocks = append(doc.Blocks, richtext.Block{Type: richtext.Table, Rows: padRows(table)})
+			table = nil
+		}
+	}
+
+	lines := strings.Split(src, "\n")
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") {
+			flush()
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				code = append(code, lines[i])
+			}
+			doc.Blocks = append(doc.Blocks, richtext.Block{
+				Type:    richtext.Paragraph,
+				Inlines: []richtext.Inline{{Text: strings.Join(code, "\n")}},
+			})
+			continue
+		}
+
+		if trimmed == "" {
+			flush()
+			continue
+		}
+
+		if m := mdHeading.FindStringSubmatch(trimmed); m != nil {
+			flush()
+			doc.Blocks = append(doc.Blocks, richtext.Block{
+				Type:    richtext.Heading,
+				Level:   len(m[1]),
+				Inlines: parseInlines(m[2]),
+			})
+			continue
+		}
+
+		if m := mdImage.FindStringSubmatch(trimmed); m != nil {
+			flush()
+			doc.Blocks = append(doc.Blocks, richtext.Block{Type: richtext.Image, Src: m[2], Alt: m[1]})
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "|") {
+			if para != nil || list != nil {
+				flush()
+			}
+			if mdSeparator.MatchString(trimmed) {
+				continue
+			}
+			table = append(table, parseTableRow(trimmed))
+			continue
+		}
+
+		ordered := false
+		m := mdBullet.FindStringSubmatch(line)
+		if m == nil {
+			m = mdNumbered.FindStringSubmatch(line)
+			ordered = m != nil
+		}
+		if m != nil {
+			if list == nil || list.Ordered != ordered || para != nil || table != nil {
+				flush()
+				list = &richtext.Block{Type: richtext.List, Ordered: ordered}
+			}
+			list.Items = append(list.Items, parseInlines(m[1]))
+			continue
+		}
+
+		if list != nil || table != nil {
+			flush()
+		}
+		para = append(para, trimmed)
+	}
+	flush()
+
+	return doc
+}
+
+func parseTableRow(line string) []richtext.Cell {
+	line = strings.TrimSuffix(strings.TrimPrefix(line, "|"), "|")
+	parts := strings.Split(line, "|")
+	row := make([]richtext.Cell, len(parts))
+	for i, p := range parts {
+		row[i] = parseInlines(strings.TrimSpace(p))
+	}
+	return row
+}
+
+// parseInlines splits text into formatted runs. Markers without a closing
+// partner are kept as text.
+func parseInlines(s string) []richtext.Inline {
+	var res []richtext.Inline
+	var cur strings.Builder
+	bold, italic := false, false
+
+	emit := func(link string) {
+		res = appendInline(res, richtext.Inline{Text: cur.String(), Bold: bold, Italic: italic, Link: link})
+		cur.Reset()
+	}
+
+	for i := 0; i < len(s); i++ {
+		rest := s[i:]
+		switch {
+		case rest[0] == '\\' && len(rest) > 1:
+			cur.WriteByte(rest[1])
+			i++
+		case strings.HasPrefix(rest, "**") || strings.HasPrefix(rest, "__"):
+			if toggles(s, i, 2, bold) {
+				emit("")
+				bold = !bold
+				i++
+				continue
+			}
+			cur.WriteString(rest[:2])
+			i++
+		case rest[0] == '*' || rest[0] == '_':
+			if toggles(s, i, 1, italic) {
+				emit("")
+				italic = !italic
+				continue
+			}
+			cur.WriteByte(rest[0])
+		case rest[0] == '[' || strings.HasPrefix(rest, "!["):
+			image := rest[0] == '!'
+			open := rest
+			if image {
+				open = rest[1:]
+			}
+			end := strings.Index(open, "](")
+			close := strings.Index(open, ")")
+			if end < 0 || close < end {
+				cur.WriteByte(rest[0])
+				continue
+			}
+			text, target := open[1:end], open[end+2:close]
+			emit("")
+			if image {
+				cur.WriteString(text)
+				emit("")
+			} else {
+				cur.WriteString(text)
+				emit(safeLink(target))
+			}
+			i += close + 1
+			if image {
+				i++
+			}
+			i--
+		default:
+			cur.WriteByte(rest[0])
+		}
+	}
+	emit("")
+
+	return res
+}
+
+// toggles reports whether the emphasis marker of the given width at s[i]
+// opens or closes a run. Opening needs a partner further on; underscores only
+// count at word boundaries, so snake_case stays text.
+func toggles(s string, i, width int, on bool) bool {
+	marker := s[i : i+width]
+	if marker[0] == '_' {
+		if on && i+width < len(s) && isWordByte(s[i+width]) {
+			return false
+		}
+		if !on && i > 0 && isWordByte(s[i-1]) {
+			return false
+		}
+	}
+	return on || strings.Contains(s[i+width:], marker)
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= 0x80
+}
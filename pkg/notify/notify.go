@@ -0,0 +1,43 @@
+package notify
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	KindShare          = "share"
+	KindMention        = "mention"
+	KindComment        = "comment"
+	KindAccessRequest  = "access_request"
+	KindAccessApproved = "access_approved"
+	KindAccessDenied   = "access_denied"
+)
+
+// Message is a rendered notification ready for delivery.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender delivers a message. Implementations must be safe for concurrent use.
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Backoff returns how long to wait before retrying after the given number of
+// failed attempts: 30s, 1m, 2m, ... capped at one hour.
+func Backoff(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	d := 30 * time.Second
+	for i := 1; i < attempts && d < time.Hour; i++ {
+		d *= 2
+	}
+	if d > time.Hour {
+		d = time.Hour
+	}
+	return d
+}
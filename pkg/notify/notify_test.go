@@ -0,0 +1,32 @@
+package notify
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRender tests rendering the share template.
+func TestRender(t *testing.T) {
+	msg, err := Render(KindShare, "bob@example.com", map[string]string{
+		"sharedBy": "alice",
+		"title":    "Plan",
+		"role":     "editor",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "bob@example.com", msg.To)
+	assert.Equal(t, `alice shared "Plan" with you`, msg.Subject)
+	assert.Contains(t, msg.Body, "editor access")
+	assert.NotContains(t, msg.Body, "expires")
+
+	_, err = Render("unknown", "bob@example.com", nil)
+	assert.Error(t, err)
+}
+
+// TestBackoff tests the retry delays.
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, 30*time.Second, Backoff(1))
+	assert.Equal(t, 2*time.Minute, Backoff(3))
+	assert.Equal(t, time.Hour, Backoff(20))
+}
@@ -0,0 +1,88 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+type messageTemplate struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+var templates = map[string]messageTemplate{
+	KindShare: parse(
+		`{{.sharedBy}} shared "{{.title}}" with you`,
+		`Hello,
+
+{{.sharedBy}} gave you {{.role}} access to the document "{{.title}}".
+{{- if .expiresAt}}
+Your access expires at {{.expiresAt}}.{{end}}
+`),
+	KindMention: parse(
+		`{{.by}} mentioned you in "{{.title}}"`,
+		`Hello,
+
+{{.by}} mentioned you in a comment on "{{.title}}":
+
+{{.text}}
+`),
+	KindComment: parse(
+		`New comment on "{{.title}}"`,
+		`Hello,
+
+{{.by}} commented on "{{.title}}":
+
+{{.text}}
+`),
+	KindAccessRequest: parse(
+		`{{.requester}} is requesting access to "{{.title}}"`,
+		`Hello,
+
+{{.requester}} asked for {{.role}} access to "{{.title}}".
+{{- if .message}}
+
+"{{.message}}"{{end}}
+`),
+	KindAccessApproved: parse(
+		`Your request for "{{.title}}" was approved`,
+		`Hello,
+
+{{.decidedBy}} approved your request. You now have {{.role}} access to "{{.title}}".
+`),
+	KindAccessDenied: parse(
+		`Your request for "{{.title}}" was denied`,
+		`Hello,
+
+{{.decidedBy}} denied your request for access to "{{.title}}".
+{{- if .reason}}
+
+Reason: {{.reason}}{{end}}
+`),
+}
+
+func parse(subject, body string) messageTemplate {
+	return messageTemplate{
+		subject: template.Must(template.New("subject").Option("missingkey=zero").Parse(subject)),
+		body:    template.Must(template.New("body").Option("missingkey=zero").Parse(body)),
+	}
+}
+
+// Render builds the message of the given kind for recipient to.
+func Render(kind, to string, data map[string]string) (Message, error) {
+	t, ok := templates[kind]
+	if !ok {
+		return Message{}, fmt.Errorf("notification kind '%s' has no template", kind)
+	}
+
+	var subject, body strings.Builder
+	if err := t.subject.Execute(&subject, data); err != nil {
+		return Message{}, err
+	}
+	if err := t.body.Execute(&body, data); err != nil {
+		return Message{}, err
+	}
+
+	return Message{To: to, Subject: subject.String(), Body: body.String()}, nil
+}
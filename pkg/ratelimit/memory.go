@@ -0,0 +1,71 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often the memory store drops buckets that have
+// refilled, which are no different from missing ones.
+const sweepInterval = time.Minute
+
+// MemoryStore keeps the buckets in process. Every server instance limits on
+// its own.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*memoryBucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type memoryBucket struct {
+	bucket
+	limit Limit
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: map[string]*memoryBucket{}, now: time.Now}
+}
+
+func (s *MemoryStore) Take(ctx context.Context, key string, limit Limit) (bool, time.Duration, error) {
+	return s.TakeAll(ctx, []Bucket{{Key: key, Limit: limit}})
+}
+
+func (s *MemoryStore) TakeAll(_ context.Context, buckets []Bucket) (bool, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.Sub(s.lastSweep) > sweepInterval {
+		for k, b := range s.buckets {
+			if b.full(now, b.limit) {
+				delete(s.buckets, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	taken := make([]*memoryBucket, len(buckets))
+	var wait time.Duration
+	for i, spec := range buckets {
+		b, ok := s.buckets[spec.Key]
+		if !ok {
+			b = &memoryBucket{bucket: bucket{tokens: float64(spec.Limit.Burst), updated: now}}
+			s.buckets[spec.Key] = b
+		}
+		b.limit = spec.Limit
+		b.refill(now, spec.Limit)
+		if w := b.wait(spec.Limit); w > wait {
+			wait = w
+		}
+		taken[i] = b
+	}
+	if wait > 0 {
+		return false, wait, nil
+	}
+	for _, b := range taken {
+		b.tokens--
+	}
+	return true, 0, nil
+}
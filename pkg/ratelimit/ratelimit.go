@@ -0,0 +1,120 @@
+// Package ratelimit implements token-bucket rate limits over a pluggable
+// store, so several server instances can share their buckets.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit allows Rate requests per second on average and bursts of up to Burst
+// requests. A zero Rate means unlimited.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Unlimited reports whether l lets everything through.
+func (l Limit) Unlimited() bool {
+	return l.Rate <= 0
+}
+
+// Store keeps one token bucket per key.
+type Store interface {
+	// Take removes a token from the bucket of key. When the bucket is empty it
+	// returns false and how long until the next token is available.
+	Take(ctx context.Context, key string, limit Limit) (bool, time.Duration, error)
+	// TakeAll removes a token from every bucket, or from none when one of
+	// them is empty. Then it returns false and the longest wait.
+	TakeAll(ctx context.Context, buckets []Bucket) (bool, time.Duration, error)
+}
+
+// Bucket names one bucket of a TakeAll.
+type Bucket struct {
+	Key   string
+	Limit Limit
+}
+
+// ParseLimits reads a list like "UpdateDocument=2:10,CreateDocument=1:5",
+// where each entry is method=rate:burst. A missing burst defaults to the
+// rate rounded up.
+func ParseLimits(s string) (map[string]Limit, error) {
+	limits := map[string]Limit{}
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		method, spec, ok := strings.Cut(entry, "=")
+		if !ok || method == "" {
+			return nil, fmt.Errorf("rate limit '%s' is not method=rate:burst", entry)
+		}
+		limit, err := parseLimit(spec)
+		if err != nil {
+			return nil, fmt.Errorf("rate limit '%s': %w", entry, err)
+		}
+		limits[strings.TrimSpace(method)] = limit
+	}
+	return limits, nil
+}
+
+func parseLimit(spec string) (Limit, error) {
+	rateSpec, burstSpec, hasBurst := strings.Cut(strings.TrimSpace(spec), ":")
+	rate, err := strconv.ParseFloat(rateSpec, 64)
+	if err != nil || rate < 0 {
+		return Limit{}, fmt.Errorf("rate '%s' is not a non-negative number", rateSpec)
+	}
+	burst := int(math.Ceil(rate))
+	if hasBurst {
+		burst, err = strconv.Atoi(burstSpec)
+		if err != nil || burst < 1 {
+			return Limit{}, fmt.Errorf("burst '%s' is not a positive number", burstSpec)
+		}
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return Limit{Rate: rate, Burst: burst}, nil
+}
+
+// bucket is the state of one token bucket.
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// take refills b up to now and removes a token if there is one.
+func (b *bucket) take(now time.Time, limit Limit) (bool, time.Duration) {
+	b.refill(now, limit)
+	if wait := b.wait(limit); wait > 0 {
+		return false, wait
+	}
+	b.tokens--
+	return true, 0
+}
+
+// refill adds the tokens earned since b was last updated.
+func (b *bucket) refill(now time.Time, limit Limit) {
+	if elapsed := now.Sub(b.updated).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(float64(limit.Burst), b.tokens+elapsed*limit.Rate)
+	}
+	b.updated = now
+}
+
+// wait is how long until b has a token, 0 if it has one now.
+func (b *bucket) wait(limit Limit) time.Duration {
+	if b.tokens >= 1 {
+		return 0
+	}
+	wait := (1 - b.tokens) / limit.Rate
+	return time.Duration(math.Ceil(wait * float64(time.Second)))
+}
+
+// full reports whether b has refilled completely by now.
+func (b *bucket) full(now time.Time, limit Limit) bool {
+	return b.tokens+now.Sub(b.updated).Seconds()*limit.Rate >= float64(limit.Burst)
+}
@@ -0,0 +1,77 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMemoryStore tests bursts, refill and the reported wait.
+func TestMemoryStore(t *testing.T) {
+	now := time.Unix(1000, 0)
+	s := NewMemoryStore()
+	s.now = func() time.Time { return now }
+	limit := Limit{Rate: 2, Burst: 3}
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		ok, _, err := s.Take(ctx, "u1:UpdateDocument", limit)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	}
+	ok, wait, _ := s.Take(ctx, "u1:UpdateDocument", limit)
+	assert.False(t, ok)
+	assert.Equal(t, 500*time.Millisecond, wait)
+
+	ok, _, _ = s.Take(ctx, "u2:UpdateDocument", limit)
+	assert.True(t, ok, "buckets are per key")
+
+	now = now.Add(500 * time.Millisecond)
+	ok, _, _ = s.Take(ctx, "u1:UpdateDocument", limit)
+	assert.True(t, ok)
+
+	now = now.Add(time.Hour)
+	s.Take(ctx, "u3:UpdateDocument", limit)
+	assert.Len(t, s.buckets, 1, "refilled buckets are swept")
+}
+
+// TestTakeAll tests that a TakeAll takes from every bucket or from none.
+func TestTakeAll(t *testing.T) {
+	now := time.Unix(1000, 0)
+	s := NewMemoryStore()
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+	user := Bucket{Key: "u1:UpdateDocument", Limit: Limit{Rate: 1, Burst: 1}}
+	address := Bucket{Key: "10.0.0.1", Limit: Limit{Rate: 1, Burst: 2}}
+
+	ok, _, err := s.TakeAll(ctx, []Bucket{user, address})
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, wait, _ := s.TakeAll(ctx, []Bucket{user, address})
+	assert.False(t, ok)
+	assert.Equal(t, time.Second, wait)
+
+	ok, _, _ = s.Take(ctx, address.Key, address.Limit)
+	assert.True(t, ok, "the rejected call took no token from the address")
+}
+
+// TestParseLimits tests the method=rate:burst list format.
+func TestParseLimits(t *testing.T) {
+	limits, err := ParseLimits("UpdateDocument=2:10, CreateDocument=0.5,GetDocument=0")
+	assert.NoError(t, err)
+	assert.Equal(t, Limit{Rate: 2, Burst: 10}, limits["UpdateDocument"])
+	assert.Equal(t, Limit{Rate: 0.5, Burst: 1}, limits["CreateDocument"])
+	assert.True(t, limits["GetDocument"].Unlimited())
+
+	limits, err = ParseLimits("")
+	assert.NoError(t, err)
+	assert.Empty(t, limits)
+
+	for _, bad := range []string{"UpdateDocument", "=1:2", "UpdateDocument=x", "UpdateDocument=1:0", "UpdateDocument=-1"} {
+		_, err := ParseLimits(bad)
+		assert.Error(t, err, bad)
+	}
+}
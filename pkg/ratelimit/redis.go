@@ -0,0 +1,80 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// takeScript refills the buckets stored as hashes under KEYS and takes a
+// token from each, or from none when one is empty, in one atomic step. ARGV
+// holds the rate and burst of every key in turn. It reads the clock of the
+// Redis server, so server instances with skewed clocks still agree. It
+// returns {allowed, wait in milliseconds}.
+var takeScript = redis.NewScript(`
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+
+local tokens, wait = {}, 0
+for i, key in ipairs(KEYS) do
+  local rate = tonumber(ARGV[2 * i - 1])
+  local burst = tonumber(ARGV[2 * i])
+  local state = redis.call('HMGET', key, 'tokens', 'updated')
+  local left = tonumber(state[1]) or burst
+  local updated = tonumber(state[2]) or now
+  if now > updated then
+    left = math.min(burst, left + (now - updated) * rate / 1000)
+  end
+  if left < 1 then
+    wait = math.max(wait, math.ceil((1 - left) * 1000 / rate))
+  end
+  tokens[i] = left
+end
+
+local allowed = 0
+if wait == 0 then
+  allowed = 1
+end
+for i, key in ipairs(KEYS) do
+  local rate = tonumber(ARGV[2 * i - 1])
+  local burst = tonumber(ARGV[2 * i])
+  if allowed == 1 then
+    tokens[i] = tokens[i] - 1
+  end
+  redis.call('HSET', key, 'tokens', tostring(tokens[i]), 'updated', now)
+  redis.call('PEXPIRE', key, math.ceil(burst * 1000 / rate) + 1000)
+end
+return {allowed, wait}
+`)
+
+// RedisStore keeps the buckets in Redis, or anything speaking its protocol
+// and scripting, so all server instances share one limit. On a Redis
+// Cluster the keys of one TakeAll must hash to the same slot.
+type RedisStore struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedisStore stores buckets under keys starting with prefix.
+func NewRedisStore(client redis.UniversalClient, prefix string) *RedisStore {
+	return &RedisStore{client: client, prefix: prefix}
+}
+
+func (s *RedisStore) Take(ctx context.Context, key string, limit Limit) (bool, time.Duration, error) {
+	return s.TakeAll(ctx, []Bucket{{Key: key, Limit: limit}})
+}
+
+func (s *RedisStore) TakeAll(ctx context.Context, buckets []Bucket) (bool, time.Duration, error) {
+	keys := make([]string, len(buckets))
+	args := make([]interface{}, 0, 2*len(buckets))
+	for i, b := range buckets {
+		keys[i] = s.prefix + b.Key
+		args = append(args, b.Limit.Rate, b.Limit.Burst)
+	}
+	res, err := takeScript.Run(ctx, s.client, keys, args...).Int64Slice()
+	if err != nil {
+		return false, 0, err
+	}
+	return res[0] == 1, time.Duration(res[1]) * time.Millisecond, nil
+}
@@ -0,0 +1,269 @@
+// Package richtext is the structured document model. Documents are stored as
+// a tree of blocks with inline runs and can be flattened to the legacy plain
+// string for clients that only know the content field.
+package richtext
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// SchemaVersion is the tree layout written by this package.
+const SchemaVersion = 1
+
+const (
+	Paragraph = "paragraph"
+	Heading   = "heading"
+	List      = "list"
+	Table     = "table"
+	Image     = "image"
+)
+
+// AttachmentScheme prefixes references to uploaded attachments. An image src
+// or a link of "attachment:<id>" points at the attachment with that id.
+const AttachmentScheme = "attachment:"
+
+// maxBlocks bounds the size of a single document.
+const maxBlocks = 10000
+
+type Document struct {
+	Version int     `json:"version" bson:"version"`
+	Blocks  []Block `json:"blocks" bson:"blocks"`
+}
+
+// Block is one top-level element. Which fields are used depends on Type:
+// paragraphs and headings have Inlines, lists have Items, tables have Rows
+// and images refer to a stored blob by Src.
+type Block struct {
+	Type    string     `json:"type" bson:"type"`
+	Level   int        `json:"level,omitempty" bson:"level,omitempty"`
+	Ordered bool       `json:"ordered,omitempty" bson:"ordered,omitempty"`
+	Inlines []Inline   `json:"inlines,omitempty" bson:"inlines,omitempty"`
+	Items   [][]Inline `json:"items,omitempty" bson:"items,omitempty"`
+	Rows    [][]Cell   `json:"rows,omitempty" bson:"rows,omitempty"`
+	Src     string     `json:"src,omitempty" bson:"src,omitempty"`
+	Alt     string     `json:"alt,omitempty" bson:"alt,omitempty"`
+}
+
+type Cell []Inline
+
+type Inline struct {
+	Text   string `json:"text" bson:"text"`
+	Bold   bool   `json:"bold,omitempty" bson:"bold,omitempty"`
+	Italic bool   `json:"italic,omitempty" bson:"italic,omitempty"`
+	Link   string `json:"link,omitempty" bson:"link,omitempty"`
+}
+
+// Validate checks a document before it is written.
+func Validate(doc *Document) error {
+	if doc.Version != SchemaVersion {
+		return fmt.Errorf("rich text version %d is not supported", doc.Version)
+	}
+	if len(doc.Blocks) > maxBlocks {
+		return fmt.Errorf("rich text has %d blocks, at most %d are allowed", len(doc.Blocks), maxBlocks)
+	}
+
+	for i, b := range doc.Blocks {
+		if err := validateBlock(b); err != nil {
+			return fmt.Errorf("block %d: %v", i, err)
+		}
+	}
+	return nil
+}
+
+func validateBlock(b Block) error {
+	switch b.Type {
+	case Paragraph:
+		return validateInlines(b.Inlines)
+	case Heading:
+		if b.Level < 1 || b.Level > 6 {
+			return fmt.Errorf("heading level %d must be between 1 and 6", b.Level)
+		}
+		return validateInlines(b.Inlines)
+	case List:
+		if len(b.Items) == 0 {
+			return fmt.Errorf("list has no items")
+		}
+		for _, item := range b.Items {
+			if err := validateInlines(item); err != nil {
+				return err
+			}
+		}
+	case Table:
+		if len(b.Rows) == 0 {
+			return fmt.Errorf("table has no rows")
+		}
+		for i, row := range b.Rows {
+			if len(row) != len(b.Rows[0]) {
+				return fmt.Errorf("table row %d has %d cells, expected %d", i, len(row), len(b.Rows[0]))
+			}
+			for _, cell := range row {
+				if err := validateInlines(cell); err != nil {
+					return err
+				}
+			}
+		}
+	case Image:
+		if b.Src == "" {
+			return fmt.Errorf("image has no src")
+		}
+	default:
+		return fmt.Errorf("block type '%s' is not supported", b.Type)
+	}
+	return nil
+}
+
+func validateInlines(inlines []Inline) error {
+	for _, in := range inlines {
+		if in.Link == "" || strings.HasPrefix(in.Link, AttachmentScheme) {
+			continue
+		}
+		u, err := url.Parse(in.Link)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "mailto") {
+			return fmt.Errorf("link '%s' is not allowed", in.Link)
+		}
+	}
+	return nil
+}
+
+// Attachments returns the ids of the attachments doc refers to, each once.
+// A nil doc refers to none.
+func Attachments(doc *Document) []string {
+	if doc == nil {
+		return nil
+	}
+	var ids []string
+	seen := map[string]bool{}
+	add := func(ref string) {
+		id := strings.TrimPrefix(ref, AttachmentScheme)
+		if id == ref || id == "" || seen[id] {
+			return
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	addInlines := func(inlines []Inline) {
+		for _, in := range inlines {
+			add(in.Link)
+		}
+	}
+
+	for _, b := range doc.Blocks {
+		add(b.Src)
+		addInlines(b.Inlines)
+		for _, item := range b.Items {
+			addInlines(item)
+		}
+		for _, row := range b.Rows {
+			for _, cell := range row {
+				addInlines(cell)
+			}
+		}
+	}
+	return ids
+}
+
+// RewriteAttachments returns a copy of doc whose references to the
+// attachments in ids point to the attachment ids map to instead. Other
+// references are kept.
+func RewriteAttachments(doc *Document, ids map[string]string) *Document {
+	rewrite := func(ref string) string {
+		if id, ok := ids[strings.TrimPrefix(ref, AttachmentScheme)]; ok && strings.HasPrefix(ref, AttachmentScheme) {
+			return AttachmentScheme + id
+		}
+		return ref
+	}
+	inlines := func(in []Inline) []Inline {
+		if in == nil {
+			return nil
+		}
+		out := make([]Inline, len(in))
+		for i, inline := range in {
+			inline.Link = rewrite(inline.Link)
+			out[i] = inline
+		}
+		return out
+	}
+
+	res := &Document{Version: doc.Version, Blocks: make([]Block, len(doc.Blocks))}
+	for i, b := range doc.Blocks {
+		b.Src = rewrite(b.Src)
+		b.Inlines = inlines(b.Inlines)
+		if b.Items != nil {
+			items := make([][]Inline, len(b.Items))
+			for j, item := range b.Items {
+				items[j] = inlines(item)
+			}
+			b.Items = items
+		}
+		if b.Rows != nil {
+			rows := make([][]Cell, len(b.Rows))
+			for j, row := range b.Rows {
+				rows[j] = make([]Cell, len(row))
+				for k, cell := range row {
+					rows[j][k] = inlines(cell)
+				}
+			}
+			b.Rows = rows
+		}
+		res.Blocks[i] = b
+	}
+	return res
+}
+
+// FromPlain turns a legacy content string into a document: every run of text
+// between blank lines becomes a paragraph.
+func FromPlain(content string) *Document {
+	doc := &Document{Version: SchemaVersion}
+	for _, p := range strings.Split(content, "\n\n") {
+		if p == "" {
+			continue
+		}
+		doc.Blocks = append(doc.Blocks, Block{Type: Paragraph, Inlines: []Inline{{Text: p}}})
+	}
+	return doc
+}
+
+// ToPlain flattens a document for clients that only read content. Formatting
+// is dropped; lists, tables and images keep a readable text form.
+func ToPlain(doc *Document) string {
+	blocks := make([]string, 0, len(doc.Blocks))
+	for _, b := range doc.Blocks {
+		switch b.Type {
+		case Paragraph, Heading:
+			blocks = append(blocks, text(b.Inlines))
+		case List:
+			lines := make([]string, len(b.Items))
+			for i, item := range b.Items {
+				marker := "-"
+				if b.Ordered {
+					marker = fmt.Sprintf("%d.", i+1)
+				}
+				lines[i] = marker + " " + text(item)
+			}
+			blocks = append(blocks, strings.Join(lines, "\n"))
+		case Table:
+			lines := make([]string, len(b.Rows))
+			for i, row := range b.Rows {
+				cells := make([]string, len(row))
+				for j, cell := range row {
+					cells[j] = text(cell)
+				}
+				lines[i] = strings.Join(cells, "\t")
+			}
+			blocks = append(blocks, strings.Join(lines, "\n"))
+		case Image:
+			blocks = append(blocks, "[image: "+b.Alt+"]")
+		}
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+func text(inlines []Inline) string {
+	var sb strings.Builder
+	for _, in := range inlines {
+		sb.WriteString(in.Text)
+	}
+	return sb.String()
+}
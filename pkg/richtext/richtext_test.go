@@ -0,0 +1,72 @@
+package richtext
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPlainRoundTrip tests that legacy content survives the conversion.
+func TestPlainRoundTrip(t *testing.T) {
+	for _, content := range []string{"", "one line", "first\nstill first\n\nsecond"} {
+		doc := FromPlain(content)
+		assert.NoError(t, Validate(doc))
+		assert.Equal(t, content, ToPlain(doc))
+	}
+	assert.Len(t, FromPlain("a\n\nb").Blocks, 2)
+}
+
+// TestToPlain tests flattening of every block type.
+func TestToPlain(t *testing.T) {
+	doc := &Document{Version: SchemaVersion, Blocks: []Block{
+		{Type: Heading, Level: 1, Inlines: []Inline{{Text: "Plan"}}},
+		{Type: Paragraph, Inlines: []Inline{{Text: "Read "}, {Text: "this", Bold: true, Link: "https://example.com"}}},
+		{Type: List, Ordered: true, Items: [][]Inline{{{Text: "a"}}, {{Text: "b"}}}},
+		{Type: Table, Rows: [][]Cell{{{{Text: "x"}}, {{Text: "y"}}}}},
+		{Type: Image, Src: "blob-1", Alt: "chart"},
+	}}
+	assert.NoError(t, Validate(doc))
+	assert.Equal(t, "Plan\n\nRead this\n\n1. a\n2. b\n\nx\ty\n\n[image: chart]", ToPlain(doc))
+}
+
+// TestValidate tests that malformed trees are rejected.
+func TestValidate(t *testing.T) {
+	bad := []*Document{
+		{Version: 2},
+		{Version: SchemaVersion, Blocks: []Block{{Type: "video"}}},
+		{Version: SchemaVersion, Blocks: []Block{{Type: Heading, Level: 7}}},
+		{Version: SchemaVersion, Blocks: []Block{{Type: List}}},
+		{Version: SchemaVersion, Blocks: []Block{{Type: Image}}},
+		{Version: SchemaVersion, Blocks: []Block{{Type: Table, Rows: [][]Cell{{{}, {}}, {{}}}}}},
+		{Version: SchemaVersion, Blocks: []Block{{Type: Paragraph, Inlines: []Inline{{Text: "x", Link: "javascript:alert(1)"}}}}},
+	}
+	for _, doc := range bad {
+		assert.Error(t, Validate(doc))
+	}
+}
+
+// TestAttachments tests collecting attachment references.
+func TestAttachments(t *testing.T) {
+	doc := &Document{Version: SchemaVersion, Blocks: []Block{
+		{Type: Image, Src: "attachment:a1"},
+		{Type: Image, Src: "https://example.com/x.png"},
+		{Type: Paragraph, Inlines: []Inline{{Text: "spec", Link: "attachment:a2"}, {Text: "again", Link: "attachment:a1"}}},
+		{Type: Table, Rows: [][]Cell{{{{Text: "c", Link: "attachment:a3"}}}}},
+	}}
+	assert.NoError(t, Validate(doc))
+	assert.Equal(t, []string{"a1", "a2", "a3"}, Attachments(doc))
+}
+
+// TestRewriteAttachments tests pointing references at other attachments.
+func TestRewriteAttachments(t *testing.T) {
+	doc := &Document{Version: SchemaVersion, Blocks: []Block{
+		{Type: Image, Src: "attachment:a1"},
+		{Type: Paragraph, Inlines: []Inline{{Text: "spec", Link: "attachment:a2"}, {Text: "site", Link: "https://example.com/a1"}}},
+	}}
+
+	res := RewriteAttachments(doc, map[string]string{"a1": "b1"})
+
+	assert.Equal(t, []string{"b1", "a2"}, Attachments(res))
+	assert.Equal(t, "https://example.com/a1", res.Blocks[1].Inlines[1].Link)
+	assert.Equal(t, []string{"a1", "a2"}, Attachments(doc))
+}
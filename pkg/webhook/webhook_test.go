@@ -0,0 +1,45 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPost tests delivery against a local stub that checks the signature.
+func TestPost(t *testing.T) {
+	var got http.Header
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got = r.Header
+		if !Verify("s3cret", body, r.Header.Get(SignatureHeader)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer stub.Close()
+
+	req := Request{URL: stub.URL, Secret: "s3cret", DeliveryId: "d1", Event: "updated", Body: []byte(`{"event":"updated"}`)}
+	code, err := Post(context.Background(), stub.Client(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, code)
+	assert.Equal(t, "updated", got.Get(EventHeader))
+	assert.Equal(t, "d1", got.Get(DeliveryHeader))
+
+	req.Secret = "wrong"
+	code, err = Post(context.Background(), stub.Client(), req)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusUnauthorized, code)
+}
+
+// TestVerify tests signing round trips.
+func TestVerify(t *testing.T) {
+	body := []byte("payload")
+	assert.True(t, Verify("k", body, Sign("k", body)))
+	assert.False(t, Verify("k", []byte("other"), Sign("k", body)))
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	pb "mainService/genproto/doccs"
+)
+
+func (s *Service) RequestAccess(ctx context.Context, req *pb.RequestAccessReq) (*pb.RequestAccessRes, error) {
+	s.logger.Debug("RequestAccess", "req", req)
+	email, err := s.emailOf(ctx, req.UserId)
+	if err != nil {
+		s.logger.Error("RequestAccess", "err", err)
+		return nil, err
+	}
+	res, err := s.accessRequests.RequestAccess(ctx, req, email)
+	if err != nil {
+		s.logger.Error("RequestAccess", "err", err)
+		return nil, err
+	}
+	s.logger.Debug("RequestAccess", "res", res)
+	return res, nil
+}
+
+func (s *Service) ListAccessRequests(ctx context.Context, req *pb.ListAccessRequestsReq) (*pb.ListAccessRequestsRes, error) {
+	s.logger.Debug("ListAccessRequests", "req", req)
+	res, err := s.accessRequests.ListAccessRequests(ctx, req)
+	if err != nil {
+		s.logger.Error("ListAccessRequests", "err", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+// ApproveAccessRequest grants the access through ShareDocument and then
+// closes the request, which notifies the requester.
+func (s *Service) ApproveAccessRequest(ctx context.Context, req *pb.ApproveAccessRequestReq) (*pb.ApproveAccessRequestRes, error) {
+	s.logger.Debug("ApproveAccessRequest", "req", req)
+	share, err := s.accessRequests.PrepareApproval(ctx, req)
+	if err != nil {
+		s.logger.Error("ApproveAccessRequest", "err", err)
+		return nil, err
+	}
+
+	if _, err := s.ShareDocument(ctx, share); err != nil {
+		return nil, err
+	}
+
+	res, err := s.accessRequests.MarkAccessRequestApproved(ctx, req, share.Permissions)
+	if err != nil {
+		s.logger.Error("ApproveAccessRequest", "err", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *Service) DenyAccessRequest(ctx context.Context, req *pb.DenyAccessRequestReq) (*pb.DenyAccessRequestRes, error) {
+	s.logger.Debug("DenyAccessRequest", "req", req)
+	res, err := s.accessRequests.DenyAccessRequest(ctx, req)
+	if err != nil {
+		s.logger.Error("DenyAccessRequest", "err", err)
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	pb "mainService/genproto/doccs"
+	"mainService/pkg/apperr"
+	"mainService/storage/mongodb"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxAttachmentSize bounds the size of a single attachment.
+const maxAttachmentSize = 50 << 20
+
+// downloadChunkSize is the size of the data in each streamed chunk.
+const downloadChunkSize = 64 << 10
+
+// UploadAttachment streams the uploaded chunks straight into the blob store.
+// The first message names the user, document and file.
+func (s *Service) UploadAttachment(stream pb.DocsService_UploadAttachmentServer) error {
+	ctx := stream.Context()
+
+	first, err := stream.Recv()
+	if err == io.EOF {
+		err = apperr.Missing("chunk")
+	}
+	if err != nil {
+		s.logger.Error("UploadAttachment", "err", err)
+		return err
+	}
+	s.logger.Debug("UploadAttachment", "userId", first.UserId, "title", first.Title, "filename", first.Filename)
+
+	docsId, title, err := s.attachments.AuthorizeUpload(ctx, first)
+	if err == nil {
+		err = s.quotas.CheckUpdateQuota(ctx, docsId, title, 0)
+	}
+	if err != nil {
+		s.logger.Error("UploadAttachment", "err", err)
+		return err
+	}
+
+	contentType := first.ContentType
+	if contentType == "" {
+		contentType = http.DetectContentType(first.Chunk)
+	}
+
+	id := uuid.NewString()
+	pr, pw := io.Pipe()
+	stored := make(chan error, 1)
+	go func() {
+		_, err := s.blobs.Put(ctx, id, pr)
+		pr.CloseWithError(err)
+		stored <- err
+	}()
+
+	hash := sha256.New()
+	var size int64
+	write := func(chunk []byte) error {
+		size += int64(len(chunk))
+		if size > maxAttachmentSize {
+			return apperr.Invalid("chunk", "attachment is larger than %d bytes", maxAttachmentSize)
+		}
+		hash.Write(chunk)
+		_, err := pw.Write(chunk)
+		return err
+	}
+
+	err = write(first.Chunk)
+	for err == nil {
+		var req *pb.UploadAttachmentReq
+		req, err = stream.Recv()
+		if err == io.EOF {
+			err = nil
+			break
+		}
+		if err == nil {
+			err = write(req.Chunk)
+		}
+	}
+	if err != nil {
+		pw.CloseWithError(err)
+		<-stored
+		s.blobs.Delete(context.WithoutCancel(ctx), id)
+		s.logger.Error("UploadAttachment", "err", err)
+		return err
+	}
+	pw.Close()
+	if err := <-stored; err != nil {
+		s.logger.Error("UploadAttachment", "err", err)
+		return err
+	}
+
+	attachment, err := s.attachments.SaveAttachment(ctx, mongodb.Attachment{
+		Id:          id,
+		DocsId:      docsId,
+		Title:       title,
+		Filename:    first.Filename,
+		ContentType: contentType,
+		Size:        size,
+		Sha256:      hex.EncodeToString(hash.Sum(nil)),
+		UploadedBy:  first.UserId,
+	})
+	if err != nil {
+		s.blobs.Delete(context.WithoutCancel(ctx), id)
+		s.logger.Error("UploadAttachment", "err", err)
+		return err
+	}
+
+	return stream.SendAndClose(&pb.UploadAttachmentRes{Attachment: attachment})
+}
+
+// DownloadAttachment streams an attachment from req.Offset on, so a broken
+// download can be resumed. The first chunk carries the metadata.
+func (s *Service) DownloadAttachment(req *pb.DownloadAttachmentReq, stream pb.DocsService_DownloadAttachmentServer) error {
+	ctx := stream.Context()
+	s.logger.Debug("DownloadAttachment", "req", req)
+
+	attachment, blob, err := s.attachments.AuthorizeDownload(ctx, req)
+	if err != nil {
+		s.logger.Error("DownloadAttachment", "err", err)
+		return err
+	}
+
+	r, err := s.blobs.Get(ctx, blob)
+	if err != nil {
+		s.logger.Error("DownloadAttachment", "err", err)
+		return err
+	}
+	defer r.Close()
+
+	offset := req.Offset
+	if offset < 0 || offset > attachment.Size {
+		err = apperr.Invalid("offset", "offset %d is outside the attachment of %d bytes", offset, attachment.Size)
+		s.logger.Error("DownloadAttachment", "err", err)
+		return err
+	}
+	if err := skip(r, offset); err != nil {
+		s.logger.Error("DownloadAttachment", "err", err)
+		return err
+	}
+
+	buf := make([]byte, downloadChunkSize)
+	chunk := &pb.AttachmentChunk{Attachment: attachment}
+	for {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 || chunk.Attachment != nil {
+			chunk.Data = buf[:n]
+			chunk.Offset = offset
+			if err := stream.Send(chunk); err != nil {
+				return err
+			}
+			offset += int64(n)
+			chunk = &pb.AttachmentChunk{}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil
+		}
+		if err != nil {
+			s.logger.Error("DownloadAttachment", "err", err)
+			return err
+		}
+	}
+}
+
+// skip advances r by n bytes, seeking when the store allows it.
+func skip(r io.Reader, n int64) error {
+	if n == 0 {
+		return nil
+	}
+	if seeker, ok := r.(io.Seeker); ok {
+		_, err := seeker.Seek(n, io.SeekStart)
+		return err
+	}
+	_, err := io.CopyN(io.Discard, r, n)
+	return err
+}
+
+func (s *Service) ListAttachments(ctx context.Context, req *pb.ListAttachmentsReq) (*pb.ListAttachmentsRes, error) {
+	s.logger.Debug("ListAttachments", "req", req)
+	res, err := s.attachments.ListAttachments(ctx, req)
+	if err != nil {
+		s.logger.Error("ListAttachments", "err", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *Service) PurgeDocument(ctx context.Context, req *pb.PurgeDocumentReq) (*pb.PurgeDocumentRes, error) {
+	s.logger.Debug("PurgeDocument", "req", req)
+	res, err := s.repo.PurgeDocument(ctx, req)
+	if err != nil {
+		s.logger.Error("PurgeDocument", "err", err)
+		return nil, err
+	}
+	s.logger.Debug("PurgeDocument", "res", res)
+	return res, nil
+}
+
+// RunAttachmentSweeper deletes orphaned attachments every interval until ctx
+// is done. Uploads younger than grace are kept.
+func (s *Service) RunAttachmentSweeper(ctx context.Context, interval, grace time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.sweepAttachments(ctx, grace)
+		}
+	}
+}
+
+func (s *Service) sweepAttachments(ctx context.Context, grace time.Duration) {
+	for {
+		id, err := s.attachments.ClaimOrphanAttachment(ctx, grace)
+		if err != nil {
+			s.logger.Error("ClaimOrphanAttachment", "err", err)
+			return
+		}
+		if id == "" {
+			return
+		}
+		if err := s.blobs.Delete(ctx, id); err != nil {
+			s.logger.Error("DeleteBlob", "id", id, "err", err)
+			continue
+		}
+		s.logger.Debug("SweepAttachments", "deleted", id)
+	}
+}
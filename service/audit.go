@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"net"
+	"path"
+
+	pb "mainService/genproto/doccs"
+	"mainService/storage/mongodb"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// requestIdHeader carries the request id in and out of every call.
+const requestIdHeader = "x-request-id"
+
+// auditActions maps the audited RPCs to their audit action. Calls to other
+// RPCs are not audited.
+var auditActions = map[string]string{
+	"CreateDocument":        "create",
+	"CopyDocument":          "create",
+	"GetDocument":           "read",
+	"SearchDocument":        "read",
+	"GetAllDocuments":       "read",
+	"GetAllVersions":        "read",
+	"ResolveShareLink":      "read",
+	"UpdateDocument":        "update",
+	"AcceptSuggestion":      "update",
+	"SaveVersion":           "update",
+	"DeleteDocument":        "delete",
+	"PurgeDocument":         "delete",
+	"ShareDocument":         "share",
+	"UnshareDocument":       "share",
+	"CreateShareLink":       "share",
+	"RevokeShareLink":       "share",
+	"ApproveAccessRequest":  "share",
+	"TransferOwnership":     "transfer_ownership",
+	"AddWorkspaceMember":    "share",
+	"RemoveWorkspaceMember": "share",
+	"RestoreVersion":        "restore",
+	"DownloadDocument":      "download",
+	"BulkDocuments":         "bulk",
+	"StreamDocument":        "download",
+	"DownloadAttachment":    "download",
+	"UploadAttachment":      "upload",
+	"ImportDocument":        "create",
+	"WatchDocument":         "read",
+}
+
+// bulkAuditActions is the audit action of one document of a bulk operation.
+var bulkAuditActions = map[string]string{
+	BulkMove:   "update",
+	BulkTag:    "update",
+	BulkUntag:  "update",
+	BulkDelete: "delete",
+	BulkShare:  "share",
+	BulkExport: "download",
+}
+
+// requestIdKey holds the request id of a call in its context, so entries
+// written for parts of one call share it.
+type requestIdKey struct{}
+
+func (s *Service) ListAuditLog(ctx context.Context, req *pb.ListAuditLogReq) (*pb.ListAuditLogRes, error) {
+	s.logger.Debug("ListAuditLog", "req", req)
+	res, err := s.audit.ListAuditLog(ctx, req)
+	if err != nil {
+		s.logger.Error("ListAuditLog", "err", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+// AuditInterceptor writes an audit entry for every audited call, failed
+// ones included, and echoes the request id back in the response header.
+func (s *Service) AuditInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		requestId := requestIdOf(ctx)
+		grpc.SetHeader(ctx, metadata.Pairs(requestIdHeader, requestId))
+		ctx = context.WithValue(ctx, requestIdKey{}, requestId)
+
+		res, err := handler(ctx, req)
+
+		method := path.Base(info.FullMethod)
+		if action, ok := auditActions[method]; ok {
+			entry := auditEntryOf(req)
+			entry.Action = action
+			entry.Method = method
+			auditCreated(&entry, res)
+			s.recordAudit(ctx, entry, err)
+		}
+
+		return res, err
+	}
+}
+
+// AuditStreamInterceptor is AuditInterceptor for streaming calls. The entry
+// is written when the stream ends and is filled from its first request
+// message, which carries the ids for client streams too.
+func (s *Service) AuditStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := ss.Context()
+		requestId := requestIdOf(ctx)
+		ss.SetHeader(metadata.Pairs(requestIdHeader, requestId))
+		ctx = context.WithValue(ctx, requestIdKey{}, requestId)
+
+		stream := &auditedStream{ServerStream: ss, ctx: ctx}
+		err := handler(srv, stream)
+
+		method := path.Base(info.FullMethod)
+		if action, ok := auditActions[method]; ok {
+			entry := auditEntryOf(stream.first)
+			entry.Action = action
+			entry.Method = method
+			auditCreated(&entry, stream.sent)
+			s.recordAudit(ctx, entry, err)
+		}
+
+		return err
+	}
+}
+
+// auditedStream remembers the first message received on a stream and the
+// last one sent.
+type auditedStream struct {
+	grpc.ServerStream
+	ctx   context.Context
+	first interface{}
+	sent  interface{}
+}
+
+func (s *auditedStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *auditedStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil && s.first == nil {
+		s.first = m
+	}
+	return err
+}
+
+func (s *auditedStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.sent = m
+	}
+	return err
+}
+
+// recordAudit completes entry with the call details and the outcome err and
+// writes it. A failed write is logged, it never fails the call.
+func (s *Service) recordAudit(ctx context.Context, entry mongodb.AuditEntry, err error) {
+	entry.ClientIp = clientIpOf(ctx)
+	entry.RequestId = requestIdOf(ctx)
+	entry.Status = mongodb.AuditSucceeded
+	if err != nil {
+		entry.Status = mongodb.AuditFailed
+		entry.Error = err.Error()
+	}
+	if rerr := s.audit.RecordAudit(context.WithoutCancel(ctx), entry); rerr != nil {
+		s.logger.Error("RecordAudit", "err", rerr, "method", entry.Method)
+	}
+}
+
+// auditEntryOf fills actor, document and target from the request fields.
+// ShareDocument names the recipient in UserId, so there GrantedBy is the actor.
+func auditEntryOf(req interface{}) mongodb.AuditEntry {
+	var entry mongodb.AuditEntry
+
+	if r, ok := req.(interface{ GetGrantedBy() string }); ok {
+		entry.Actor = r.GetGrantedBy()
+		if r, ok := req.(interface{ GetUserId() string }); ok {
+			entry.Target = r.GetUserId()
+		}
+	} else if r, ok := req.(interface{ GetUserId() string }); ok {
+		entry.Actor = r.GetUserId()
+	} else if r, ok := req.(interface{ GetAuthorId() string }); ok {
+		entry.Actor = r.GetAuthorId()
+	}
+
+	if r, ok := req.(interface{ GetDocsId() string }); ok {
+		entry.DocsId = r.GetDocsId()
+	}
+	if r, ok := req.(interface{ GetTitle() string }); ok {
+		entry.Title = r.GetTitle()
+	}
+
+	switch r := req.(type) {
+	case *pb.TransferOwnershipReq:
+		entry.Target = r.NewOwnerEmail
+	case *pb.UnshareDocumentReq:
+		entry.Target = r.CollaboratorId
+	case *pb.AddWorkspaceMemberReq:
+		entry.Target = r.MemberId
+	case *pb.RemoveWorkspaceMemberReq:
+		entry.Target = r.MemberId
+	case *pb.ShareDocumentReq:
+		if entry.Target == "" {
+			entry.Target = r.RecipientEmail
+		}
+	}
+	return entry
+}
+
+// auditCreated points a "create" entry at the document the call created,
+// which only the response names: CreateDocument and ImportDocument requests
+// carry no docs id and CopyDocument requests name the source.
+func auditCreated(entry *mongodb.AuditEntry, res interface{}) {
+	if entry.Action != "create" {
+		return
+	}
+	if r, ok := res.(interface{ GetDocsId() string }); ok && r.GetDocsId() != "" {
+		entry.DocsId = r.GetDocsId()
+	}
+	if r, ok := res.(interface{ GetTitle() string }); ok && r.GetTitle() != "" {
+		entry.Title = r.GetTitle()
+	}
+}
+
+func requestIdOf(ctx context.Context) string {
+	if id, ok := ctx.Value(requestIdKey{}).(string); ok {
+		return id
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get(requestIdHeader); len(ids) > 0 && ids[0] != "" {
+			return ids[0]
+		}
+	}
+	return uuid.NewString()
+}
+
+func clientIpOf(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
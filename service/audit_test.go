@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	pb "mainService/genproto/doccs"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+// TestAuditCreated tests that a copy is audited under the new document, not
+// the source, and that other actions keep the ids of the request.
+func TestAuditCreated(t *testing.T) {
+	audit := &fakeAudit{}
+	s := newTestService(&Service{audit: audit})
+
+	copyDocument := chainUnary([]grpc.UnaryServerInterceptor{s.AuditInterceptor()}, "CopyDocument", func(ctx context.Context, req interface{}) (interface{}, error) {
+		return &pb.CopyDocumentRes{DocsId: "personal", Title: "t (copy)", AuthorId: "u1"}, nil
+	})
+	_, err := copyDocument(context.Background(), &pb.CopyDocumentReq{UserId: "u1", DocsId: "d1", Title: "t"})
+	assert.NoError(t, err)
+
+	updateDocument := chainUnary([]grpc.UnaryServerInterceptor{s.AuditInterceptor()}, "UpdateDocument", func(ctx context.Context, req interface{}) (interface{}, error) {
+		return &pb.UpdateDocumentRes{Message: "saved"}, nil
+	})
+	_, err = updateDocument(context.Background(), &pb.UpdateDocumentReq{AuthorId: "u1", DocsId: "d1", Title: "t"})
+	assert.NoError(t, err)
+
+	entries := audit.all()
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, "create", entries[0].Action)
+		assert.Equal(t, "personal", entries[0].DocsId)
+		assert.Equal(t, "t (copy)", entries[0].Title)
+		assert.Equal(t, "d1", entries[1].DocsId)
+		assert.Equal(t, "t", entries[1].Title)
+	}
+}
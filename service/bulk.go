@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	pb "mainService/genproto/doccs"
+	"mainService/pkg/apperr"
+	"mainService/pkg/exporter"
+	"mainService/storage/mongodb"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	BulkMove   = "move"
+	BulkTag    = "tag"
+	BulkUntag  = "untag"
+	BulkDelete = "delete"
+	BulkShare  = "share"
+	BulkExport = "export"
+
+	maxBulkDocuments = 500
+	bulkConcurrency  = 8
+	// maxBulkExportSize keeps an export response below gRPC's default 4MB
+	// message limit; larger documents are read with StreamDocument.
+	maxBulkExportSize = 3 << 20
+)
+
+// bulkRoles is the role an operation needs on every document. It is the
+// role the single-document call checks, so a bulk call allows no more and no
+// less.
+var bulkRoles = map[string]string{
+	BulkMove:   mongodb.RoleEditor,
+	BulkTag:    mongodb.RoleEditor,
+	BulkUntag:  mongodb.RoleEditor,
+	BulkDelete: mongodb.RoleEditor,
+	BulkShare:  mongodb.RoleEditor,
+	BulkExport: mongodb.RoleViewer,
+}
+
+// BulkDocuments applies one operation to many documents. Every document gets
+// its own result, so one failure does not stop the rest. With DryRun set only
+// the checks run and nothing is changed.
+func (s *Service) BulkDocuments(ctx context.Context, req *pb.BulkDocumentsReq) (*pb.BulkDocumentsRes, error) {
+	s.logger.Debug("BulkDocuments", "req", req)
+
+	if err := validateBulk(req); err != nil {
+		s.logger.Error("BulkDocuments", "err", err)
+		return nil, err
+	}
+
+	res := &pb.BulkDocumentsRes{
+		Results: make([]*pb.BulkItemResult, len(req.Documents)),
+		DryRun:  req.DryRun,
+	}
+	budget := &exportBudget{left: maxBulkExportSize}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, bulkConcurrency)
+	for i, ref := range req.Documents {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, ref *pb.DocumentRef) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			res.Results[i] = s.bulkItem(ctx, req, ref, budget)
+		}(i, ref)
+	}
+	wg.Wait()
+
+	for _, item := range res.Results {
+		if item.Code == codes.OK.String() {
+			res.Succeeded++
+		} else {
+			res.Failed++
+		}
+	}
+	return res, nil
+}
+
+func validateBulk(req *pb.BulkDocumentsReq) error {
+	if req.UserId == "" {
+		return apperr.Missing("user_id")
+	}
+	if _, ok := bulkRoles[req.Operation]; !ok {
+		return apperr.Invalid("operation", "operation '%s' is not supported", req.Operation)
+	}
+	if len(req.Documents) == 0 {
+		return apperr.Missing("documents")
+	}
+	if len(req.Documents) > maxBulkDocuments {
+		return apperr.Invalid("documents", "at most %d documents can be changed at once", maxBulkDocuments)
+	}
+
+	switch req.Operation {
+	case BulkTag, BulkUntag:
+		if len(req.Tags) == 0 {
+			return apperr.Missing("tags")
+		}
+	case BulkShare:
+		if req.RecipientId == "" {
+			return apperr.Missing("recipient_id")
+		}
+		if role := mongodb.NormalizeRole(req.Permissions); role == "" || role == mongodb.RoleOwner {
+			return apperr.Invalid("permissions", "permission '%s' is not supported", req.Permissions)
+		}
+	case BulkExport:
+		if !exporter.Supported(req.Format) {
+			return apperr.Invalid("format", "export format '%s' is not supported", req.Format)
+		}
+	}
+	return nil
+}
+
+func (s *Service) bulkItem(ctx context.Context, req *pb.BulkDocumentsReq, ref *pb.DocumentRef, budget *exportBudget) *pb.BulkItemResult {
+	item := &pb.BulkItemResult{DocsId: ref.DocsId, Title: ref.Title}
+
+	err := s.repo.AuthorizeDocument(ctx, req.UserId, ref.DocsId, ref.Title, bulkRoles[req.Operation])
+	if err == nil && !req.DryRun {
+		err = s.applyBulk(ctx, req, ref, item, budget)
+	}
+	if !req.DryRun {
+		s.recordAudit(ctx, mongodb.AuditEntry{
+			Actor:  req.UserId,
+			Action: bulkAuditActions[req.Operation],
+			Method: "BulkDocuments",
+			DocsId: ref.DocsId,
+			Title:  ref.Title,
+			Target: req.RecipientId,
+		}, err)
+	}
+	if err != nil {
+		s.logger.Error("BulkDocuments", "docsId", ref.DocsId, "title", ref.Title, "err", err)
+		st := status.Convert(s.statusError(ctx, "BulkDocuments", err))
+		item.Code = st.Code().String()
+		item.Message = messageOf(st)
+		item.Data = nil
+		item.ContentType = ""
+		return item
+	}
+
+	item.Code = codes.OK.String()
+	return item
+}
+
+func (s *Service) applyBulk(ctx context.Context, req *pb.BulkDocumentsReq, ref *pb.DocumentRef, item *pb.BulkItemResult, budget *exportBudget) error {
+	switch req.Operation {
+	case BulkMove:
+		return s.repo.MoveDocument(ctx, req.UserId, ref.DocsId, ref.Title, req.Folder)
+	case BulkTag, BulkUntag:
+		return s.repo.TagDocument(ctx, req.UserId, ref.DocsId, ref.Title, req.Tags, req.Operation == BulkUntag)
+	case BulkDelete:
+		_, err := s.DeleteDocument(ctx, &pb.DeleteDocumentReq{Title: ref.Title, DocsId: ref.DocsId, AuthorId: req.UserId})
+		return err
+	case BulkShare:
+		_, err := s.ShareDocument(ctx, &pb.ShareDocumentReq{
+			Title:          ref.Title,
+			DocsId:         ref.DocsId,
+			UserId:         req.RecipientId,
+			RecipientEmail: req.RecipientEmail,
+			Permissions:    req.Permissions,
+			GrantedBy:      req.UserId,
+		})
+		return err
+	case BulkExport:
+		body, err := s.repo.ReadDocument(ctx, &pb.StreamDocumentReq{UserId: req.UserId, DocsId: ref.DocsId, Title: ref.Title})
+		if err != nil {
+			return err
+		}
+		data, contentType, err := exporter.Export(body, req.Format)
+		if err != nil {
+			return err
+		}
+		if !budget.take(len(data)) {
+			return apperr.TooLarge("document", ref.Title, "export of '%s' does not fit into the response, use StreamDocument", ref.Title)
+		}
+		item.Data = data
+		item.ContentType = contentType
+	}
+	return nil
+}
+
+// exportBudget is the response space left for exported documents.
+type exportBudget struct {
+	mu   sync.Mutex
+	left int
+}
+
+func (b *exportBudget) take(n int) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if n > b.left {
+		return false
+	}
+	b.left -= n
+	return true
+}
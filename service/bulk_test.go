@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	pb "mainService/genproto/doccs"
+	"mainService/pkg/apperr"
+	"mainService/pkg/richtext"
+	"mainService/storage/mongodb"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+)
+
+// fakeDocuments lets userId at every document but those in denied, and
+// records the moves.
+type fakeDocuments struct {
+	mongodb.DocumentRepository
+	denied map[string]bool
+	size   int
+	mu     sync.Mutex
+	moved  []string
+}
+
+func (f *fakeDocuments) AuthorizeDocument(ctx context.Context, userId, docsId, title, minRole string) error {
+	if f.denied[title] {
+		return apperr.NoAccess(userId, "document", title)
+	}
+	return nil
+}
+
+func (f *fakeDocuments) MoveDocument(ctx context.Context, userId, docsId, title, folder string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.moved = append(f.moved, title)
+	return nil
+}
+
+func (f *fakeDocuments) ReadDocument(ctx context.Context, req *pb.StreamDocumentReq) (*richtext.Document, error) {
+	return richtext.FromPlain(strings.Repeat("x", f.size)), nil
+}
+
+func refs(titles ...string) []*pb.DocumentRef {
+	var documents []*pb.DocumentRef
+	for _, title := range titles {
+		documents = append(documents, &pb.DocumentRef{DocsId: "d1", Title: title})
+	}
+	return documents
+}
+
+// TestBulkDocuments tests that every document gets its own result and audit
+// entry, and that a dry run changes nothing.
+func TestBulkDocuments(t *testing.T) {
+	documents := &fakeDocuments{denied: map[string]bool{"b": true}}
+	audit := &fakeAudit{}
+	s := newTestService(&Service{repo: documents, audit: audit})
+	ctx := context.Background()
+
+	res, err := s.BulkDocuments(ctx, &pb.BulkDocumentsReq{UserId: "u1", Operation: BulkMove, Folder: "f", Documents: refs("a", "b", "c"), DryRun: true})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), res.Succeeded)
+	assert.Equal(t, int32(1), res.Failed)
+	assert.Empty(t, documents.moved)
+	assert.Empty(t, audit.all())
+
+	res, err = s.BulkDocuments(ctx, &pb.BulkDocumentsReq{UserId: "u1", Operation: BulkMove, Folder: "f", Documents: refs("a", "b", "c")})
+	assert.NoError(t, err)
+	assert.Equal(t, codes.OK.String(), res.Results[0].Code)
+	assert.Equal(t, codes.PermissionDenied.String(), res.Results[1].Code)
+	assert.NotEmpty(t, res.Results[1].Message)
+	assert.Equal(t, codes.OK.String(), res.Results[2].Code)
+	assert.ElementsMatch(t, []string{"a", "c"}, documents.moved)
+
+	entries := audit.all()
+	assert.Len(t, entries, 3)
+	failed := 0
+	for _, e := range entries {
+		assert.Equal(t, "update", e.Action)
+		if e.Status == mongodb.AuditFailed {
+			failed++
+			assert.Equal(t, "b", e.Title)
+		}
+	}
+	assert.Equal(t, 1, failed)
+
+	_, err = s.BulkDocuments(ctx, &pb.BulkDocumentsReq{UserId: "u1", Operation: "rename", Documents: refs("a")})
+	assert.Equal(t, apperr.ReasonInvalidField, apperr.ReasonOf(err))
+	_, err = s.BulkDocuments(ctx, &pb.BulkDocumentsReq{UserId: "u1", Operation: BulkTag, Documents: refs("a")})
+	assert.Equal(t, apperr.ReasonMissingField, apperr.ReasonOf(err))
+}
+
+// TestBulkExportBudget tests that exports stop once the response is full.
+func TestBulkExportBudget(t *testing.T) {
+	documents := &fakeDocuments{size: maxBulkExportSize/2 + 1}
+	s := newTestService(&Service{repo: documents})
+
+	res, err := s.BulkDocuments(context.Background(), &pb.BulkDocumentsReq{UserId: "u1", Operation: BulkExport, Format: "text", Documents: refs("a", "b", "c")})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), res.Succeeded)
+	assert.Equal(t, int32(2), res.Failed)
+	for _, item := range res.Results {
+		if item.Code == codes.OK.String() {
+			assert.NotEmpty(t, item.Data)
+		} else {
+			assert.Equal(t, codes.ResourceExhausted.String(), item.Code)
+			assert.Empty(t, item.Data)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	pb "mainService/genproto/doccs"
+	"mainService/storage/mongodb"
+	"time"
+)
+
+func (s *Service) UnshareDocument(ctx context.Context, req *pb.UnshareDocumentReq) (*pb.UnshareDocumentRes, error) {
+	s.logger.Debug("UnshareDocument", "req", req)
+	res, err := s.collaborators.UnshareDocument(ctx, req)
+	if err != nil {
+		s.logger.Error("UnshareDocument", "err", err)
+		return nil, err
+	}
+	s.publishEvent(ctx, mongodb.EventUnshared, req.DocsId, req.Title, "", req.UserId)
+	return res, nil
+}
+
+func (s *Service) ListCollaborators(ctx context.Context, req *pb.ListCollaboratorsReq) (*pb.ListCollaboratorsRes, error) {
+	s.logger.Debug("ListCollaborators", "req", req)
+	res, err := s.collaborators.ListCollaborators(ctx, req)
+	if err != nil {
+		s.logger.Error("ListCollaborators", "err", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+// RunGrantSweeper removes expired grants every interval until ctx is done.
+func (s *Service) RunGrantSweeper(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			removed, err := s.collaborators.SweepExpiredGrants(ctx)
+			if err != nil {
+				s.logger.Error("SweepExpiredGrants", "err", err)
+				continue
+			}
+			if removed > 0 {
+				s.logger.Debug("SweepExpiredGrants", "removed", removed)
+			}
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	pb "mainService/genproto/doccs"
+)
+
+func (s *Service) AddComment(ctx context.Context, req *pb.AddCommentReq) (*pb.AddCommentRes, error) {
+	s.logger.Debug("AddComment", "req", req)
+	res, err := s.comments.AddComment(ctx, req)
+	if err != nil {
+		s.logger.Error("AddComment", "err", err)
+		return nil, err
+	}
+	s.logger.Debug("AddComment", "res", res)
+	return res, nil
+}
+
+func (s *Service) ReplyComment(ctx context.Context, req *pb.ReplyCommentReq) (*pb.ReplyCommentRes, error) {
+	s.logger.Debug("ReplyComment", "req", req)
+	res, err := s.comments.ReplyComment(ctx, req)
+	if err != nil {
+		s.logger.Error("ReplyComment", "err", err)
+		return nil, err
+	}
+	s.logger.Debug("ReplyComment", "res", res)
+	return res, nil
+}
+
+func (s *Service) ResolveComment(ctx context.Context, req *pb.ResolveCommentReq) (*pb.ResolveCommentRes, error) {
+	s.logger.Debug("ResolveComment", "req", req)
+	res, err := s.comments.ResolveComment(ctx, req)
+	if err != nil {
+		s.logger.Error("ResolveComment", "err", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *Service) ReopenComment(ctx context.Context, req *pb.ReopenCommentReq) (*pb.ReopenCommentRes, error) {
+	s.logger.Debug("ReopenComment", "req", req)
+	res, err := s.comments.ReopenComment(ctx, req)
+	if err != nil {
+		s.logger.Error("ReopenComment", "err", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *Service) ListComments(ctx context.Context, req *pb.ListCommentsReq) (*pb.ListCommentsRes, error) {
+	s.logger.Debug("ListComments", "req", req)
+	res, err := s.comments.ListComments(ctx, req)
+	if err != nil {
+		s.logger.Error("ListComments", "err", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+// reanchorComments keeps comment ranges in step with a new head version.
+// The document change already succeeded, so failures are only logged.
+func (s *Service) reanchorComments(ctx context.Context, docsId, title string) {
+	if err := s.comments.ReanchorComments(ctx, docsId, title); err != nil {
+		s.logger.Error("ReanchorComments", "err", err)
+	}
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"mainService/pkg/apperr"
+	"path"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// acceptLanguageHeader picks the language of the localized error messages.
+const acceptLanguageHeader = "accept-language"
+
+// ErrorInterceptor turns the error of a call into a gRPC status. Domain
+// errors keep their code and details and get a message in the caller's
+// language; errors without a code become codes.Internal, so none reaches the
+// client as codes.Unknown. It goes first in the chain to see every error.
+func (s *Service) ErrorInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		res, err := handler(ctx, req)
+		if err != nil {
+			return nil, s.statusError(ctx, info.FullMethod, err)
+		}
+		return res, nil
+	}
+}
+
+// ErrorStreamInterceptor is ErrorInterceptor for streaming calls.
+func (s *Service) ErrorStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := handler(srv, stream); err != nil {
+			return s.statusError(stream.Context(), info.FullMethod, err)
+		}
+		return nil
+	}
+}
+
+func (s *Service) statusError(ctx context.Context, fullMethod string, err error) error {
+	var e *apperr.Error
+	if errors.As(err, &e) {
+		return e.Status(localeOf(ctx)).Err()
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if st := status.FromContextError(err); st.Code() != codes.Unknown {
+		return st.Err()
+	}
+	s.logger.Error("internal error", "method", path.Base(fullMethod), "err", err)
+	return status.Error(codes.Internal, "internal error")
+}
+
+// messageOf returns the localized message of st when it carries one, and
+// its plain message otherwise.
+func messageOf(st *status.Status) string {
+	for _, d := range st.Details() {
+		if m, ok := d.(*errdetails.LocalizedMessage); ok {
+			return m.Message
+		}
+	}
+	return st.Message()
+}
+
+func localeOf(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if langs := md.Get(acceptLanguageHeader); len(langs) > 0 {
+			return apperr.Locale(langs[0])
+		}
+	}
+	return apperr.DefaultLocale
+}
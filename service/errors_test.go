@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"mainService/pkg/apperr"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// TestStatusError tests how errors of a call reach the client.
+func TestStatusError(t *testing.T) {
+	s := newTestService(&Service{})
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(acceptLanguageHeader, "ru"))
+
+	err := s.statusError(ctx, "/doccs.DocsService/GetDocument", fmt.Errorf("reading: %w", apperr.NotFound("document", "t")))
+	st := status.Convert(err)
+	assert.Equal(t, codes.NotFound, st.Code())
+	var reason, locale string
+	for _, d := range st.Details() {
+		switch d := d.(type) {
+		case *errdetails.ErrorInfo:
+			reason = d.Reason
+		case *errdetails.LocalizedMessage:
+			locale = d.Locale
+		}
+	}
+	assert.Equal(t, apperr.ReasonNotFound, reason, "wrapped domain errors keep their details")
+	assert.Equal(t, "ru", locale)
+
+	err = s.statusError(ctx, "/doccs.DocsService/GetDocument", status.Error(codes.Unavailable, "down"))
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	err = s.statusError(ctx, "/doccs.DocsService/GetDocument", context.DeadlineExceeded)
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+
+	err = s.statusError(ctx, "/doccs.DocsService/GetDocument", errors.New("connection reset"))
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.Equal(t, "internal error", status.Convert(err).Message(), "internal details are not sent")
+}
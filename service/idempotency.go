@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"mainService/pkg/apperr"
+	"mainService/storage/mongodb"
+	"path"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+const (
+	// idempotencyKeyHeader carries the client's key for a call that may be
+	// retried; replayedHeader marks a response served from an earlier call.
+	idempotencyKeyHeader = "idempotency-key"
+	replayedHeader       = "idempotent-replayed"
+	maxIdempotencyKeyLen = 255
+)
+
+// IdempotencyInterceptor replays the stored response when a call comes again
+// with an idempotency key that has already succeeded. Keys are scoped to the
+// caller and the method. Failed calls are not stored, so their retries run
+// again. Calls without a key are passed straight through. It is chained
+// before AuditInterceptor, so only the first call is audited.
+func (s *Service) IdempotencyInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		key := idempotencyKeyOf(ctx)
+		if key == "" {
+			return handler(ctx, req)
+		}
+		if len(key) > maxIdempotencyKeyLen {
+			return nil, apperr.Invalid(idempotencyKeyHeader, "idempotency key is longer than %d characters", maxIdempotencyKeyLen)
+		}
+
+		msg, ok := req.(proto.Message)
+		if !ok {
+			return handler(ctx, req)
+		}
+		data, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(data)
+		hash := hex.EncodeToString(sum[:])
+
+		method := path.Base(info.FullMethod)
+		scoped := auditEntryOf(req).Actor + ":" + method + ":" + key
+
+		record, err := s.idempotency.BeginIdempotent(ctx, scoped, hash)
+		if err != nil {
+			s.logger.Error("Idempotency", "err", err, "method", method)
+			return handler(ctx, req)
+		}
+		if record != nil {
+			return s.replay(ctx, record, hash)
+		}
+
+		res, err := handler(ctx, req)
+		store := context.WithoutCancel(ctx)
+		if err != nil {
+			if aerr := s.idempotency.AbandonIdempotent(store, scoped); aerr != nil {
+				s.logger.Error("Idempotency", "err", aerr, "method", method)
+			}
+			return res, err
+		}
+
+		stored, err := anypb.New(res.(proto.Message))
+		if err == nil {
+			data, err = proto.Marshal(stored)
+		}
+		if err == nil {
+			err = s.idempotency.CompleteIdempotent(store, scoped, data)
+		}
+		if err != nil {
+			s.logger.Error("Idempotency", "err", err, "method", method)
+		}
+		return res, nil
+	}
+}
+
+// replay answers a retried call from the record of the first one.
+func (s *Service) replay(ctx context.Context, record *mongodb.IdempotencyRecord, hash string) (interface{}, error) {
+	if record.RequestHash != hash {
+		return nil, apperr.Invalid(idempotencyKeyHeader, "idempotency key was already used for a different request")
+	}
+	if !record.Done {
+		return nil, apperr.Aborted("idempotency key", "", "a request with this idempotency key is still in progress")
+	}
+
+	var stored anypb.Any
+	if err := proto.Unmarshal(record.Response, &stored); err != nil {
+		return nil, err
+	}
+	res, err := stored.UnmarshalNew()
+	if err != nil {
+		return nil, err
+	}
+	grpc.SetHeader(ctx, metadata.Pairs(replayedHeader, "true"))
+	return res, nil
+}
+
+func idempotencyKeyOf(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if keys := md.Get(idempotencyKeyHeader); len(keys) > 0 {
+			return keys[0]
+		}
+	}
+	return ""
+}
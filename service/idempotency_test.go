@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	pb "mainService/genproto/doccs"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func withIdempotencyKey(key string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(idempotencyKeyHeader, key))
+}
+
+// TestIdempotency tests that a retried call is answered from the first one
+// and that failed calls run again.
+func TestIdempotency(t *testing.T) {
+	s := newTestService(&Service{idempotency: &fakeIdempotency{}})
+
+	calls := 0
+	var fail error
+	handler := chainUnary(s.UnaryInterceptors(RateLimiter{}), "UpdateDocument", func(ctx context.Context, req interface{}) (interface{}, error) {
+		calls++
+		if fail != nil {
+			return nil, fail
+		}
+		return &pb.UpdateDocumentRes{Message: "saved"}, nil
+	})
+	req := &pb.UpdateDocumentReq{AuthorId: "u1", DocsId: "d1", Title: "t", Content: "x"}
+
+	ctx := withIdempotencyKey("k1")
+	res, err := handler(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, "saved", res.(*pb.UpdateDocumentRes).Message)
+
+	res, err = handler(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, "saved", res.(*pb.UpdateDocumentRes).Message)
+	assert.Equal(t, 1, calls, "the retry is replayed")
+
+	_, err = handler(ctx, &pb.UpdateDocumentReq{AuthorId: "u1", DocsId: "d1", Title: "t", Content: "y"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "the key belongs to another request")
+
+	_, err = handler(withIdempotencyKey("k1"), &pb.UpdateDocumentReq{AuthorId: "u2", DocsId: "d1", Title: "t", Content: "y"})
+	assert.NoError(t, err, "keys are scoped to the caller")
+	assert.Equal(t, 2, calls)
+
+	fail = errors.New("boom")
+	_, err = handler(withIdempotencyKey("k2"), req)
+	assert.Error(t, err)
+	fail = nil
+	_, err = handler(withIdempotencyKey("k2"), req)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, calls, "a failed call runs again")
+
+	_, err = handler(withIdempotencyKey(strings.Repeat("k", maxIdempotencyKeyLen+1)), req)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+// TestIdempotencyReplayNotAudited tests that a replayed call leaves no audit
+// entry of its own.
+func TestIdempotencyReplayNotAudited(t *testing.T) {
+	audit := &fakeAudit{}
+	s := newTestService(&Service{idempotency: &fakeIdempotency{}, audit: audit})
+
+	handler := chainUnary(s.UnaryInterceptors(RateLimiter{}), "UpdateDocument", func(ctx context.Context, req interface{}) (interface{}, error) {
+		return &pb.UpdateDocumentRes{Message: "saved"}, nil
+	})
+	req := &pb.UpdateDocumentReq{AuthorId: "u1", DocsId: "d1", Title: "t"}
+
+	for i := 0; i < 3; i++ {
+		_, err := handler(withIdempotencyKey("k1"), req)
+		assert.NoError(t, err)
+	}
+	entries := audit.all()
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "update", entries[0].Action)
+	assert.Equal(t, "u1", entries[0].Actor)
+}
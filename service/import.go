@@ -0,0 +1,74 @@
+package service
+
+import (
+	"io"
+	pb "mainService/genproto/doccs"
+	"mainService/pkg/apperr"
+	"mainService/pkg/importer"
+	"mainService/pkg/richtext"
+	"mainService/storage/mongodb"
+)
+
+// maxImportSize bounds the size of an uploaded file.
+const maxImportSize = 20 << 20
+
+// ImportDocument receives a file in chunks and creates a document from it.
+// The first message names the author and file; a format given there
+// overrides detection.
+func (s *Service) ImportDocument(stream pb.DocsService_ImportDocumentServer) error {
+	ctx := stream.Context()
+
+	var authorId, filename, format string
+	var data []byte
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			s.logger.Error("ImportDocument", "err", err)
+			return err
+		}
+		if authorId == "" {
+			authorId = req.AuthorId
+		}
+		if filename == "" {
+			filename = req.Filename
+		}
+		if format == "" {
+			format = req.Format
+		}
+		if len(data)+len(req.Chunk) > maxImportSize {
+			err = apperr.Invalid("chunk", "file is larger than %d bytes", maxImportSize)
+			s.logger.Error("ImportDocument", "err", err)
+			return err
+		}
+		data = append(data, req.Chunk...)
+	}
+	s.logger.Debug("ImportDocument", "authorId", authorId, "filename", filename, "size", len(data))
+
+	if format == "" {
+		format = importer.Detect(filename, data)
+	}
+	body, err := importer.Convert(format, data)
+	if err != nil {
+		s.logger.Error("ImportDocument", "err", err)
+		return apperr.Invalid("chunk", "file cannot be imported: %v", err).Wrap(err)
+	}
+
+	if err := s.quotas.CheckCreateQuota(ctx, authorId, "", mongodb.RowBytes(richtext.ToPlain(body), body)); err != nil {
+		s.logger.Error("ImportDocument", "err", err)
+		return err
+	}
+
+	res, err := s.repo.ImportDocument(ctx, authorId, importer.Title(filename), body)
+	if err != nil {
+		s.logger.Error("ImportDocument", "err", err)
+		return err
+	}
+	res.Format = format
+	s.publishEvent(ctx, mongodb.EventCreated, res.DocsId, res.Title, res.AuthorId, authorId)
+
+	s.logger.Debug("ImportDocument", "res", res)
+	return stream.SendAndClose(res)
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	pb "mainService/genproto/doccs"
+)
+
+func (s *Service) StarDocument(ctx context.Context, req *pb.StarDocumentReq) (*pb.StarDocumentRes, error) {
+	s.logger.Debug("StarDocument", "req", req)
+	res, err := s.starred.StarDocument(ctx, req)
+	if err != nil {
+		s.logger.Error("StarDocument", "err", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *Service) UnstarDocument(ctx context.Context, req *pb.UnstarDocumentReq) (*pb.UnstarDocumentRes, error) {
+	s.logger.Debug("UnstarDocument", "req", req)
+	res, err := s.starred.UnstarDocument(ctx, req)
+	if err != nil {
+		s.logger.Error("UnstarDocument", "err", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *Service) ListStarredDocuments(ctx context.Context, req *pb.ListStarredDocumentsReq) (*pb.ListStarredDocumentsRes, error) {
+	s.logger.Debug("ListStarredDocuments", "req", req)
+	res, err := s.starred.ListStarredDocuments(ctx, req)
+	if err != nil {
+		s.logger.Error("ListStarredDocuments", "err", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *Service) ListRecentDocuments(ctx context.Context, req *pb.ListRecentDocumentsReq) (*pb.ListRecentDocumentsRes, error) {
+	s.logger.Debug("ListRecentDocuments", "req", req)
+	res, err := s.recent.ListRecentDocuments(ctx, req)
+	if err != nil {
+		s.logger.Error("ListRecentDocuments", "err", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+// recordOpen adds the documents to the user's recent list. A failure here
+// must not fail the read itself, so it is only logged.
+func (s *Service) recordOpen(ctx context.Context, userId string, docs ...*pb.GetDocumentRes) {
+	if err := s.recent.RecordOpen(ctx, userId, docs...); err != nil {
+		s.logger.Error("RecordOpen", "err", err)
+	}
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"mainService/pkg/apperr"
+	"mainService/pkg/notify"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxNotificationAttempts is how often delivery is tried before a
+// notification is parked as failed.
+const maxNotificationAttempts = 6
+
+// permanentCodes are the delivery failures that retrying does not fix, such
+// as an unknown recipient or a notification that cannot be rendered.
+var permanentCodes = map[codes.Code]bool{
+	codes.InvalidArgument:    true,
+	codes.NotFound:           true,
+	codes.AlreadyExists:      true,
+	codes.PermissionDenied:   true,
+	codes.FailedPrecondition: true,
+	codes.OutOfRange:         true,
+	codes.Unimplemented:      true,
+	codes.Unauthenticated:    true,
+}
+
+// RunNotificationWorker delivers queued notifications every interval until
+// ctx is done.
+func (s *Service) RunNotificationWorker(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.deliverNotifications(ctx)
+		}
+	}
+}
+
+// deliverNotifications drains every notification that is currently due.
+func (s *Service) deliverNotifications(ctx context.Context) {
+	for {
+		n, err := s.notifications.ClaimNotification(ctx)
+		if err != nil {
+			s.logger.Error("ClaimNotification", "err", err)
+			return
+		}
+		if n == nil {
+			return
+		}
+
+		err = s.deliverNotification(ctx, n.Kind, n.To, n.UserId, n.Data)
+		if err == nil {
+			if err := s.notifications.CompleteNotification(ctx, n.Id); err != nil {
+				s.logger.Error("CompleteNotification", "err", err)
+			}
+			continue
+		}
+
+		attempts := n.Attempts + 1
+		dead := attempts >= maxNotificationAttempts || permanentFailure(err)
+		s.logger.Error("DeliverNotification", "id", n.Id, "attempt", attempts, "err", err)
+		if err := s.notifications.FailNotification(ctx, n.Id, attempts, err, time.Now().Add(notify.Backoff(attempts)), dead); err != nil {
+			s.logger.Error("FailNotification", "err", err)
+		}
+	}
+}
+
+func (s *Service) deliverNotification(ctx context.Context, kind, to, userId string, data map[string]string) error {
+	if to == "" {
+		email, err := s.recipientEmail(ctx, userId)
+		if err != nil {
+			return err
+		}
+		to = email
+	}
+
+	msg, err := notify.Render(kind, to, data)
+	if err != nil {
+		return apperr.Invalid("kind", "notification '%s' cannot be rendered: %v", kind, err).Wrap(err)
+	}
+	return s.sender.Send(ctx, msg)
+}
+
+// permanentFailure reports whether a delivery failed for good. Errors without
+// a status code, such as those of the mail server, are retried.
+func permanentFailure(err error) bool {
+	return permanentCodes[status.Code(err)]
+}
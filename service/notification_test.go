@@ -0,0 +1,25 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"mainService/pkg/apperr"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestPermanentFailure tests that only failures retrying cannot fix park a
+// notification.
+func TestPermanentFailure(t *testing.T) {
+	assert.True(t, permanentFailure(apperr.NotFound("user", "u1")))
+	assert.True(t, permanentFailure(status.Error(codes.InvalidArgument, "bad email")))
+	assert.True(t, permanentFailure(fmt.Errorf("lookup: %w", status.Error(codes.PermissionDenied, "denied"))))
+
+	assert.False(t, permanentFailure(status.Error(codes.Unavailable, "user service down")))
+	assert.False(t, permanentFailure(status.Error(codes.DeadlineExceeded, "slow")))
+	assert.False(t, permanentFailure(errors.New("smtp: connection reset")))
+}
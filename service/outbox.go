@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"mainService/pkg/events"
+	"mainService/pkg/notify"
+	"time"
+)
+
+// RunEventRelay hands outbox events to the broker every interval until ctx
+// is done.
+func (s *Service) RunEventRelay(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.relayEvents(ctx)
+		}
+	}
+}
+
+// relayEvents drains every outbox event that is currently due. It stops at
+// the first failure, since the broker is most likely down.
+func (s *Service) relayEvents(ctx context.Context) {
+	for {
+		e, err := s.outbox.ClaimOutboxEvent(ctx)
+		if err != nil {
+			s.logger.Error("ClaimOutboxEvent", "err", err)
+			return
+		}
+		if e == nil {
+			return
+		}
+
+		err = s.publisher.Publish(ctx, events.Event{
+			Id:        e.Event.Id,
+			Kind:      e.Event.Kind,
+			DocsId:    e.Event.DocsId,
+			Title:     e.Event.Title,
+			AuthorId:  e.Event.AuthorId,
+			Actor:     e.Event.Actor,
+			CreatedAt: e.Event.CreatedAt,
+		})
+		if err == nil {
+			if err := s.outbox.CompleteOutboxEvent(ctx, e.Event.Id); err != nil {
+				s.logger.Error("CompleteOutboxEvent", "err", err)
+			}
+			continue
+		}
+
+		attempts := e.Attempts + 1
+		s.logger.Error("PublishEvent", "id", e.Event.Id, "attempt", attempts, "err", err)
+		if err := s.outbox.FailOutboxEvent(ctx, e.Event.Id, attempts, err, time.Now().Add(notify.Backoff(attempts))); err != nil {
+			s.logger.Error("FailOutboxEvent", "err", err)
+		}
+		return
+	}
+}
@@ -0,0 +1,17 @@
+package service
+
+import (
+	"context"
+	pb "mainService/genproto/doccs"
+)
+
+func (s *Service) GetUsage(ctx context.Context, req *pb.GetUsageReq) (*pb.GetUsageRes, error) {
+	s.logger.Debug("GetUsage", "req", req)
+	res, err := s.quotas.GetUsage(ctx, req)
+	if err != nil {
+		s.logger.Error("GetUsage", "err", err)
+		return nil, err
+	}
+	return res, nil
+}
+
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"math"
+	"path"
+	"strconv"
+
+	"mainService/pkg/apperr"
+	"mainService/pkg/ratelimit"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// retryAfterHeader tells a limited client how many seconds to wait.
+const retryAfterHeader = "retry-after"
+
+// RateLimiter picks the limit of every call. Methods without an entry in
+// Methods get Default. Address limits all calls from one client address
+// together; it is meant to be much higher than the others, as one address
+// may be a gateway in front of many users.
+type RateLimiter struct {
+	Store   ratelimit.Store
+	Default ratelimit.Limit
+	Methods map[string]ratelimit.Limit
+	Address ratelimit.Limit
+}
+
+func (l RateLimiter) limitOf(method string) ratelimit.Limit {
+	if limit, ok := l.Methods[method]; ok {
+		return limit
+	}
+	return l.Default
+}
+
+// RateLimitInterceptor limits calls per caller and method, and all calls of
+// a client address together. The caller is the user named in the request,
+// or the client address when there is none. The user is set by the client,
+// so a client that makes up a new user for every call still runs into the
+// limit of its address. A call takes its tokens only when both limits let
+// it through. A store that fails lets the call through, so an outage of a
+// shared store does not take the service down with it.
+func (s *Service) RateLimitInterceptor(limiter RateLimiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		caller := "user:" + auditEntryOf(req).Actor
+		if caller == "user:" {
+			caller = "ip:" + clientIpOf(ctx)
+		}
+		if err := s.allow(ctx, limiter, caller, path.Base(info.FullMethod)); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// RateLimitStreamInterceptor limits stream calls per client address and
+// method; the request of a stream is not known when it opens.
+func (s *Service) RateLimitStreamInterceptor(limiter RateLimiter) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		caller := "ip:" + clientIpOf(stream.Context())
+		if err := s.allow(stream.Context(), limiter, caller, path.Base(info.FullMethod)); err != nil {
+			return err
+		}
+		return handler(srv, stream)
+	}
+}
+
+func (s *Service) allow(ctx context.Context, limiter RateLimiter, caller, method string) error {
+	var buckets []ratelimit.Bucket
+	if limit := limiter.limitOf(method); !limit.Unlimited() {
+		buckets = append(buckets, ratelimit.Bucket{Key: caller + ":" + method, Limit: limit})
+	}
+	if !limiter.Address.Unlimited() {
+		buckets = append(buckets, ratelimit.Bucket{Key: "address:" + clientIpOf(ctx), Limit: limiter.Address})
+	}
+	if len(buckets) == 0 {
+		return nil
+	}
+
+	ok, wait, err := limiter.Store.TakeAll(ctx, buckets)
+	if err != nil {
+		s.logger.Error("RateLimit", "err", err, "method", method)
+		return nil
+	}
+	if ok {
+		return nil
+	}
+
+	seconds := int(math.Ceil(wait.Seconds()))
+	grpc.SetHeader(ctx, metadata.Pairs(retryAfterHeader, strconv.Itoa(seconds)))
+	return apperr.RateLimited(method, seconds)
+}
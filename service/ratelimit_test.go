@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	pb "mainService/genproto/doccs"
+	"mainService/pkg/ratelimit"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func fromAddress(ip string) context.Context {
+	return peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 4000}})
+}
+
+// failingStore is a rate limit store that is down.
+type failingStore struct{}
+
+func (failingStore) Take(ctx context.Context, key string, limit ratelimit.Limit) (bool, time.Duration, error) {
+	return false, 0, errors.New("store is down")
+}
+
+func (failingStore) TakeAll(ctx context.Context, buckets []ratelimit.Bucket) (bool, time.Duration, error) {
+	return false, 0, errors.New("store is down")
+}
+
+// TestRateLimit tests that calls are limited per user and method, and per
+// client address across users and methods.
+func TestRateLimit(t *testing.T) {
+	s := newTestService(&Service{})
+	limiter := RateLimiter{
+		Store:   ratelimit.NewMemoryStore(),
+		Default: ratelimit.Limit{Rate: 0.001, Burst: 2},
+		Methods: map[string]ratelimit.Limit{"GetDocument": {}},
+		Address: ratelimit.Limit{Rate: 0.001, Burst: 4},
+	}
+	update := chainUnary(s.UnaryInterceptors(limiter), "UpdateDocument", func(ctx context.Context, req interface{}) (interface{}, error) {
+		return &pb.UpdateDocumentRes{}, nil
+	})
+	get := chainUnary(s.UnaryInterceptors(limiter), "GetDocument", func(ctx context.Context, req interface{}) (interface{}, error) {
+		return &pb.GetDocumentRes{}, nil
+	})
+
+	for i := 0; i < 2; i++ {
+		_, err := update(fromAddress("10.0.0.1"), &pb.UpdateDocumentReq{AuthorId: "u1"})
+		assert.NoError(t, err)
+	}
+	_, err := update(fromAddress("10.0.0.2"), &pb.UpdateDocumentReq{AuthorId: "u1"})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err), "the user is limited from any address")
+
+	_, err = update(fromAddress("10.0.0.1"), &pb.UpdateDocumentReq{AuthorId: "u1"})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	_, err = update(fromAddress("10.0.0.1"), &pb.UpdateDocumentReq{AuthorId: "u2"})
+	assert.NoError(t, err, "calls rejected for the user took nothing from the address")
+	_, err = get(fromAddress("10.0.0.1"), &pb.GetDocumentReq{AuthorId: "u3"})
+	assert.NoError(t, err)
+
+	_, err = update(fromAddress("10.0.0.1"), &pb.UpdateDocumentReq{AuthorId: "u4"})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err), "a new user does not get around the address limit")
+	_, err = get(fromAddress("10.0.0.1"), &pb.GetDocumentReq{AuthorId: "u4"})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err), "the address limit covers every method")
+}
+
+// TestRateLimitStoreDown tests that calls go through while the store fails.
+func TestRateLimitStoreDown(t *testing.T) {
+	s := newTestService(&Service{})
+	limiter := RateLimiter{Store: failingStore{}, Default: ratelimit.Limit{Rate: 1, Burst: 1}}
+	handler := chainUnary(s.UnaryInterceptors(limiter), "UpdateDocument", func(ctx context.Context, req interface{}) (interface{}, error) {
+		return &pb.UpdateDocumentRes{}, nil
+	})
+
+	for i := 0; i < 3; i++ {
+		_, err := handler(fromAddress("10.0.0.1"), &pb.UpdateDocumentReq{AuthorId: "u1"})
+		assert.NoError(t, err)
+	}
+}
@@ -4,7 +4,15 @@ import (
 	"context"
 	"log/slog"
 	pb "mainService/genproto/doccs"
+	pbu "mainService/genproto/user"
+	"mainService/pkg/apperr"
+	"mainService/pkg/blob"
+	"mainService/pkg/events"
+	"mainService/pkg/notify"
 	"mainService/storage/mongodb"
+	"time"
+
+	"google.golang.org/grpc"
 )
 
 type Service struct {
@@ -12,27 +20,122 @@ type Service struct {
 	logger *slog.Logger
 	repo   mongodb.DocumentRepository
 	version mongodb.DocumentVersionRepository
+	starred mongodb.StarredRepository
+	recent  mongodb.RecentRepository
+	comments mongodb.CommentRepository
+	suggestions mongodb.SuggestionRepository
+	templates   mongodb.TemplateRepository
+	links       mongodb.ShareLinkRepository
+	collaborators mongodb.CollaboratorRepository
+	notifications mongodb.NotificationRepository
+	accessRequests mongodb.AccessRequestRepository
+	sender      notify.Sender
+	users       pbu.UserServiceClient
+	audit       mongodb.AuditRepository
+	events      mongodb.EventRepository
+	webhooks    mongodb.WebhookRepository
+	outbox      mongodb.OutboxRepository
+	publisher   events.Publisher
+	attachments mongodb.AttachmentRepository
+	blobs       blob.Store
+	workspaces  mongodb.WorkspaceRepository
+	quotas      mongodb.QuotaRepository
+	// autosaveWindow is how long autosaves of one user keep amending the
+	// same version.
+	autosaveWindow time.Duration
+	idempotency    mongodb.IdempotencyRepository
 }
 
-func NewService(logger *slog.Logger, repo mongodb.DocumentRepository,version mongodb.DocumentVersionRepository) *Service {
+func NewService(logger *slog.Logger, repo mongodb.DocumentRepository,version mongodb.DocumentVersionRepository, starred mongodb.StarredRepository, recent mongodb.RecentRepository, comments mongodb.CommentRepository, suggestions mongodb.SuggestionRepository, templates mongodb.TemplateRepository, links mongodb.ShareLinkRepository, collaborators mongodb.CollaboratorRepository, notifications mongodb.NotificationRepository, accessRequests mongodb.AccessRequestRepository, sender notify.Sender, users pbu.UserServiceClient, audit mongodb.AuditRepository, events mongodb.EventRepository, webhooks mongodb.WebhookRepository, outbox mongodb.OutboxRepository, publisher events.Publisher, attachments mongodb.AttachmentRepository, blobs blob.Store, workspaces mongodb.WorkspaceRepository, quotas mongodb.QuotaRepository, autosaveWindow time.Duration, idempotency mongodb.IdempotencyRepository) *Service {
 	return &Service{
 		logger: logger,
 		repo:   repo,
 		version: version,
+		starred: starred,
+		recent:  recent,
+		comments: comments,
+		suggestions: suggestions,
+		templates:   templates,
+		links:       links,
+		collaborators: collaborators,
+		notifications: notifications,
+		accessRequests: accessRequests,
+		sender:      sender,
+		users:       users,
+		audit:       audit,
+		events:      events,
+		webhooks:    webhooks,
+		outbox:      outbox,
+		publisher:   publisher,
+		attachments: attachments,
+		blobs:       blobs,
+		workspaces:  workspaces,
+		quotas:      quotas,
+		autosaveWindow: autosaveWindow,
+		idempotency:    idempotency,
+	}
+}
+
+// UnaryInterceptors returns the interceptors of unary calls in the order
+// they are chained. Idempotency goes before audit, so a replayed call is not
+// audited as a call of its own.
+func (s *Service) UnaryInterceptors(limiter RateLimiter) []grpc.UnaryServerInterceptor {
+	return []grpc.UnaryServerInterceptor{
+		s.ErrorInterceptor(),
+		s.RateLimitInterceptor(limiter),
+		s.IdempotencyInterceptor(),
+		s.AuditInterceptor(),
+	}
+}
+
+// StreamInterceptors is UnaryInterceptors for streaming calls.
+func (s *Service) StreamInterceptors(limiter RateLimiter) []grpc.StreamServerInterceptor {
+	return []grpc.StreamServerInterceptor{
+		s.ErrorStreamInterceptor(),
+		s.RateLimitStreamInterceptor(limiter),
+		s.AuditStreamInterceptor(),
 	}
 }
 
 func (s *Service) CreateDocument(ctx context.Context, req *pb.CreateDocumentReq) (*pb.CreateDocumentRes, error) {
 	s.logger.Debug("CreateDocument", "req", req)
-	res, err := s.repo.CreateDocument(ctx, req)
+	var err error
+	if req.TemplateId != "" {
+		err = s.quotas.CheckTemplateQuota(ctx, req)
+	} else {
+		err = s.quotas.CheckCreateQuota(ctx, req.AuthorId, req.WorkspaceId, 0)
+	}
 	if err != nil {
 		s.logger.Error("CreateDocument", "err", err)
 		return nil, err
 	}
+	var res *pb.CreateDocumentRes
+	if req.TemplateId != "" {
+		res, err = s.repo.CreateFromTemplate(ctx, req, s.displayName(ctx, req.AuthorId))
+	} else {
+		res, err = s.repo.CreateDocument(ctx, req)
+	}
+	if err != nil {
+		s.logger.Error("CreateDocument", "err", err)
+		return nil, err
+	}
+	s.publishEvent(ctx, mongodb.EventCreated, res.DocsId, res.Title, res.AuthorId, req.AuthorId)
 	s.logger.Debug("CreateDocument", "res", res)
 	return res, nil
 }
 
+func (s *Service) GetDocument(ctx context.Context, req *pb.GetDocumentReq) (*pb.GetDocumentRes, error) {
+	s.logger.Debug("GetDocument", "req", req)
+	res, err := s.repo.GetDocument(ctx, req)
+	if err != nil {
+		s.logger.Error("GetDocument", "err", err)
+		return nil, err
+	}
+	s.recordOpen(ctx, req.AuthorId, res)
+	s.logger.Debug("GetDocument", "res", res)
+	return res, nil
+}
+
 func (s *Service) SearchDocument(ctx context.Context, req *pb.SearchDocumentReq) (*pb.SearchDocumentRes, error) {
 	s.logger.Debug("SearchDocument", "req", req)
 	res, err := s.repo.SearchDocument(ctx, req)
@@ -51,21 +154,56 @@ func (s *Service) GetAllDocuments(ctx context.Context, req *pb.GetAllDocumentsRe
 		s.logger.Error("GetAllDocuments", "err", err)
 		return nil, err
 	}
+	s.recordOpen(ctx, req.AuthorId, res.Documents...)
 	s.logger.Debug("GetAllDocuments", "res", res)
 	return res, nil
 }
 
 func (s *Service) UpdateDocument(ctx context.Context, req *pb.UpdateDocumentReq) (*pb.UpdateDocumentRes, error) {
+	return s.updateDocument(ctx, req, "")
+}
+
+// updateDocument saves req, crediting suggestedBy when it applies an accepted
+// suggestion. Suggestions are never autosaved.
+func (s *Service) updateDocument(ctx context.Context, req *pb.UpdateDocumentReq, suggestedBy string) (*pb.UpdateDocumentRes, error) {
 	s.logger.Debug("UpdateDocument", "req", req)
-	res, err := s.repo.UpdateDocument(ctx, req)
+	size, err := mongodb.UpdateBytes(req)
+	if err == nil {
+		err = s.quotas.CheckUpdateQuota(ctx, req.DocsId, req.Title, size)
+	}
 	if err != nil {
 		s.logger.Error("UpdateDocument", "err", err)
 		return nil, err
 	}
+	var res *pb.UpdateDocumentRes
+	if suggestedBy != "" {
+		res, err = s.repo.ApplySuggestedUpdate(ctx, req, suggestedBy)
+	} else if req.Autosave {
+		res, err = s.repo.AutosaveDocument(ctx, req, s.autosaveWindow)
+	} else {
+		res, err = s.repo.UpdateDocument(ctx, req)
+	}
+	if err != nil {
+		s.logger.Error("UpdateDocument", "err", err)
+		return nil, err
+	}
+	s.reanchorComments(ctx, req.DocsId, req.Title)
+	s.publishEvent(ctx, mongodb.EventUpdated, req.DocsId, req.Title, "", req.AuthorId)
 	s.logger.Debug("UpdateDocument", "res", res)
 	return res, nil
 }
 
+// SaveVersion keeps the current autosaved content as a version of its own.
+func (s *Service) SaveVersion(ctx context.Context, req *pb.SaveVersionReq) (*pb.SaveVersionRes, error) {
+	s.logger.Debug("SaveVersion", "req", req)
+	res, err := s.repo.SaveVersion(ctx, req)
+	if err != nil {
+		s.logger.Error("SaveVersion", "err", err)
+		return nil, err
+	}
+	return res, nil
+}
+
 func (s *Service) DeleteDocument(ctx context.Context, req *pb.DeleteDocumentReq) (*pb.DeleteDocumentRes, error) {
 	s.logger.Debug("DeleteDocument", "req", req)
 	res, err := s.repo.DeleteDocument(ctx, req)
@@ -73,6 +211,7 @@ func (s *Service) DeleteDocument(ctx context.Context, req *pb.DeleteDocumentReq)
 		s.logger.Error("DeleteDocument", "err", err)
 		return nil, err
 	}
+	s.publishEvent(ctx, mongodb.EventDeleted, req.DocsId, req.Title, "", req.AuthorId)
 	s.logger.Debug("DeleteDocument", "res", res)
 	return res, nil
 }
@@ -83,6 +222,7 @@ func (s *Service) ShareDocument(ctx context.Context,req *pb.ShareDocumentReq) (*
 		s.logger.Error("ShareDocument", "err", err)
 		return nil, err
 	}
+	s.publishEvent(ctx, mongodb.EventShared, res.DocsId, req.Title, "", req.GrantedBy)
 	return res, nil
 }
 
@@ -98,12 +238,58 @@ func (s *Service) GetAllVersions(ctx context.Context,req *pb.GetAllVersionsReq)
 }
 
 func (s *Service) RestoreVersion(ctx context.Context,req *pb.RestoreVersionReq)(*pb.RestoreVersionRes,error){
+	if err := s.quotas.CheckRestoreQuota(ctx, req); err != nil {
+		s.logger.Error("RestoreVersion", "err", err)
+		return nil, err
+	}
 	res,err:=s.version.RestoreVersion(ctx,req)
 	if err!=nil{
 		s.logger.Error("RestoreVersion", "err", err)
 		return nil, err
 	}
+	s.reanchorComments(ctx, req.Id, req.Title)
+	s.publishEvent(ctx, mongodb.EventRestored, req.Id, req.Title, "", req.AuthorId)
 	return &pb.RestoreVersionRes{
 		Message: res.Message,
 	},nil
-}
\ No newline at end of file
+}
+func (s *Service) CopyDocument(ctx context.Context, req *pb.CopyDocumentReq) (*pb.CopyDocumentRes, error) {
+	s.logger.Debug("CopyDocument", "req", req)
+	if err := s.quotas.CheckCopyQuota(ctx, req); err != nil {
+		s.logger.Error("CopyDocument", "err", err)
+		return nil, err
+	}
+	res, err := s.repo.CopyDocument(ctx, req)
+	if err != nil {
+		s.logger.Error("CopyDocument", "err", err)
+		return nil, err
+	}
+	s.publishEvent(ctx, mongodb.EventCreated, res.DocsId, res.Title, res.AuthorId, req.UserId)
+	s.logger.Debug("CopyDocument", "res", res)
+	return res, nil
+}
+
+// TransferOwnership resolves the new owner through the user service, so only
+// registered users can receive a document.
+func (s *Service) TransferOwnership(ctx context.Context, req *pb.TransferOwnershipReq) (*pb.TransferOwnershipRes, error) {
+	s.logger.Debug("TransferOwnership", "req", req)
+	user, err := s.users.GetUSerByEmail(ctx, &pbu.GetUSerByEmailReq{Email: req.NewOwnerEmail})
+	if err != nil {
+		s.logger.Error("TransferOwnership", "err", err)
+		return nil, err
+	}
+	if user.GetUser().GetId() == "" {
+		err = apperr.NotFound("user", req.NewOwnerEmail)
+		s.logger.Error("TransferOwnership", "err", err)
+		return nil, err
+	}
+
+	res, err := s.repo.TransferOwnership(ctx, req, user.User.Id)
+	if err != nil {
+		s.logger.Error("TransferOwnership", "err", err)
+		return nil, err
+	}
+	s.publishEvent(ctx, mongodb.EventTransferred, req.DocsId, req.Title, res.NewOwnerId, req.UserId)
+	s.logger.Debug("TransferOwnership", "res", res)
+	return res, nil
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"mainService/storage/mongodb"
+	"sync"
+
+	"google.golang.org/grpc"
+)
+
+// newTestService returns a service on the given fakes. Repositories left
+// nil are not used by the code under test.
+func newTestService(s *Service) *Service {
+	s.logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	if s.audit == nil {
+		s.audit = &fakeAudit{}
+	}
+	return s
+}
+
+// chainUnary runs handler behind interceptors the way grpc chains them.
+func chainUnary(interceptors []grpc.UnaryServerInterceptor, method string, handler grpc.UnaryHandler) grpc.UnaryHandler {
+	info := &grpc.UnaryServerInfo{FullMethod: "/doccs.DocsService/" + method}
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], handler
+		handler = func(ctx context.Context, req interface{}) (interface{}, error) {
+			return interceptor(ctx, req, info, next)
+		}
+	}
+	return handler
+}
+
+// fakeAudit keeps the entries written.
+type fakeAudit struct {
+	mongodb.AuditRepository
+	mu      sync.Mutex
+	entries []mongodb.AuditEntry
+}
+
+func (f *fakeAudit) RecordAudit(ctx context.Context, entry mongodb.AuditEntry) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.entries = append(f.entries, entry)
+	return nil
+}
+
+func (f *fakeAudit) all() []mongodb.AuditEntry {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]mongodb.AuditEntry(nil), f.entries...)
+}
+
+// fakeIdempotency keeps records in memory.
+type fakeIdempotency struct {
+	mongodb.IdempotencyRepository
+	mu      sync.Mutex
+	records map[string]*mongodb.IdempotencyRecord
+}
+
+func (f *fakeIdempotency) BeginIdempotent(ctx context.Context, key, requestHash string) (*mongodb.IdempotencyRecord, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.records == nil {
+		f.records = map[string]*mongodb.IdempotencyRecord{}
+	}
+	if record, ok := f.records[key]; ok {
+		copied := *record
+		return &copied, nil
+	}
+	f.records[key] = &mongodb.IdempotencyRecord{RequestHash: requestHash}
+	return nil, nil
+}
+
+func (f *fakeIdempotency) CompleteIdempotent(ctx context.Context, key string, response []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.records[key].Done = true
+	f.records[key].Response = response
+	return nil
+}
+
+func (f *fakeIdempotency) AbandonIdempotent(ctx context.Context, key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.records, key)
+	return nil
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	pb "mainService/genproto/doccs"
+)
+
+func (s *Service) CreateShareLink(ctx context.Context, req *pb.CreateShareLinkReq) (*pb.CreateShareLinkRes, error) {
+	s.logger.Debug("CreateShareLink", "req", req)
+	res, err := s.links.CreateShareLink(ctx, req)
+	if err != nil {
+		s.logger.Error("CreateShareLink", "err", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *Service) RevokeShareLink(ctx context.Context, req *pb.RevokeShareLinkReq) (*pb.RevokeShareLinkRes, error) {
+	s.logger.Debug("RevokeShareLink", "req", req)
+	res, err := s.links.RevokeShareLink(ctx, req)
+	if err != nil {
+		s.logger.Error("RevokeShareLink", "err", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *Service) ListShareLinks(ctx context.Context, req *pb.ListShareLinksReq) (*pb.ListShareLinksRes, error) {
+	s.logger.Debug("ListShareLinks", "req", req)
+	res, err := s.links.ListShareLinks(ctx, req)
+	if err != nil {
+		s.logger.Error("ListShareLinks", "err", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *Service) ResolveShareLink(ctx context.Context, req *pb.ResolveShareLinkReq) (*pb.ResolveShareLinkRes, error) {
+	s.logger.Debug("ResolveShareLink", "req", req)
+	var email string
+	if req.UserId != "" {
+		var err error
+		if email, err = s.emailOf(ctx, req.UserId); err != nil {
+			s.logger.Error("ResolveShareLink", "err", err)
+			return nil, err
+		}
+	}
+	res, err := s.links.ResolveShareLink(ctx, req, email)
+	if err != nil {
+		s.logger.Error("ResolveShareLink", "err", err)
+		return nil, err
+	}
+	s.recordOpen(ctx, req.UserId, res.Document)
+	return res, nil
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	pb "mainService/genproto/doccs"
+	"mainService/pkg/apperr"
+	"mainService/pkg/exporter"
+)
+
+const (
+	defaultChunkSize = 64 << 10
+	maxChunkSize     = 1 << 20
+)
+
+// StreamDocument sends a document, rendered in the requested format, in
+// chunks. A client resumes a broken download by sending the offset it got to
+// and the etag of the first attempt; when the document changed in between,
+// the etag no longer matches and the download has to start over.
+func (s *Service) StreamDocument(req *pb.StreamDocumentReq, stream pb.DocsService_StreamDocumentServer) error {
+	ctx := stream.Context()
+	s.logger.Debug("StreamDocument", "req", req)
+
+	if !exporter.Supported(req.Format) {
+		err := apperr.Invalid("format", "export format '%s' is not supported", req.Format)
+		s.logger.Error("StreamDocument", "err", err)
+		return err
+	}
+
+	body, err := s.repo.ReadDocument(ctx, req)
+	if err != nil {
+		s.logger.Error("StreamDocument", "err", err)
+		return err
+	}
+	data, contentType, err := exporter.Export(body, req.Format)
+	if err != nil {
+		s.logger.Error("StreamDocument", "err", err)
+		return err
+	}
+
+	sum := sha256.Sum256(data)
+	etag := hex.EncodeToString(sum[:16])
+	if req.Etag != "" && req.Etag != etag {
+		err = apperr.Conflict("document", req.Title, "document '%s' changed since the download started", req.Title)
+		s.logger.Error("StreamDocument", "err", err)
+		return err
+	}
+	if req.Offset < 0 || req.Offset > int64(len(data)) {
+		err = apperr.Invalid("offset", "offset %d is outside the document of %d bytes", req.Offset, len(data))
+		s.logger.Error("StreamDocument", "err", err)
+		return err
+	}
+
+	size := int64(req.ChunkSize)
+	if size <= 0 {
+		size = defaultChunkSize
+	}
+	if size > maxChunkSize {
+		size = maxChunkSize
+	}
+
+	total := int64(len(data))
+	for offset := req.Offset; ; offset += size {
+		end := offset + size
+		if end > total {
+			end = total
+		}
+		err := stream.Send(&pb.DocumentChunk{
+			Offset:      offset,
+			Data:        data[offset:end],
+			TotalSize:   total,
+			Etag:        etag,
+			ContentType: contentType,
+			Last:        end == total,
+		})
+		if err != nil {
+			return err
+		}
+		if end == total {
+			return nil
+		}
+	}
+}
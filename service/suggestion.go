@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	pb "mainService/genproto/doccs"
+)
+
+func (s *Service) CreateSuggestion(ctx context.Context, req *pb.CreateSuggestionReq) (*pb.CreateSuggestionRes, error) {
+	s.logger.Debug("CreateSuggestion", "req", req)
+	res, err := s.suggestions.CreateSuggestion(ctx, req)
+	if err != nil {
+		s.logger.Error("CreateSuggestion", "err", err)
+		return nil, err
+	}
+	s.logger.Debug("CreateSuggestion", "res", res)
+	return res, nil
+}
+
+func (s *Service) ListSuggestions(ctx context.Context, req *pb.ListSuggestionsReq) (*pb.ListSuggestionsRes, error) {
+	s.logger.Debug("ListSuggestions", "req", req)
+	res, err := s.suggestions.ListSuggestions(ctx, req)
+	if err != nil {
+		s.logger.Error("ListSuggestions", "err", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+// AcceptSuggestion applies the suggestion through UpdateDocument, so the
+// result is an ordinary new version of the document, saved by the accepting
+// user and crediting the suggesting one in suggestedBy. The suggestion stays claimed while that runs, so it is applied once.
+func (s *Service) AcceptSuggestion(ctx context.Context, req *pb.AcceptSuggestionReq) (*pb.AcceptSuggestionRes, error) {
+	s.logger.Debug("AcceptSuggestion", "req", req)
+	update, suggestedBy, err := s.suggestions.ApplySuggestion(ctx, req)
+	if err != nil {
+		s.logger.Error("AcceptSuggestion", "err", err)
+		return nil, err
+	}
+
+	if _, err := s.updateDocument(ctx, update, suggestedBy); err != nil {
+		if rerr := s.suggestions.ReleaseSuggestion(ctx, req); rerr != nil {
+			s.logger.Error("ReleaseSuggestion", "err", rerr)
+		}
+		return nil, err
+	}
+
+	res, err := s.suggestions.MarkSuggestionAccepted(ctx, req)
+	if err != nil {
+		s.logger.Error("AcceptSuggestion", "err", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *Service) RejectSuggestion(ctx context.Context, req *pb.RejectSuggestionReq) (*pb.RejectSuggestionRes, error) {
+	s.logger.Debug("RejectSuggestion", "req", req)
+	res, err := s.suggestions.RejectSuggestion(ctx, req)
+	if err != nil {
+		s.logger.Error("RejectSuggestion", "err", err)
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"context"
+	pb "mainService/genproto/doccs"
+)
+
+func (s *Service) SaveAsTemplate(ctx context.Context, req *pb.SaveAsTemplateReq) (*pb.SaveAsTemplateRes, error) {
+	s.logger.Debug("SaveAsTemplate", "req", req)
+	res, err := s.templates.SaveAsTemplate(ctx, req)
+	if err != nil {
+		s.logger.Error("SaveAsTemplate", "err", err)
+		return nil, err
+	}
+	s.logger.Debug("SaveAsTemplate", "res", res)
+	return res, nil
+}
+
+func (s *Service) ListTemplates(ctx context.Context, req *pb.ListTemplatesReq) (*pb.ListTemplatesRes, error) {
+	s.logger.Debug("ListTemplates", "req", req)
+	res, err := s.templates.ListTemplates(ctx, req)
+	if err != nil {
+		s.logger.Error("ListTemplates", "err", err)
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	pbu "mainService/genproto/user"
+	"mainService/pkg/apperr"
+	"strings"
+)
+
+// displayName is the name of a user as shown to other users: the full name,
+// or the email when the user has not set one. It falls back to the userId
+// when the user service cannot name the user, so a template never renders
+// an empty {{author}}.
+func (s *Service) displayName(ctx context.Context, userId string) string {
+	res, err := s.users.GetUser(ctx, &pbu.UserId{Id: userId})
+	if err != nil {
+		s.logger.Error("GetUser", "userId", userId, "err", err)
+		return userId
+	}
+	user := res.GetUser()
+	if name := strings.TrimSpace(user.GetFirstName() + " " + user.GetLastName()); name != "" {
+		return name
+	}
+	if email := user.GetEmail(); email != "" {
+		return email
+	}
+	return userId
+}
+
+// emailOf returns the registered email of a user.
+func (s *Service) emailOf(ctx context.Context, userId string) (string, error) {
+	res, err := s.users.GetUser(ctx, &pbu.UserId{Id: userId})
+	if err != nil {
+		return "", err
+	}
+	if res.GetUser().GetEmail() == "" {
+		return "", apperr.NotFound("user", userId)
+	}
+	return res.GetUser().GetEmail(), nil
+}
+
+// recipientEmail returns the registered email of a notification recipient,
+// given by userId or, for mentions, by email. Addresses that do not belong
+// to a registered user are not mailed.
+func (s *Service) recipientEmail(ctx context.Context, recipient string) (string, error) {
+	if recipient == "" {
+		return "", apperr.Missing("user_id")
+	}
+	if !strings.Contains(recipient, "@") {
+		return s.emailOf(ctx, recipient)
+	}
+
+	res, err := s.users.GetUSerByEmail(ctx, &pbu.GetUSerByEmailReq{Email: recipient})
+	if err != nil {
+		return "", err
+	}
+	if res.GetUser().GetEmail() == "" {
+		return "", apperr.NotFound("user", recipient)
+	}
+	return res.GetUser().GetEmail(), nil
+}
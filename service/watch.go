@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+	pb "mainService/genproto/doccs"
+)
+
+// WatchDocument streams the events of a document until the client goes away,
+// the document is deleted or transferred, or the caller loses access.
+func (s *Service) WatchDocument(req *pb.WatchDocumentReq, stream pb.DocsService_WatchDocumentServer) error {
+	s.logger.Debug("WatchDocument", "req", req)
+	if err := s.events.WatchEvents(stream.Context(), req, stream.Send); err != nil {
+		s.logger.Error("WatchDocument", "err", err)
+		return err
+	}
+	return nil
+}
+
+// publishEvent tells the watchers of a document about a change. The broker
+// and webhook subscribers get the event through the outbox, which the
+// repository writes in the transaction of the change. A failure here does
+// not fail the change itself.
+func (s *Service) publishEvent(ctx context.Context, kind, docsId, title, authorId, actor string) {
+	event := &pb.DocumentEvent{
+		Kind:     kind,
+		DocsId:   docsId,
+		Title:    title,
+		AuthorId: authorId,
+		Actor:    actor,
+	}
+	if err := s.events.PublishEvent(ctx, event); err != nil {
+		s.logger.Error("PublishEvent", "err", err, "kind", kind)
+	}
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	pb "mainService/genproto/doccs"
+	"mainService/pkg/notify"
+	"mainService/pkg/webhook"
+	"net/http"
+	"time"
+)
+
+// maxWebhookAttempts is how often delivery is tried before a delivery is
+// moved to the dead-letter list.
+const maxWebhookAttempts = 8
+
+func (s *Service) CreateWebhook(ctx context.Context, req *pb.CreateWebhookReq) (*pb.CreateWebhookRes, error) {
+	s.logger.Debug("CreateWebhook", "url", req.Url, "userId", req.UserId)
+	res, err := s.webhooks.CreateWebhook(ctx, req)
+	if err != nil {
+		s.logger.Error("CreateWebhook", "err", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *Service) DeleteWebhook(ctx context.Context, req *pb.DeleteWebhookReq) (*pb.DeleteWebhookRes, error) {
+	s.logger.Debug("DeleteWebhook", "req", req)
+	res, err := s.webhooks.DeleteWebhook(ctx, req)
+	if err != nil {
+		s.logger.Error("DeleteWebhook", "err", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *Service) ListWebhooks(ctx context.Context, req *pb.ListWebhooksReq) (*pb.ListWebhooksRes, error) {
+	s.logger.Debug("ListWebhooks", "req", req)
+	res, err := s.webhooks.ListWebhooks(ctx, req)
+	if err != nil {
+		s.logger.Error("ListWebhooks", "err", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *Service) ListWebhookDeliveries(ctx context.Context, req *pb.ListWebhookDeliveriesReq) (*pb.ListWebhookDeliveriesRes, error) {
+	s.logger.Debug("ListWebhookDeliveries", "req", req)
+	res, err := s.webhooks.ListWebhookDeliveries(ctx, req)
+	if err != nil {
+		s.logger.Error("ListWebhookDeliveries", "err", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *Service) RetryWebhookDelivery(ctx context.Context, req *pb.RetryWebhookDeliveryReq) (*pb.RetryWebhookDeliveryRes, error) {
+	s.logger.Debug("RetryWebhookDelivery", "req", req)
+	res, err := s.webhooks.RetryWebhookDelivery(ctx, req)
+	if err != nil {
+		s.logger.Error("RetryWebhookDelivery", "err", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+// RunWebhookWorker delivers queued webhooks every interval until ctx is
+// done. Each request is given timeout to answer.
+func (s *Service) RunWebhookWorker(ctx context.Context, interval, timeout time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	client := &http.Client{Timeout: timeout}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.deliverWebhooks(ctx, client)
+		}
+	}
+}
+
+// deliverWebhooks drains every webhook delivery that is currently due.
+func (s *Service) deliverWebhooks(ctx context.Context, client *http.Client) {
+	for {
+		d, err := s.webhooks.ClaimWebhookDelivery(ctx)
+		if err != nil {
+			s.logger.Error("ClaimWebhookDelivery", "err", err)
+			return
+		}
+		if d == nil {
+			return
+		}
+
+		attempts := d.Attempts + 1
+		if d.URL == "" {
+			err = fmt.Errorf("webhook has been deleted")
+			if err := s.webhooks.FailWebhookDelivery(ctx, d.Id, attempts, 0, err, time.Now(), true); err != nil {
+				s.logger.Error("FailWebhookDelivery", "err", err)
+			}
+			continue
+		}
+
+		code, err := webhook.Post(ctx, client, webhook.Request{
+			URL:        d.URL,
+			Secret:     d.Secret,
+			DeliveryId: d.Id,
+			Event:      d.Event,
+			Body:       d.Payload,
+		})
+		if err == nil {
+			if err := s.webhooks.CompleteWebhookDelivery(ctx, d.Id, code); err != nil {
+				s.logger.Error("CompleteWebhookDelivery", "err", err)
+			}
+			continue
+		}
+
+		dead := attempts >= maxWebhookAttempts
+		s.logger.Error("DeliverWebhook", "id", d.Id, "attempt", attempts, "err", err)
+		if err := s.webhooks.FailWebhookDelivery(ctx, d.Id, attempts, code, err, time.Now().Add(notify.Backoff(attempts)), dead); err != nil {
+			s.logger.Error("FailWebhookDelivery", "err", err)
+		}
+	}
+}
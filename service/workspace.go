@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	pb "mainService/genproto/doccs"
+)
+
+func (s *Service) CreateWorkspace(ctx context.Context, req *pb.CreateWorkspaceReq) (*pb.CreateWorkspaceRes, error) {
+	s.logger.Debug("CreateWorkspace", "req", req)
+	res, err := s.workspaces.CreateWorkspace(ctx, req)
+	if err != nil {
+		s.logger.Error("CreateWorkspace", "err", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *Service) AddWorkspaceMember(ctx context.Context, req *pb.AddWorkspaceMemberReq) (*pb.AddWorkspaceMemberRes, error) {
+	s.logger.Debug("AddWorkspaceMember", "req", req)
+	res, err := s.workspaces.AddWorkspaceMember(ctx, req)
+	if err != nil {
+		s.logger.Error("AddWorkspaceMember", "err", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *Service) RemoveWorkspaceMember(ctx context.Context, req *pb.RemoveWorkspaceMemberReq) (*pb.RemoveWorkspaceMemberRes, error) {
+	s.logger.Debug("RemoveWorkspaceMember", "req", req)
+	res, err := s.workspaces.RemoveWorkspaceMember(ctx, req)
+	if err != nil {
+		s.logger.Error("RemoveWorkspaceMember", "err", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *Service) ListWorkspaces(ctx context.Context, req *pb.ListWorkspacesReq) (*pb.ListWorkspacesRes, error) {
+	s.logger.Debug("ListWorkspaces", "req", req)
+	res, err := s.workspaces.ListWorkspaces(ctx, req)
+	if err != nil {
+		s.logger.Error("ListWorkspaces", "err", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *Service) ListWorkspaceDocuments(ctx context.Context, req *pb.ListWorkspaceDocumentsReq) (*pb.ListWorkspaceDocumentsRes, error) {
+	s.logger.Debug("ListWorkspaceDocuments", "req", req)
+	res, err := s.workspaces.ListWorkspaceDocuments(ctx, req)
+	if err != nil {
+		s.logger.Error("ListWorkspaceDocuments", "err", err)
+		return nil, err
+	}
+	return res, nil
+}
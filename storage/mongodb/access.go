@@ -0,0 +1,328 @@
+package mongodb
+
+import (
+	"context"
+	"encoding/json"
+	pb "mainService/genproto/doccs"
+	"mainService/pkg/apperr"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	RoleOwner     = "owner"
+	RoleEditor    = "editor"
+	RoleCommenter = "commenter"
+	RoleViewer    = "viewer"
+)
+
+var roleRank = map[string]int{
+	RoleViewer:    1,
+	RoleCommenter: 2,
+	RoleEditor:    3,
+	RoleOwner:     4,
+}
+
+// NormalizeRole maps the permission strings accepted by ShareDocument
+// ("read", "write", "comment", ...) to one of the role constants.
+func NormalizeRole(permission string) string {
+	switch strings.ToLower(strings.TrimSpace(permission)) {
+	case "owner":
+		return RoleOwner
+	case "write", "edit", "editor":
+		return RoleEditor
+	case "comment", "commenter":
+		return RoleCommenter
+	case "read", "view", "viewer":
+		return RoleViewer
+	}
+	return ""
+}
+
+// RoleAtLeast reports whether role grants at least the rights of min.
+func RoleAtLeast(role, min string) bool {
+	return roleRank[role] >= roleRank[min] && roleRank[min] > 0
+}
+
+// Grant is one collaborator entry of a document. A zero ExpiresAt never
+// expires. ViaLink is the token of the share link the grant was handed out
+// through, so revoking the link takes it back.
+type Grant struct {
+	Role      string
+	GrantedBy string
+	GrantedAt time.Time
+	ExpiresAt time.Time
+	ViaLink   string
+}
+
+func (g Grant) expired(now time.Time) bool {
+	return !g.ExpiresAt.IsZero() && !now.Before(g.ExpiresAt)
+}
+
+// grants returns every collaborator entry stored on a document row,
+// including expired ones. Older rows keep them as "" or as a JSON string of
+// userId -> permission, newer ones as a sub-document of grant records.
+func grants(doc bson.M) map[string]Grant {
+	res := map[string]Grant{}
+	switch v := doc["collaboratorId"].(type) {
+	case string:
+		if v == "" {
+			return res
+		}
+		var m map[string]string
+		if err := json.Unmarshal([]byte(v), &m); err != nil {
+			return res
+		}
+		for id, p := range m {
+			res[id] = Grant{Role: NormalizeRole(p)}
+		}
+	case bson.M:
+		for id, entry := range v {
+			switch e := entry.(type) {
+			case string:
+				res[id] = Grant{Role: NormalizeRole(e)}
+			case bson.M:
+				g := Grant{}
+				g.Role, _ = e["role"].(string)
+				g.GrantedBy, _ = e["grantedBy"].(string)
+				g.GrantedAt, _ = toTime(e["grantedAt"])
+				g.ExpiresAt, _ = toTime(e["expiresAt"])
+				g.ViaLink, _ = e["viaLink"].(string)
+				res[id] = g
+			}
+		}
+	case primitive.A:
+		for _, id := range v {
+			if s, ok := id.(string); ok {
+				res[s] = Grant{Role: RoleViewer}
+			}
+		}
+	}
+	return res
+}
+
+// collaborators returns the userId -> role of the grants that are still valid.
+func collaborators(doc bson.M) map[string]string {
+	now := time.Now()
+	res := map[string]string{}
+	for id, g := range grants(doc) {
+		if g.Role != "" && !g.expired(now) {
+			res[id] = g.Role
+		}
+	}
+	return res
+}
+
+func encodeGrants(grants map[string]Grant) bson.M {
+	res := bson.M{}
+	for id, g := range grants {
+		res[id] = encodeGrant(g)
+	}
+	return res
+}
+
+func encodeGrant(g Grant) bson.M {
+	entry := bson.M{"role": g.Role, "grantedBy": g.GrantedBy}
+	if !g.GrantedAt.IsZero() {
+		entry["grantedAt"] = g.GrantedAt
+	}
+	if !g.ExpiresAt.IsZero() {
+		entry["expiresAt"] = g.ExpiresAt
+	}
+	if g.ViaLink != "" {
+		entry["viaLink"] = g.ViaLink
+	}
+	return entry
+}
+
+// grantPath is the field of the grant of userId. Grants are set and unset
+// one by one in place, so concurrent changes for different users do not
+// overwrite each other.
+func grantPath(userId string) (string, error) {
+	return userField("collaboratorId", userId)
+}
+
+// userField is the field of userId in the sub-document field, which is
+// keyed by userId.
+func userField(field, userId string) (string, error) {
+	if userId == "" || strings.ContainsAny(userId, ".$") {
+		return "", apperr.Invalid("user_id", "userId '%s' cannot be used as a key", userId)
+	}
+	return field + "." + userId, nil
+}
+
+// ensureGrantMap converts the grants of an older row, kept as "" or a JSON
+// string, into a sub-document, so single grants can be changed in place.
+// The filter on the old value leaves a row another call converted first.
+func ensureGrantMap(ctx context.Context, db *mongo.Database, head bson.M) error {
+	if _, ok := head["collaboratorId"].(bson.M); ok {
+		return nil
+	}
+	collaboratorId := encodeGrants(grants(head))
+	_, err := db.Collection("docs").UpdateOne(ctx, bson.M{"_id": head["_id"], "collaboratorId": head["collaboratorId"]}, bson.M{
+		"$set": bson.M{"collaboratorId": collaboratorId},
+	})
+	if err != nil {
+		return err
+	}
+	head["collaboratorId"] = collaboratorId
+	return nil
+}
+
+// putGrant stores g for userId on the document head, replacing any
+// previous grant of that user.
+func putGrant(ctx context.Context, db *mongo.Database, head bson.M, userId string, g Grant) error {
+	path, err := grantPath(userId)
+	if err != nil {
+		return err
+	}
+	if err := ensureGrantMap(ctx, db, head); err != nil {
+		return err
+	}
+
+	entry := encodeGrant(g)
+	_, err = db.Collection("docs").UpdateOne(ctx, bson.M{"_id": head["_id"]}, bson.M{
+		"$set": bson.M{path: entry},
+	})
+	if err != nil {
+		return err
+	}
+	head["collaboratorId"].(bson.M)[userId] = entry
+	return nil
+}
+
+// dropGrant removes the grant of userId from the document head. It returns
+// false when the user held none.
+func dropGrant(ctx context.Context, db *mongo.Database, head bson.M, userId string) (bool, error) {
+	path, err := grantPath(userId)
+	if err != nil {
+		return false, err
+	}
+	if err := ensureGrantMap(ctx, db, head); err != nil {
+		return false, err
+	}
+
+	result, err := db.Collection("docs").UpdateOne(ctx, bson.M{"_id": head["_id"], path: bson.M{"$exists": true}}, bson.M{
+		"$unset": bson.M{path: ""},
+	})
+	if err != nil {
+		return false, err
+	}
+	delete(head["collaboratorId"].(bson.M), userId)
+	return result.MatchedCount > 0, nil
+}
+
+// grantRole stores g for userId on the document head unless they already
+// hold an equal or higher role.
+func grantRole(ctx context.Context, db *mongo.Database, head bson.M, userId string, g Grant) error {
+	if RoleAtLeast(roleOf(head, userId), g.Role) {
+		return nil
+	}
+	return putGrant(ctx, db, head, userId, g)
+}
+
+// dropLinkGrants removes the grants handed out through the share link token
+// from the document head.
+func dropLinkGrants(ctx context.Context, db *mongo.Database, head bson.M, token string) error {
+	for id, g := range grants(head) {
+		if g.ViaLink != token {
+			continue
+		}
+		if _, err := dropGrant(ctx, db, head, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// roleOf returns the role userId holds on the document row, or "" if none.
+// Documents of a workspace also grant the access of the workspace members;
+// the higher of that and a direct grant wins.
+func roleOf(doc bson.M, userId string) string {
+	if userId == "" {
+		return ""
+	}
+	if authorId, _ := doc["authorId"].(string); authorId == userId {
+		return RoleOwner
+	}
+	role := collaborators(doc)[userId]
+	if access, ok := doc["workspaceAccess"].(bson.M); ok {
+		if ws, _ := access[userId].(string); roleRank[ws] > roleRank[role] {
+			role = ws
+		}
+	}
+	return role
+}
+
+// findHead loads the live (not deleted) row of the document identified by
+// docsId and title. Titles are only unique within a docsId, so both are
+// required.
+func findHead(ctx context.Context, db *mongo.Database, docsId, title string) (bson.M, error) {
+	if docsId == "" {
+		return nil, apperr.Missing("docs_id")
+	}
+	var doc bson.M
+	err := db.Collection("docs").FindOne(ctx, bson.M{"docsId": docsId, "title": title, "deletedAt": 0}).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, apperr.NotFound("document", title)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+func toDocumentRes(doc bson.M) *pb.GetDocumentRes {
+	res := &pb.GetDocumentRes{}
+	res.Title, _ = doc["title"].(string)
+	res.Content, _ = doc["content"].(string)
+	res.DocsId, _ = doc["docsId"].(string)
+	res.AuthorId, _ = doc["authorId"].(string)
+	res.UpdatedBy, _ = doc["updatedBy"].(string)
+	res.SuggestedBy, _ = doc["suggestedBy"].(string)
+	res.Version, _ = doc["version"].(int32)
+	res.LastUpdated = formatTime(doc["updatedAt"])
+	res.Body = toRichTextPb(bodyOf(doc))
+	res.Folder, _ = doc["folder"].(string)
+	if tags, ok := doc["tags"].(bson.A); ok {
+		for _, t := range tags {
+			if t, ok := t.(string); ok {
+				res.Tags = append(res.Tags, t)
+			}
+		}
+	}
+	return res
+}
+
+// formatTime renders a timestamp read back from Mongo (or still held as a
+// time.Time before insert) as RFC 3339.
+func formatTime(v interface{}) string {
+	if t, ok := toTime(v); ok {
+		return t.Format(time.RFC3339)
+	}
+	return ""
+}
+
+func toTime(v interface{}) (time.Time, bool) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, true
+	case primitive.DateTime:
+		return t.Time(), true
+	}
+	return time.Time{}, false
+}
+
+func pageOptions(limit, page int32) (int64, int64) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if page <= 0 {
+		page = 1
+	}
+	return int64(limit), int64((page - 1) * limit)
+}
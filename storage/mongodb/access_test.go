@@ -0,0 +1,98 @@
+package mongodb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// TestRoleOf tests role resolution for owners and the stored collaborator formats.
+func TestRoleOf(t *testing.T) {
+	doc := bson.M{"authorId": "owner", "collaboratorId": `{"u1":"read","u2":"write"}`}
+	assert.Equal(t, RoleOwner, roleOf(doc, "owner"))
+	assert.Equal(t, RoleViewer, roleOf(doc, "u1"))
+	assert.Equal(t, RoleEditor, roleOf(doc, "u2"))
+	assert.Equal(t, "", roleOf(doc, "stranger"))
+	assert.Equal(t, "", roleOf(doc, ""))
+
+	doc = bson.M{"authorId": "owner", "collaboratorId": ""}
+	assert.Equal(t, "", roleOf(doc, "u1"))
+
+	doc = bson.M{"authorId": "owner", "collaboratorId": bson.M{"u3": "comment"}}
+	assert.Equal(t, RoleCommenter, roleOf(doc, "u3"))
+}
+
+// TestRoleAtLeast tests the role ordering.
+func TestRoleAtLeast(t *testing.T) {
+	assert.True(t, RoleAtLeast(RoleOwner, RoleEditor))
+	assert.True(t, RoleAtLeast(RoleCommenter, RoleViewer))
+	assert.False(t, RoleAtLeast(RoleViewer, RoleCommenter))
+	assert.False(t, RoleAtLeast("", RoleViewer))
+}
+
+// TestGrantExpiry tests that expired grants are ignored at read time.
+func TestGrantExpiry(t *testing.T) {
+	doc := bson.M{"authorId": "owner", "collaboratorId": encodeGrants(map[string]Grant{
+		"active":  {Role: RoleEditor, GrantedBy: "owner", ExpiresAt: time.Now().Add(time.Hour)},
+		"expired": {Role: RoleEditor, GrantedBy: "owner", ExpiresAt: time.Now().Add(-time.Hour)},
+		"forever": {Role: RoleViewer, GrantedBy: "owner"},
+	})}
+
+	assert.Equal(t, RoleEditor, roleOf(doc, "active"))
+	assert.Equal(t, "", roleOf(doc, "expired"))
+	assert.Equal(t, RoleViewer, roleOf(doc, "forever"))
+	assert.Equal(t, "owner", grants(doc)["expired"].GrantedBy)
+}
+
+// TestGrantViaLink tests that grants remember the share link they came from.
+func TestGrantViaLink(t *testing.T) {
+	doc := bson.M{"authorId": "owner", "collaboratorId": encodeGrants(map[string]Grant{
+		"linked": {Role: RoleViewer, GrantedBy: "owner", ViaLink: "token"},
+		"shared": {Role: RoleEditor, GrantedBy: "owner"},
+	})}
+
+	assert.Equal(t, "token", grants(doc)["linked"].ViaLink)
+	assert.Equal(t, "", grants(doc)["shared"].ViaLink)
+}
+
+// TestGrantPath tests the field a single grant is changed in.
+func TestGrantPath(t *testing.T) {
+	path, err := grantPath("u1")
+	assert.NoError(t, err)
+	assert.Equal(t, "collaboratorId.u1", path)
+
+	for _, bad := range []string{"", "a.b", "$where"} {
+		_, err := grantPath(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+// TestRoleOfWorkspace tests that workspace access and direct grants combine.
+func TestRoleOfWorkspace(t *testing.T) {
+	ws := bson.M{
+		"defaultRole": RoleCommenter,
+		"members": bson.A{
+			bson.M{"userId": "owner", "role": WorkspaceOwner},
+			bson.M{"userId": "admin", "role": WorkspaceAdmin},
+			bson.M{"userId": "member", "role": WorkspaceMember},
+			bson.M{"userId": "guest", "role": WorkspaceGuest},
+		},
+	}
+	assert.Equal(t, WorkspaceAdmin, workspaceRoleOf(ws, "admin"))
+	assert.Equal(t, "", workspaceRoleOf(ws, "stranger"))
+
+	doc := bson.M{
+		"authorId":        "member",
+		"collaboratorId":  bson.M{"guest": "write"},
+		"workspaceAccess": workspaceAccessOf(ws),
+	}
+	assert.Equal(t, RoleOwner, roleOf(doc, "member"))
+	assert.Equal(t, RoleEditor, roleOf(doc, "admin"))
+	assert.Equal(t, RoleEditor, roleOf(doc, "guest"))
+	assert.Equal(t, "", roleOf(doc, "stranger"))
+
+	doc["authorId"] = "owner"
+	assert.Equal(t, RoleCommenter, roleOf(doc, "member"))
+}
@@ -0,0 +1,258 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	pb "mainService/genproto/doccs"
+	"mainService/pkg/apperr"
+	"mainService/pkg/notify"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	AccessRequestPending  = "pending"
+	AccessRequestApproved = "approved"
+	AccessRequestDenied   = "denied"
+)
+
+type AccessRequestRepository interface {
+	RequestAccess(ctx context.Context, req *pb.RequestAccessReq, email string) (*pb.RequestAccessRes, error)
+	ListAccessRequests(ctx context.Context, req *pb.ListAccessRequestsReq) (*pb.ListAccessRequestsRes, error)
+	PrepareApproval(ctx context.Context, req *pb.ApproveAccessRequestReq) (*pb.ShareDocumentReq, error)
+	MarkAccessRequestApproved(ctx context.Context, req *pb.ApproveAccessRequestReq, role string) (*pb.ApproveAccessRequestRes, error)
+	DenyAccessRequest(ctx context.Context, req *pb.DenyAccessRequestReq) (*pb.DenyAccessRequestRes, error)
+}
+
+type accessRequestRepositoryImpl struct {
+	coll *mongo.Database
+}
+
+func NewAccessRequestRepository(db *mongo.Database) AccessRequestRepository {
+	return &accessRequestRepositoryImpl{coll: db}
+}
+
+// RequestAccess stores a pending request and tells the owner about it. A
+// second request by the same user replaces the pending one. email is the
+// registered address of the requester shown to the owner; req.Email is set
+// by the client and is not trusted.
+func (r *accessRequestRepositoryImpl) RequestAccess(ctx context.Context, req *pb.RequestAccessReq, email string) (*pb.RequestAccessRes, error) {
+	if req.UserId == "" {
+		return nil, apperr.Missing("user_id")
+	}
+
+	role := NormalizeRole(req.Role)
+	if req.Role == "" {
+		role = RoleViewer
+	}
+	if role == "" || role == RoleOwner {
+		return nil, apperr.Invalid("role", "role '%s' can not be requested", req.Role)
+	}
+
+	doc, err := findHead(ctx, r.coll, req.DocsId, req.Title)
+	if err != nil {
+		return nil, err
+	}
+	if RoleAtLeast(roleOf(doc, req.UserId), role) {
+		return nil, apperr.Conflict("document", req.Title, "userId '%s' already has %s access to document '%s'", req.UserId, role, req.Title)
+	}
+
+	now := time.Now()
+	filter := bson.M{
+		"docsId": doc["docsId"],
+		"title":  doc["title"],
+		"userId": req.UserId,
+		"status": AccessRequestPending,
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"email":     email,
+			"role":      role,
+			"message":   req.Message,
+			"updatedAt": now,
+		},
+		"$setOnInsert": bson.M{
+			"_id":       uuid.NewString(),
+			"createdAt": now,
+		},
+	}
+
+	var request bson.M
+	err = withTransaction(ctx, r.coll, func(ctx context.Context) error {
+		opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+		if err := r.coll.Collection("access_requests").FindOneAndUpdate(ctx, filter, update, opts).Decode(&request); err != nil {
+			return err
+		}
+		owner, _ := doc["authorId"].(string)
+		requester := email
+		if requester == "" {
+			requester = req.UserId
+		}
+		return enqueueNotification(ctx, r.coll, notify.KindAccessRequest, "", owner, map[string]string{
+			"requester": requester,
+			"title":     req.Title,
+			"role":      role,
+			"message":   req.Message,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.RequestAccessRes{Request: toAccessRequest(request)}, nil
+}
+
+func (r *accessRequestRepositoryImpl) ListAccessRequests(ctx context.Context, req *pb.ListAccessRequestsReq) (*pb.ListAccessRequestsRes, error) {
+	doc, err := findHead(ctx, r.coll, req.DocsId, req.Title)
+	if err != nil {
+		return nil, err
+	}
+	if roleOf(doc, req.UserId) != RoleOwner {
+		return nil, apperr.NotAllowed("document", req.Title, "only the owner can see access requests of document '%s'", req.Title)
+	}
+
+	filter := bson.M{"docsId": doc["docsId"], "title": doc["title"]}
+	if !req.IncludeDecided {
+		filter["status"] = AccessRequestPending
+	}
+
+	cursor, err := r.coll.Collection("access_requests").Find(ctx, filter,
+		options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var requests []*pb.AccessRequest
+	for cursor.Next(ctx) {
+		var request bson.M
+		if err := cursor.Decode(&request); err != nil {
+			return nil, err
+		}
+		requests = append(requests, toAccessRequest(request))
+	}
+
+	return &pb.ListAccessRequestsRes{Requests: requests}, cursor.Err()
+}
+
+// PrepareApproval checks the approval and returns the ShareDocument request
+// that grants the access, so approvals use the regular sharing path.
+func (r *accessRequestRepositoryImpl) PrepareApproval(ctx context.Context, req *pb.ApproveAccessRequestReq) (*pb.ShareDocumentReq, error) {
+	request, doc, err := r.findPending(ctx, req.RequestId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	role, _ := request["role"].(string)
+	if req.Role != "" {
+		role = NormalizeRole(req.Role)
+	}
+
+	return &pb.ShareDocumentReq{
+		Title:            doc["title"].(string),
+		Permissions:      role,
+		UserId:           request["userId"].(string),
+		Id:               doc["_id"].(string),
+		DocsId:           doc["docsId"].(string),
+		GrantedBy:        req.UserId,
+		ExpiresInSeconds: req.ExpiresInSeconds,
+	}, nil
+}
+
+func (r *accessRequestRepositoryImpl) MarkAccessRequestApproved(ctx context.Context, req *pb.ApproveAccessRequestReq, role string) (*pb.ApproveAccessRequestRes, error) {
+	err := r.decide(ctx, req.RequestId, req.UserId, AccessRequestApproved, "", notify.KindAccessApproved, map[string]string{"role": role})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ApproveAccessRequestRes{Message: "Access request approved successfully"}, nil
+}
+
+func (r *accessRequestRepositoryImpl) DenyAccessRequest(ctx context.Context, req *pb.DenyAccessRequestReq) (*pb.DenyAccessRequestRes, error) {
+	if _, _, err := r.findPending(ctx, req.RequestId, req.UserId); err != nil {
+		return nil, err
+	}
+
+	err := r.decide(ctx, req.RequestId, req.UserId, AccessRequestDenied, req.Reason, notify.KindAccessDenied, map[string]string{"reason": req.Reason})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.DenyAccessRequestRes{Message: "Access request denied successfully"}, nil
+}
+
+// decide closes a pending request and queues the outcome notification for
+// the requester in the same transaction.
+func (r *accessRequestRepositoryImpl) decide(ctx context.Context, id, userId, status, reason, kind string, data map[string]string) error {
+	return withTransaction(ctx, r.coll, func(ctx context.Context) error {
+		var request bson.M
+		err := r.coll.Collection("access_requests").FindOneAndUpdate(ctx,
+			bson.M{"_id": id, "status": AccessRequestPending},
+			bson.M{"$set": bson.M{
+				"status":    status,
+				"decidedBy": userId,
+				"reason":    reason,
+				"decidedAt": time.Now(),
+			}}).Decode(&request)
+		if err == mongo.ErrNoDocuments {
+			return apperr.Conflict("access request", id, "access request '%s' is no longer pending", id)
+		}
+		if err != nil {
+			return err
+		}
+
+		data["title"], _ = request["title"].(string)
+		data["decidedBy"] = userId
+		requester, _ := request["userId"].(string)
+		return enqueueNotification(ctx, r.coll, kind, "", requester, data)
+	})
+}
+
+// findPending loads a pending request with its document and checks that
+// userId owns the document.
+func (r *accessRequestRepositoryImpl) findPending(ctx context.Context, id, userId string) (bson.M, bson.M, error) {
+	if userId == "" {
+		return nil, nil, apperr.Missing("user_id")
+	}
+
+	var request bson.M
+	err := r.coll.Collection("access_requests").FindOne(ctx, bson.M{"_id": id}).Decode(&request)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil, apperr.NotFound("access request", id)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if request["status"] != AccessRequestPending {
+		return nil, nil, apperr.Conflict("access request", id, "access request '%s' is already %s", id, request["status"])
+	}
+
+	doc, err := findHead(ctx, r.coll, request["docsId"].(string), request["title"].(string))
+	if err != nil {
+		return nil, nil, err
+	}
+	if roleOf(doc, userId) != RoleOwner {
+		return nil, nil, apperr.NotAllowed("document", fmt.Sprint(doc["title"]), "only the owner can decide access requests of document '%s'", doc["title"])
+	}
+
+	return request, doc, nil
+}
+
+func toAccessRequest(request bson.M) *pb.AccessRequest {
+	res := &pb.AccessRequest{}
+	res.Id, _ = request["_id"].(string)
+	res.DocsId, _ = request["docsId"].(string)
+	res.Title, _ = request["title"].(string)
+	res.UserId, _ = request["userId"].(string)
+	res.Email, _ = request["email"].(string)
+	res.Role, _ = request["role"].(string)
+	res.Message, _ = request["message"].(string)
+	res.Status, _ = request["status"].(string)
+	res.DecidedBy, _ = request["decidedBy"].(string)
+	res.Reason, _ = request["reason"].(string)
+	res.CreatedAt = formatTime(request["createdAt"])
+	res.DecidedAt = formatTime(request["decidedAt"])
+	return res
+}
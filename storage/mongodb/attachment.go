@@ -0,0 +1,283 @@
+package mongodb
+
+import (
+	"context"
+	pb "mainService/genproto/doccs"
+	"mainService/pkg/apperr"
+	"mainService/pkg/richtext"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Attachment is the metadata of an uploaded blob; the blob itself is stored
+// under Id in the blob store.
+type Attachment struct {
+	Id          string
+	DocsId      string
+	Title       string
+	Filename    string
+	ContentType string
+	Size        int64
+	Sha256      string
+	UploadedBy  string
+}
+
+// AttachmentRepository keeps attachment metadata. refCount counts the
+// document rows whose body refers to an attachment; once it is back at zero
+// the attachment is an orphan and gets swept. An attachment belongs to one
+// document; copies of the document get copies of the metadata, which share
+// the blob of the original.
+type AttachmentRepository interface {
+	AuthorizeUpload(ctx context.Context, req *pb.UploadAttachmentReq) (string, string, error)
+	SaveAttachment(ctx context.Context, a Attachment) (*pb.Attachment, error)
+	AuthorizeDownload(ctx context.Context, req *pb.DownloadAttachmentReq) (*pb.Attachment, string, error)
+	ListAttachments(ctx context.Context, req *pb.ListAttachmentsReq) (*pb.ListAttachmentsRes, error)
+	ClaimOrphanAttachment(ctx context.Context, grace time.Duration) (string, error)
+}
+
+type attachmentRepositoryImpl struct {
+	coll *mongo.Database
+}
+
+func NewAttachmentRepository(db *mongo.Database) AttachmentRepository {
+	return &attachmentRepositoryImpl{coll: db}
+}
+
+// AuthorizeUpload checks that the caller may edit the document and returns
+// its docsId and title.
+func (r *attachmentRepositoryImpl) AuthorizeUpload(ctx context.Context, req *pb.UploadAttachmentReq) (string, string, error) {
+	if req.UserId == "" {
+		return "", "", apperr.Missing("user_id")
+	}
+	doc, err := findHead(ctx, r.coll, req.DocsId, req.Title)
+	if err != nil {
+		return "", "", err
+	}
+	if !RoleAtLeast(roleOf(doc, req.UserId), RoleEditor) {
+		return "", "", apperr.NotAllowed("document", req.Title, "userId '%s' is not allowed to add attachments to document '%s'", req.UserId, req.Title)
+	}
+	docsId, _ := doc["docsId"].(string)
+	title, _ := doc["title"].(string)
+	return docsId, title, nil
+}
+
+func (r *attachmentRepositoryImpl) SaveAttachment(ctx context.Context, a Attachment) (*pb.Attachment, error) {
+	attachment := bson.M{
+		"_id":         a.Id,
+		"docsId":      a.DocsId,
+		"title":       a.Title,
+		"filename":    a.Filename,
+		"contentType": a.ContentType,
+		"size":        a.Size,
+		"sha256":      a.Sha256,
+		"uploadedBy":  a.UploadedBy,
+		"refCount":    int32(0),
+		"createdAt":   time.Now(),
+	}
+	if _, err := r.coll.Collection("attachments").InsertOne(ctx, attachment); err != nil {
+		return nil, err
+	}
+	return toAttachment(attachment), nil
+}
+
+// AuthorizeDownload returns the attachment and the key of its blob when the
+// caller can read the document the attachment belongs to.
+func (r *attachmentRepositoryImpl) AuthorizeDownload(ctx context.Context, req *pb.DownloadAttachmentReq) (*pb.Attachment, string, error) {
+	var attachment bson.M
+	err := r.coll.Collection("attachments").FindOne(ctx, bson.M{"_id": req.AttachmentId}).Decode(&attachment)
+	if err == mongo.ErrNoDocuments {
+		return nil, "", apperr.NotFound("attachment", req.AttachmentId)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	doc, err := findHead(ctx, r.coll, attachment["docsId"].(string), attachment["title"].(string))
+	if err != nil {
+		return nil, "", err
+	}
+	if roleOf(doc, req.UserId) == "" {
+		return nil, "", apperr.NoAccess(req.UserId, "attachment", req.AttachmentId)
+	}
+	return toAttachment(attachment), blobOf(attachment), nil
+}
+
+func (r *attachmentRepositoryImpl) ListAttachments(ctx context.Context, req *pb.ListAttachmentsReq) (*pb.ListAttachmentsRes, error) {
+	doc, err := findHead(ctx, r.coll, req.DocsId, req.Title)
+	if err != nil {
+		return nil, err
+	}
+	if roleOf(doc, req.UserId) == "" {
+		return nil, apperr.NoAccess(req.UserId, "document", req.Title)
+	}
+
+	cursor, err := r.coll.Collection("attachments").Find(ctx,
+		bson.M{"docsId": doc["docsId"], "title": doc["title"]},
+		options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var attachments []*pb.Attachment
+	for cursor.Next(ctx) {
+		var attachment bson.M
+		if err := cursor.Decode(&attachment); err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, toAttachment(attachment))
+	}
+
+	return &pb.ListAttachmentsRes{Attachments: attachments}, cursor.Err()
+}
+
+// ClaimOrphanAttachment removes the metadata of attachments no row refers
+// to until it finds one whose blob no other attachment shares, and returns
+// the key of that blob, so the caller can delete it. Fresh uploads are left
+// alone for grace, as the body referring to them may not be saved yet. It
+// returns "" when there is nothing to sweep.
+func (r *attachmentRepositoryImpl) ClaimOrphanAttachment(ctx context.Context, grace time.Duration) (string, error) {
+	coll := r.coll.Collection("attachments")
+	for {
+		var attachment bson.M
+		err := coll.FindOneAndDelete(ctx, bson.M{
+			"refCount":  bson.M{"$lte": 0},
+			"createdAt": bson.M{"$lt": time.Now().Add(-grace)},
+		}).Decode(&attachment)
+		if err == mongo.ErrNoDocuments {
+			return "", nil
+		}
+		if err != nil {
+			return "", err
+		}
+
+		blob := blobOf(attachment)
+		shared, err := coll.CountDocuments(ctx, bson.M{"$or": bson.A{
+			bson.M{"_id": blob},
+			bson.M{"blobId": blob},
+		}})
+		if err != nil {
+			return "", err
+		}
+		if shared == 0 {
+			return blob, nil
+		}
+	}
+}
+
+// addRefs adjusts the reference count of every attachment body refers to.
+// Call it with +1 for each row written with body and -1 for each row purged.
+func addRefs(ctx context.Context, db *mongo.Database, body *richtext.Document, delta int32) error {
+	ids := richtext.Attachments(body)
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := db.Collection("attachments").UpdateMany(ctx,
+		bson.M{"_id": bson.M{"$in": ids}},
+		bson.M{"$inc": bson.M{"refCount": delta}})
+	return err
+}
+
+// checkRefs rejects a body written to the document docsId and title that
+// refers to attachments of another document. Attachments are read through
+// their own document, so a foreign reference would hand them to everyone
+// who can read this one.
+func checkRefs(ctx context.Context, db *mongo.Database, body *richtext.Document, docsId, title string) error {
+	ids := richtext.Attachments(body)
+	if len(ids) == 0 {
+		return nil
+	}
+	count, err := db.Collection("attachments").CountDocuments(ctx, bson.M{
+		"_id":    bson.M{"$in": ids},
+		"docsId": docsId,
+		"title":  title,
+	})
+	if err != nil {
+		return err
+	}
+	if int(count) != len(ids) {
+		return apperr.Invalid("body", "body refers to attachments of another document")
+	}
+	return nil
+}
+
+// copyAttachments gives the document docsId and title a copy of every
+// attachment body refers to that belongs to the document from (docsId and
+// title too), and returns body referring to the copies. References to other
+// attachments are left as they are; they cannot be read through the copy.
+func copyAttachments(ctx context.Context, db *mongo.Database, body *richtext.Document, from bson.M, docsId, title string) (*richtext.Document, error) {
+	ids := richtext.Attachments(body)
+	if len(ids) == 0 {
+		return body, nil
+	}
+
+	coll := db.Collection("attachments")
+	cursor, err := coll.Find(ctx, bson.M{
+		"_id":    bson.M{"$in": ids},
+		"docsId": from["docsId"],
+		"title":  from["title"],
+	})
+	if err != nil {
+		return nil, err
+	}
+	var originals []bson.M
+	if err := cursor.All(ctx, &originals); err != nil {
+		return nil, err
+	}
+	if len(originals) == 0 {
+		return body, nil
+	}
+
+	renamed := map[string]string{}
+	copies := make([]interface{}, 0, len(originals))
+	for _, a := range originals {
+		id := uuid.NewString()
+		renamed[a["_id"].(string)] = id
+
+		c := bson.M{}
+		for k, v := range a {
+			c[k] = v
+		}
+		c["_id"] = id
+		c["docsId"] = docsId
+		c["title"] = title
+		c["blobId"] = blobOf(a)
+		c["refCount"] = int32(0)
+		c["createdAt"] = time.Now()
+		copies = append(copies, c)
+	}
+	if _, err := coll.InsertMany(ctx, copies); err != nil {
+		return nil, err
+	}
+	return richtext.RewriteAttachments(body, renamed), nil
+}
+
+// blobOf returns the key of the blob of an attachment. Copies name the blob
+// of their original; uploads are stored under their own id.
+func blobOf(attachment bson.M) string {
+	if blob, _ := attachment["blobId"].(string); blob != "" {
+		return blob
+	}
+	id, _ := attachment["_id"].(string)
+	return id
+}
+
+func toAttachment(attachment bson.M) *pb.Attachment {
+	res := &pb.Attachment{}
+	res.Id, _ = attachment["_id"].(string)
+	res.DocsId, _ = attachment["docsId"].(string)
+	res.Title, _ = attachment["title"].(string)
+	res.Filename, _ = attachment["filename"].(string)
+	res.ContentType, _ = attachment["contentType"].(string)
+	res.Size, _ = attachment["size"].(int64)
+	res.Sha256, _ = attachment["sha256"].(string)
+	res.UploadedBy, _ = attachment["uploadedBy"].(string)
+	res.RefCount, _ = attachment["refCount"].(int32)
+	res.CreatedAt = formatTime(attachment["createdAt"])
+	res.Ref = richtext.AttachmentScheme + res.Id
+	return res
+}
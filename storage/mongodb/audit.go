@@ -0,0 +1,136 @@
+package mongodb
+
+import (
+	"context"
+	pb "mainService/genproto/doccs"
+	"mainService/pkg/apperr"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	AuditSucceeded = "ok"
+	AuditFailed    = "error"
+)
+
+// AuditEntry is one line of the audit log. Entries are only ever inserted.
+type AuditEntry struct {
+	Actor     string
+	Action    string
+	Method    string
+	DocsId    string
+	Title     string
+	Target    string
+	Status    string
+	Error     string
+	ClientIp  string
+	RequestId string
+	CreatedAt time.Time
+}
+
+type AuditRepository interface {
+	RecordAudit(ctx context.Context, entry AuditEntry) error
+	ListAuditLog(ctx context.Context, req *pb.ListAuditLogReq) (*pb.ListAuditLogRes, error)
+}
+
+type auditRepositoryImpl struct {
+	coll *mongo.Database
+}
+
+func NewAuditRepository(db *mongo.Database) AuditRepository {
+	return &auditRepositoryImpl{coll: db}
+}
+
+func (r *auditRepositoryImpl) RecordAudit(ctx context.Context, entry AuditEntry) error {
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+
+	_, err := r.coll.Collection("audit").InsertOne(ctx, bson.M{
+		"_id":       uuid.NewString(),
+		"actor":     entry.Actor,
+		"action":    entry.Action,
+		"method":    entry.Method,
+		"docsId":    entry.DocsId,
+		"title":     entry.Title,
+		"target":    entry.Target,
+		"status":    entry.Status,
+		"error":     entry.Error,
+		"clientIp":  entry.ClientIp,
+		"requestId": entry.RequestId,
+		"createdAt": entry.CreatedAt,
+	})
+	return err
+}
+
+// ListAuditLog returns entries newest first. The owner may read the log of a
+// document; everybody may read the log of their own actions.
+func (r *auditRepositoryImpl) ListAuditLog(ctx context.Context, req *pb.ListAuditLogReq) (*pb.ListAuditLogRes, error) {
+	if req.UserId == "" {
+		return nil, apperr.Missing("user_id")
+	}
+
+	filter := bson.M{}
+	if req.Title != "" {
+		doc, err := findHead(ctx, r.coll, req.DocsId, req.Title)
+		if err != nil {
+			return nil, err
+		}
+		if roleOf(doc, req.UserId) != RoleOwner {
+			return nil, apperr.NotAllowed("document", req.Title, "only the owner can see the audit log of document '%s'", req.Title)
+		}
+		filter["docsId"] = doc["docsId"]
+		filter["title"] = doc["title"]
+	}
+	if req.Actor != "" {
+		if req.Title == "" && req.Actor != req.UserId {
+			return nil, apperr.NotAllowed("user", req.Actor, "userId '%s' is not allowed to see the audit log of '%s'", req.UserId, req.Actor)
+		}
+		filter["actor"] = req.Actor
+	}
+	if len(filter) == 0 {
+		filter["actor"] = req.UserId
+	}
+
+	size, skip := pageOptions(req.Limit, req.Page)
+	cursor, err := r.coll.Collection("audit").Find(ctx, filter, options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}}).
+		SetSkip(skip).
+		SetLimit(size))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var entries []*pb.AuditEntry
+	for cursor.Next(ctx) {
+		var entry bson.M
+		if err := cursor.Decode(&entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, toAuditEntry(entry))
+	}
+
+	return &pb.ListAuditLogRes{Entries: entries}, cursor.Err()
+}
+
+func toAuditEntry(entry bson.M) *pb.AuditEntry {
+	res := &pb.AuditEntry{}
+	res.Id, _ = entry["_id"].(string)
+	res.Actor, _ = entry["actor"].(string)
+	res.Action, _ = entry["action"].(string)
+	res.Method, _ = entry["method"].(string)
+	res.DocsId, _ = entry["docsId"].(string)
+	res.Title, _ = entry["title"].(string)
+	res.Target, _ = entry["target"].(string)
+	res.Status, _ = entry["status"].(string)
+	res.Error, _ = entry["error"].(string)
+	res.ClientIp, _ = entry["clientIp"].(string)
+	res.RequestId, _ = entry["requestId"].(string)
+	res.CreatedAt = formatTime(entry["createdAt"])
+	return res
+}
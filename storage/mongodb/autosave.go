@@ -0,0 +1,118 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	pb "mainService/genproto/doccs"
+	"mainService/pkg/apperr"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// AutosaveDocument saves like UpdateDocument, but while the same editor keeps
+// saving within window of the version being opened, the head is amended in
+// place instead of getting a new version. Any other write, SaveVersion or
+// the window running out closes the version again.
+func (r *documentRepositoryImpl) AutosaveDocument(ctx context.Context, req *pb.UpdateDocumentReq, window time.Duration) (*pb.UpdateDocumentRes, error) {
+	coll := r.coll.Collection("docs")
+
+	if req.AuthorId == "" {
+		return nil, apperr.Missing("author_id")
+	}
+
+	head, err := authorizedHead(ctx, r.coll, req.AuthorId, req.DocsId, req.Title, RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	content, body, err := contentOf(req)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if !amendable(head, req.AuthorId, now, window) {
+		// The new version is opened for amending by the row itself, so no
+		// other save can slip in between.
+		version, err := newVersion(ctx, r.coll, head, req.AuthorId, content, body, EventUpdated, bson.M{
+			"autosaveBy":        req.AuthorId,
+			"autosaveStartedAt": now,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &pb.UpdateDocumentRes{Message: fmt.Sprintf("Document updated successfully with version %d", version)}, nil
+	}
+
+	if err := checkRefs(ctx, r.coll, body, req.DocsId, req.Title); err != nil {
+		return nil, err
+	}
+
+	err = withTransaction(ctx, r.coll, func(ctx context.Context) error {
+		// The filter fails the amend if the head was replaced or closed since
+		// it was read.
+		result, err := coll.UpdateOne(ctx, bson.M{"_id": head["_id"], "deletedAt": 0, "autosaveBy": req.AuthorId}, bson.M{
+			"$set": bson.M{"content": content, "body": body, "updatedAt": now},
+		})
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return apperr.Conflict("document", req.Title, "document '%s' was changed concurrently, save again", req.Title)
+		}
+		if err := addRefs(ctx, r.coll, body, 1); err != nil {
+			return err
+		}
+		if err := addRefs(ctx, r.coll, bodyOf(head), -1); err != nil {
+			return err
+		}
+		return recordEvent(ctx, r.coll, EventUpdated, head, req.AuthorId)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.UpdateDocumentRes{Message: fmt.Sprintf("Document autosaved into version %d", head["version"])}, nil
+}
+
+// SaveVersion closes the version an autosave keeps amending, so the next
+// save starts a new one.
+func (r *documentRepositoryImpl) SaveVersion(ctx context.Context, req *pb.SaveVersionReq) (*pb.SaveVersionRes, error) {
+	head, err := authorizedHead(ctx, r.coll, req.UserId, req.DocsId, req.Title, RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := closeAutosave(ctx, r.coll, head); err != nil {
+		return nil, err
+	}
+
+	version, _ := head["version"].(int32)
+	return &pb.SaveVersionRes{Message: "Version saved successfully", Version: version}, nil
+}
+
+// closeAutosave stops autosaves from amending head any further. Comments and
+// suggestions call it too: they anchor to a version, so its content must not
+// change under them.
+func closeAutosave(ctx context.Context, db *mongo.Database, head bson.M) error {
+	if _, ok := head["autosaveBy"]; !ok {
+		return nil
+	}
+	_, err := db.Collection("docs").UpdateOne(ctx, bson.M{"_id": head["_id"]}, bson.M{
+		"$unset": bson.M{"autosaveBy": "", "autosaveStartedAt": ""},
+	})
+	return err
+}
+
+// amendable reports whether an autosave by userId at now may amend head: the
+// head must have been opened by an autosave of the same user less than
+// window ago.
+func amendable(head bson.M, userId string, now time.Time, window time.Duration) bool {
+	if by, _ := head["autosaveBy"].(string); by == "" || by != userId {
+		return false
+	}
+	started, ok := toTime(head["autosaveStartedAt"])
+	return ok && now.Before(started.Add(window))
+}
@@ -0,0 +1,20 @@
+package mongodb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// TestAmendable tests when an autosave may amend the head instead of adding a version.
+func TestAmendable(t *testing.T) {
+	start := time.Now()
+	head := bson.M{"autosaveBy": "u1", "autosaveStartedAt": start}
+
+	assert.True(t, amendable(head, "u1", start.Add(time.Minute), 2*time.Minute))
+	assert.False(t, amendable(head, "u1", start.Add(2*time.Minute), 2*time.Minute), "window closed")
+	assert.False(t, amendable(head, "u2", start.Add(time.Minute), 2*time.Minute), "other user")
+	assert.False(t, amendable(bson.M{}, "u1", start, 2*time.Minute), "closed version")
+}
@@ -0,0 +1,131 @@
+package mongodb
+
+import (
+	"context"
+	pb "mainService/genproto/doccs"
+	"mainService/pkg/apperr"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type CollaboratorRepository interface {
+	UnshareDocument(ctx context.Context, req *pb.UnshareDocumentReq) (*pb.UnshareDocumentRes, error)
+	ListCollaborators(ctx context.Context, req *pb.ListCollaboratorsReq) (*pb.ListCollaboratorsRes, error)
+	SweepExpiredGrants(ctx context.Context) (int, error)
+}
+
+type collaboratorRepositoryImpl struct {
+	coll *mongo.Database
+}
+
+func NewCollaboratorRepository(db *mongo.Database) CollaboratorRepository {
+	return &collaboratorRepositoryImpl{coll: db}
+}
+
+// UnshareDocument removes a grant. Editors and the owner may remove anyone
+// but the owner; every collaborator may remove themselves.
+func (r *collaboratorRepositoryImpl) UnshareDocument(ctx context.Context, req *pb.UnshareDocumentReq) (*pb.UnshareDocumentRes, error) {
+	if req.UserId == "" {
+		return nil, apperr.Missing("user_id")
+	}
+
+	doc, err := findHead(ctx, r.coll, req.DocsId, req.Title)
+	if err != nil {
+		return nil, err
+	}
+	if req.CollaboratorId != req.UserId && !RoleAtLeast(roleOf(doc, req.UserId), RoleEditor) {
+		return nil, apperr.NotAllowed("document", req.Title, "userId '%s' is not allowed to unshare document '%s'", req.UserId, req.Title)
+	}
+
+	err = withTransaction(ctx, r.coll, func(ctx context.Context) error {
+		dropped, err := dropGrant(ctx, r.coll, doc, req.CollaboratorId)
+		if err != nil {
+			return err
+		}
+		if !dropped {
+			return apperr.NotFound("member", req.CollaboratorId)
+		}
+		return recordEvent(ctx, r.coll, EventUnshared, doc, req.UserId)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.UnshareDocumentRes{Message: "Document unshared successfully"}, nil
+}
+
+func (r *collaboratorRepositoryImpl) ListCollaborators(ctx context.Context, req *pb.ListCollaboratorsReq) (*pb.ListCollaboratorsRes, error) {
+	doc, err := findHead(ctx, r.coll, req.DocsId, req.Title)
+	if err != nil {
+		return nil, err
+	}
+	if roleOf(doc, req.UserId) == "" {
+		return nil, apperr.NoAccess(req.UserId, "document", req.Title)
+	}
+
+	authorId, _ := doc["authorId"].(string)
+	res := []*pb.Collaborator{{UserId: authorId, Role: RoleOwner}}
+
+	now := time.Now()
+	var shared []*pb.Collaborator
+	for id, g := range grants(doc) {
+		if g.Role == "" || g.expired(now) {
+			continue
+		}
+		c := &pb.Collaborator{UserId: id, Role: g.Role, GrantedBy: g.GrantedBy}
+		if !g.GrantedAt.IsZero() {
+			c.GrantedAt = g.GrantedAt.Format(time.RFC3339)
+		}
+		if !g.ExpiresAt.IsZero() {
+			c.ExpiresAt = g.ExpiresAt.Format(time.RFC3339)
+		}
+		shared = append(shared, c)
+	}
+	sort.Slice(shared, func(i, j int) bool { return shared[i].UserId < shared[j].UserId })
+
+	return &pb.ListCollaboratorsRes{Collaborators: append(res, shared...)}, nil
+}
+
+// SweepExpiredGrants removes expired grants from live documents and returns
+// how many were removed. Reads already ignore them; this keeps rows tidy.
+func (r *collaboratorRepositoryImpl) SweepExpiredGrants(ctx context.Context) (int, error) {
+	coll := r.coll.Collection("docs")
+
+	cursor, err := coll.Find(ctx, bson.M{
+		"deletedAt":      0,
+		"collaboratorId": bson.M{"$type": "object"},
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	now := time.Now()
+	removed := 0
+	for cursor.Next(ctx) {
+		var doc bson.M
+		if err := cursor.Decode(&doc); err != nil {
+			return removed, err
+		}
+
+		unset := bson.M{}
+		for id, g := range grants(doc) {
+			if g.expired(now) {
+				unset["collaboratorId."+id] = ""
+			}
+		}
+		if len(unset) == 0 {
+			continue
+		}
+
+		if _, err := coll.UpdateOne(ctx, bson.M{"_id": doc["_id"]}, bson.M{"$unset": unset}); err != nil {
+			return removed, err
+		}
+		removed += len(unset)
+	}
+
+	return removed, cursor.Err()
+}
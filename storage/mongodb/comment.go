@@ -0,0 +1,476 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	pb "mainService/genproto/doccs"
+	"mainService/pkg/apperr"
+	"mainService/pkg/notify"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var mentionPattern = regexp.MustCompile(`@([A-Za-z0-9._\-]+(?:@[A-Za-z0-9.\-]+\.[A-Za-z]+)?)`)
+
+type CommentRepository interface {
+	AddComment(ctx context.Context, req *pb.AddCommentReq) (*pb.AddCommentRes, error)
+	ReplyComment(ctx context.Context, req *pb.ReplyCommentReq) (*pb.ReplyCommentRes, error)
+	ResolveComment(ctx context.Context, req *pb.ResolveCommentReq) (*pb.ResolveCommentRes, error)
+	ReopenComment(ctx context.Context, req *pb.ReopenCommentReq) (*pb.ReopenCommentRes, error)
+	ListComments(ctx context.Context, req *pb.ListCommentsReq) (*pb.ListCommentsRes, error)
+	ReanchorComments(ctx context.Context, docsId, title string) error
+}
+
+type commentRepositoryImpl struct {
+	coll *mongo.Database
+}
+
+func NewCommentRepository(db *mongo.Database) CommentRepository {
+	return &commentRepositoryImpl{coll: db}
+}
+
+func (r *commentRepositoryImpl) AddComment(ctx context.Context, req *pb.AddCommentReq) (*pb.AddCommentRes, error) {
+	if req.UserId == "" {
+		return nil, apperr.Missing("user_id")
+	}
+	if strings.TrimSpace(req.Text) == "" {
+		return nil, apperr.Missing("text")
+	}
+
+	doc, err := findHead(ctx, r.coll, req.DocsId, req.Title)
+	if err != nil {
+		return nil, err
+	}
+	if !RoleAtLeast(roleOf(doc, req.UserId), RoleCommenter) {
+		return nil, apperr.NotAllowed("document", req.Title, "userId '%s' is not allowed to comment on document '%s'", req.UserId, req.Title)
+	}
+	if err := closeAutosave(ctx, r.coll, doc); err != nil {
+		return nil, err
+	}
+
+	text, _ := doc["content"].(string)
+	content := []rune(text)
+	if req.Start < 0 || req.End < req.Start || int(req.End) > len(content) {
+		return nil, apperr.Invalid("start", "range [%d, %d) is outside of the document", req.Start, req.End)
+	}
+
+	id := uuid.NewString()
+	now := time.Now()
+	comment := bson.M{
+		"_id":        id,
+		"threadId":   id,
+		"parentId":   "",
+		"docsId":     doc["docsId"],
+		"title":      doc["title"],
+		"userId":     req.UserId,
+		"text":       req.Text,
+		"mentions":   parseMentions(req.Text),
+		"start":      req.Start,
+		"end":        req.End,
+		"quotedText": string(content[req.Start:req.End]),
+		"version":    doc["version"],
+		"detached":   false,
+		"resolved":   false,
+		"resolvedBy": "",
+		"createdAt":  now,
+		"updatedAt":  now,
+	}
+
+	err = withTransaction(ctx, r.coll, func(ctx context.Context) error {
+		if _, err := r.coll.Collection("comments").InsertOne(ctx, comment); err != nil {
+			return err
+		}
+		owner, _ := doc["authorId"].(string)
+		return notifyComment(ctx, r.coll, comment, owner)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.AddCommentRes{Comment: toComment(comment)}, nil
+}
+
+func (r *commentRepositoryImpl) ReplyComment(ctx context.Context, req *pb.ReplyCommentReq) (*pb.ReplyCommentRes, error) {
+	if req.UserId == "" {
+		return nil, apperr.Missing("user_id")
+	}
+	if strings.TrimSpace(req.Text) == "" {
+		return nil, apperr.Missing("text")
+	}
+
+	parent, err := r.findComment(ctx, req.CommentId)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.checkRole(ctx, parent, req.UserId, RoleCommenter); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	reply := bson.M{
+		"_id":       uuid.NewString(),
+		"threadId":  parent["threadId"],
+		"parentId":  parent["_id"],
+		"docsId":    parent["docsId"],
+		"title":     parent["title"],
+		"userId":    req.UserId,
+		"text":      req.Text,
+		"mentions":  parseMentions(req.Text),
+		"createdAt": now,
+		"updatedAt": now,
+	}
+
+	thread, err := r.findComment(ctx, parent["threadId"].(string))
+	if err != nil {
+		return nil, err
+	}
+
+	err = withTransaction(ctx, r.coll, func(ctx context.Context) error {
+		if _, err := r.coll.Collection("comments").InsertOne(ctx, reply); err != nil {
+			return err
+		}
+		threadAuthor, _ := thread["userId"].(string)
+		return notifyComment(ctx, r.coll, reply, threadAuthor)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ReplyCommentRes{Comment: toComment(reply)}, nil
+}
+
+func (r *commentRepositoryImpl) ResolveComment(ctx context.Context, req *pb.ResolveCommentReq) (*pb.ResolveCommentRes, error) {
+	if err := r.setResolved(ctx, req.CommentId, req.UserId, true); err != nil {
+		return nil, err
+	}
+	return &pb.ResolveCommentRes{Message: "Comment resolved successfully"}, nil
+}
+
+func (r *commentRepositoryImpl) ReopenComment(ctx context.Context, req *pb.ReopenCommentReq) (*pb.ReopenCommentRes, error) {
+	if err := r.setResolved(ctx, req.CommentId, req.UserId, false); err != nil {
+		return nil, err
+	}
+	return &pb.ReopenCommentRes{Message: "Comment reopened successfully"}, nil
+}
+
+// setResolved resolves or reopens a whole thread. Only the thread author or
+// someone who can edit the document may do so.
+func (r *commentRepositoryImpl) setResolved(ctx context.Context, commentId, userId string, resolved bool) error {
+	if userId == "" {
+		return apperr.Missing("user_id")
+	}
+
+	comment, err := r.findComment(ctx, commentId)
+	if err != nil {
+		return err
+	}
+
+	thread, err := r.findComment(ctx, comment["threadId"].(string))
+	if err != nil {
+		return err
+	}
+
+	minRole := RoleEditor
+	if thread["userId"] == userId {
+		minRole = RoleCommenter
+	}
+	if _, err := r.checkRole(ctx, thread, userId, minRole); err != nil {
+		return err
+	}
+
+	resolvedBy := ""
+	if resolved {
+		resolvedBy = userId
+	}
+
+	_, err = r.coll.Collection("comments").UpdateOne(ctx, bson.M{"_id": thread["_id"]}, bson.M{
+		"$set": bson.M{
+			"resolved":   resolved,
+			"resolvedBy": resolvedBy,
+			"updatedAt":  time.Now(),
+		},
+	})
+	return err
+}
+
+func (r *commentRepositoryImpl) ListComments(ctx context.Context, req *pb.ListCommentsReq) (*pb.ListCommentsRes, error) {
+	doc, err := findHead(ctx, r.coll, req.DocsId, req.Title)
+	if err != nil {
+		return nil, err
+	}
+	if roleOf(doc, req.UserId) == "" {
+		return nil, apperr.NoAccess(req.UserId, "document", req.Title)
+	}
+
+	cursor, err := r.coll.Collection("comments").Find(ctx,
+		bson.M{"docsId": doc["docsId"], "title": doc["title"]},
+		options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var threads []*pb.Comment
+	byThread := map[string]*pb.Comment{}
+
+	for cursor.Next(ctx) {
+		var c bson.M
+		if err := cursor.Decode(&c); err != nil {
+			return nil, err
+		}
+
+		comment := toComment(c)
+		if comment.ParentId == "" {
+			if comment.Resolved && !req.IncludeResolved {
+				continue
+			}
+			byThread[comment.Id] = comment
+			threads = append(threads, comment)
+			continue
+		}
+
+		if thread, ok := byThread[c["threadId"].(string)]; ok {
+			thread.Replies = append(thread.Replies, comment)
+		}
+	}
+
+	return &pb.ListCommentsRes{Comments: threads}, cursor.Err()
+}
+
+// ReanchorComments moves the ranges of open threads that were anchored to an
+// older version onto the current head of the document.
+func (r *commentRepositoryImpl) ReanchorComments(ctx context.Context, docsId, title string) error {
+	head, err := findHead(ctx, r.coll, docsId, title)
+	if err != nil {
+		return err
+	}
+	headVersion, _ := head["version"].(int32)
+	headContent, _ := head["content"].(string)
+
+	coll := r.coll.Collection("comments")
+	cursor, err := coll.Find(ctx, bson.M{
+		"docsId":   head["docsId"],
+		"title":    title,
+		"parentId": "",
+		"resolved": false,
+		"detached": false,
+		"version":  bson.M{"$ne": headVersion},
+	})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	// contents caches the text of the old versions; nil marks a version
+	// whose row is gone, so its comments cannot be moved.
+	contents := map[int32]*string{}
+	for cursor.Next(ctx) {
+		var c bson.M
+		if err := cursor.Decode(&c); err != nil {
+			return err
+		}
+
+		version, _ := c["version"].(int32)
+		oldContent, cached := contents[version]
+		if !cached {
+			var row bson.M
+			err := r.coll.Collection("docs").FindOne(ctx, bson.M{
+				"docsId":  head["docsId"],
+				"title":   title,
+				"version": version,
+			}).Decode(&row)
+			if err != nil && err != mongo.ErrNoDocuments {
+				return err
+			}
+			if err == nil {
+				content, _ := row["content"].(string)
+				oldContent = &content
+			}
+			contents[version] = oldContent
+		}
+
+		start, _ := c["start"].(int32)
+		end, _ := c["end"].(int32)
+		quoted, _ := c["quotedText"].(string)
+		newStart, newEnd, ok := int(start), int(end), false
+		if oldContent != nil {
+			newStart, newEnd, ok = reanchor(*oldContent, headContent, int(start), int(end), quoted)
+		}
+
+		_, err = coll.UpdateOne(ctx, bson.M{"_id": c["_id"]}, bson.M{
+			"$set": bson.M{
+				"start":     int32(newStart),
+				"end":       int32(newEnd),
+				"version":   headVersion,
+				"detached":  !ok,
+				"updatedAt": time.Now(),
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+// notifyComment queues a mention notification for everyone mentioned in the
+// comment and a comment notification for recipient, skipping the commenter.
+func notifyComment(ctx context.Context, db *mongo.Database, comment bson.M, recipient string) error {
+	by, _ := comment["userId"].(string)
+	data := map[string]string{
+		"by":    by,
+		"title": comment["title"].(string),
+		"text":  comment["text"].(string),
+	}
+
+	mentions, _ := comment["mentions"].([]string)
+	notified := map[string]bool{by: true}
+	for _, m := range mentions {
+		if notified[m] {
+			continue
+		}
+		notified[m] = true
+
+		// m is a userId or an email; either is resolved to a registered
+		// user on delivery.
+		if err := enqueueNotification(ctx, db, notify.KindMention, "", m, data); err != nil {
+			return err
+		}
+	}
+
+	if recipient == "" || notified[recipient] {
+		return nil
+	}
+	return enqueueNotification(ctx, db, notify.KindComment, "", recipient, data)
+}
+
+func (r *commentRepositoryImpl) findComment(ctx context.Context, id string) (bson.M, error) {
+	var c bson.M
+	err := r.coll.Collection("comments").FindOne(ctx, bson.M{"_id": id}).Decode(&c)
+	if err == mongo.ErrNoDocuments {
+		return nil, apperr.NotFound("comment", id)
+	}
+	return c, err
+}
+
+// checkRole verifies that userId holds at least minRole on the document the
+// comment belongs to.
+func (r *commentRepositoryImpl) checkRole(ctx context.Context, comment bson.M, userId, minRole string) (bson.M, error) {
+	doc, err := findHead(ctx, r.coll, comment["docsId"].(string), comment["title"].(string))
+	if err != nil {
+		return nil, err
+	}
+	if !RoleAtLeast(roleOf(doc, userId), minRole) {
+		return nil, apperr.NotAllowed("document", fmt.Sprint(comment["title"]), "userId '%s' is not allowed to change comments on document '%s'", userId, comment["title"])
+	}
+	return doc, nil
+}
+
+func toComment(c bson.M) *pb.Comment {
+	res := &pb.Comment{}
+	res.Id, _ = c["_id"].(string)
+	res.DocsId, _ = c["docsId"].(string)
+	res.Title, _ = c["title"].(string)
+	res.UserId, _ = c["userId"].(string)
+	res.Text, _ = c["text"].(string)
+	res.Start, _ = c["start"].(int32)
+	res.End, _ = c["end"].(int32)
+	res.QuotedText, _ = c["quotedText"].(string)
+	res.Version, _ = c["version"].(int32)
+	res.Resolved, _ = c["resolved"].(bool)
+	res.Detached, _ = c["detached"].(bool)
+	res.ParentId, _ = c["parentId"].(string)
+
+	switch mentions := c["mentions"].(type) {
+	case []string:
+		res.Mentions = mentions
+	case primitive.A:
+		for _, m := range mentions {
+			if s, ok := m.(string); ok {
+				res.Mentions = append(res.Mentions, s)
+			}
+		}
+	}
+
+	res.CreatedAt = formatTime(c["createdAt"])
+	return res
+}
+
+func parseMentions(text string) []string {
+	mentions := []string{}
+	seen := map[string]bool{}
+	for _, m := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		name := strings.TrimRight(m[1], ".-")
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		mentions = append(mentions, name)
+	}
+	return mentions
+}
+
+// reanchor maps the rune range [start, end) of oldText onto newText. Ranges
+// before or after the edited region are kept or shifted; ranges that overlap
+// the edit are looked up by their quoted text, nearest to the old position.
+// ok is false when the quoted text no longer exists.
+func reanchor(oldText, newText string, start, end int, quoted string) (int, int, bool) {
+	o, n := []rune(oldText), []rune(newText)
+	if end > len(o) {
+		end = len(o)
+	}
+	if start > end {
+		start = end
+	}
+
+	prefix := 0
+	for prefix < len(o) && prefix < len(n) && o[prefix] == n[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(o)-prefix && suffix < len(n)-prefix && o[len(o)-1-suffix] == n[len(n)-1-suffix] {
+		suffix++
+	}
+
+	if end <= prefix {
+		return start, end, true
+	}
+	if start >= len(o)-suffix {
+		delta := len(n) - len(o)
+		return start + delta, end + delta, true
+	}
+
+	q := []rune(quoted)
+	if len(q) == 0 {
+		return min(start, len(n)), min(start, len(n)), false
+	}
+
+	best := -1
+	for i := 0; i+len(q) <= len(n); i++ {
+		if string(n[i:i+len(q)]) != quoted {
+			continue
+		}
+		if best == -1 || abs(i-start) < abs(best-start) {
+			best = i
+		}
+	}
+	if best == -1 {
+		return min(start, len(n)), min(start, len(n)), false
+	}
+	return best, best + len(q), true
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
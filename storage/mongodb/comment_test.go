@@ -0,0 +1,37 @@
+package mongodb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestReanchor tests moving comment ranges across an edit.
+func TestReanchor(t *testing.T) {
+	old := "hello brave new world"
+
+	// edit after the range keeps it in place
+	start, end, ok := reanchor(old, "hello brave new world!!", 0, 5, "hello")
+	assert.True(t, ok)
+	assert.Equal(t, []int{0, 5}, []int{start, end})
+
+	// edit before the range shifts it
+	start, end, ok = reanchor(old, "oh, hello brave new world", 16, 21, "world")
+	assert.True(t, ok)
+	assert.Equal(t, []int{20, 25}, []int{start, end})
+
+	// edit inside the range finds the quoted text again
+	start, end, ok = reanchor(old, "hello new brave world", 6, 11, "brave")
+	assert.True(t, ok)
+	assert.Equal(t, []int{10, 15}, []int{start, end})
+
+	// quoted text removed detaches the comment
+	_, _, ok = reanchor(old, "hello new world", 6, 11, "brave")
+	assert.False(t, ok)
+}
+
+// TestParseMentions tests extracting @mentions from comment text.
+func TestParseMentions(t *testing.T) {
+	assert.Equal(t, []string{"alice", "bob@example.com"}, parseMentions("@alice please ask @bob@example.com, @alice."))
+	assert.Empty(t, parseMentions("no mentions here"))
+}
@@ -2,9 +2,11 @@ package mongodb
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	pb "mainService/genproto/doccs"
+	"mainService/pkg/apperr"
+	"mainService/pkg/notify"
+	"mainService/pkg/richtext"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,11 +17,24 @@ import (
 
 type DocumentRepository interface {
 	CreateDocument(ctx context.Context, req *pb.CreateDocumentReq) (*pb.CreateDocumentRes, error)
+	CreateFromTemplate(ctx context.Context, req *pb.CreateDocumentReq, authorName string) (*pb.CreateDocumentRes, error)
+	GetDocument(ctx context.Context, req *pb.GetDocumentReq) (*pb.GetDocumentRes, error)
+	CopyDocument(ctx context.Context, req *pb.CopyDocumentReq) (*pb.CopyDocumentRes, error)
 	SearchDocument(ctx context.Context, req *pb.SearchDocumentReq) (*pb.SearchDocumentRes, error)
 	GetAllDocuments(ctx context.Context, req *pb.GetAllDocumentsReq) (*pb.GetAllDocumentsRes, error)
 	UpdateDocument(ctx context.Context, req *pb.UpdateDocumentReq) (*pb.UpdateDocumentRes, error)
+	ApplySuggestedUpdate(ctx context.Context, req *pb.UpdateDocumentReq, suggestedBy string) (*pb.UpdateDocumentRes, error)
 	DeleteDocument(ctx context.Context, req *pb.DeleteDocumentReq) (*pb.DeleteDocumentRes, error)
 	ShareDocument(ctx context.Context, req *pb.ShareDocumentReq) (*pb.ShareDocumentRes, error)
+	TransferOwnership(ctx context.Context, req *pb.TransferOwnershipReq, newOwnerId string) (*pb.TransferOwnershipRes, error)
+	ImportDocument(ctx context.Context, authorId, title string, body *richtext.Document) (*pb.ImportDocumentRes, error)
+	PurgeDocument(ctx context.Context, req *pb.PurgeDocumentReq) (*pb.PurgeDocumentRes, error)
+	ReadDocument(ctx context.Context, req *pb.StreamDocumentReq) (*richtext.Document, error)
+	AuthorizeDocument(ctx context.Context, userId, docsId, title, minRole string) error
+	MoveDocument(ctx context.Context, userId, docsId, title, folder string) error
+	TagDocument(ctx context.Context, userId, docsId, title string, tags []string, remove bool) error
+	AutosaveDocument(ctx context.Context, req *pb.UpdateDocumentReq, window time.Duration) (*pb.UpdateDocumentRes, error)
+	SaveVersion(ctx context.Context, req *pb.SaveVersionReq) (*pb.SaveVersionRes, error)
 }
 
 type documentRepositoryImpl struct {
@@ -30,26 +45,57 @@ func NewDocumentRepository(db *mongo.Database) DocumentRepository {
 	return &documentRepositoryImpl{coll: db}
 }
 
+// CreateDocument creates an empty document, or one from req.TemplateId. Use
+// CreateFromTemplate to render {{author}} as a name; here it is the userId.
 func (r *documentRepositoryImpl) CreateDocument(ctx context.Context, req *pb.CreateDocumentReq) (*pb.CreateDocumentRes, error) {
-	coll := r.coll.Collection("docs")
-	var existingDoc bson.M
+	return r.CreateFromTemplate(ctx, req, req.AuthorId)
+}
 
-	err := coll.FindOne(ctx, bson.M{"authorId": req.AuthorId}).Decode(&existingDoc)
-	if err != nil && err != mongo.ErrNoDocuments {
-		return nil, err
-	}
+// CreateFromTemplate is CreateDocument with the display name of the author
+// for the {{author}} placeholder of the template.
+func (r *documentRepositoryImpl) CreateFromTemplate(ctx context.Context, req *pb.CreateDocumentReq, authorName string) (*pb.CreateDocumentRes, error) {
+	coll := r.coll.Collection("docs")
 
+	// A workspace is a document space of its own: its id is the docsId of
+	// every document in it.
+	var workspace bson.M
 	var docsId string
-	if err == mongo.ErrNoDocuments {
-		docsId = uuid.NewString()
+	var err error
+	if req.WorkspaceId != "" {
+		workspace, err = findWorkspace(ctx, r.coll, req.WorkspaceId)
+		if err != nil {
+			return nil, err
+		}
+		if workspaceRank[workspaceRoleOf(workspace, req.AuthorId)] < workspaceRank[WorkspaceMember] {
+			return nil, apperr.NotAllowed("workspace", req.WorkspaceId, "userId '%s' is not allowed to create documents in workspace '%s'", req.AuthorId, req.WorkspaceId)
+		}
+		docsId = req.WorkspaceId
 	} else {
-		docsId = existingDoc["docsId"].(string)
+		docsId, err = authorDocsId(ctx, coll, req.AuthorId)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	_, err = coll.InsertOne(ctx, bson.M{
+	title, content := req.Title, ""
+	var body *richtext.Document
+	if req.TemplateId != "" {
+		template, templateBody, err := findTemplate(ctx, r.coll, req.TemplateId, req.AuthorId)
+		if err != nil {
+			return nil, err
+		}
+		now := time.Now()
+		if title == "" {
+			title = renderTemplate(template.TitlePattern, templateVars(authorName, "", now))
+		}
+		body = renderBody(templateBody, templateVars(authorName, title, now))
+		content = richtext.ToPlain(body)
+	}
+
+	doc := bson.M{
 		"_id":            uuid.New().String(),
-		"title":          req.Title,
-		"content":        "",
+		"title":          title,
+		"content":        content,
 		"docsId":         docsId,
 		"authorId":       req.AuthorId,
 		"collaboratorId": "",
@@ -57,17 +103,379 @@ func (r *documentRepositoryImpl) CreateDocument(ctx context.Context, req *pb.Cre
 		"createdAt":      time.Now(),
 		"updatedAt":      time.Now(),
 		"deletedAt":      0,
+	}
+	if workspace != nil {
+		// All members share the titles of a workspace, so a taken one is
+		// refused.
+		if _, err := findHead(ctx, r.coll, docsId, title); err == nil {
+			return nil, apperr.AlreadyExists("document", title)
+		}
+		doc["workspaceId"] = workspace["_id"]
+		doc["workspaceAccess"] = workspaceAccessOf(workspace)
+	}
+	err = withTransaction(ctx, r.coll, func(ctx context.Context) error {
+		if body != nil {
+			// The document gets its own copies of the images of the template.
+			body, err := copyAttachments(ctx, r.coll, body, templateKey(req.TemplateId), docsId, title)
+			if err != nil {
+				return err
+			}
+			doc["body"] = body
+			if err := addRefs(ctx, r.coll, body, 1); err != nil {
+				return err
+			}
+		}
+		if _, err := coll.InsertOne(ctx, doc); err != nil {
+			return err
+		}
+		return recordEvent(ctx, r.coll, EventCreated, doc, req.AuthorId)
 	})
 
 	if mongo.IsDuplicateKeyError(err) {
-		return nil, fmt.Errorf("title '%s' is already taken", req.Title)
+		return nil, apperr.AlreadyExists("document", title)
 	} else if err != nil {
 		return nil, err
 	}
 
-	return &pb.CreateDocumentRes{Title: req.Title, AuthorId: req.AuthorId,DocsId: docsId}, nil
+	return &pb.CreateDocumentRes{Title: title, AuthorId: req.AuthorId,DocsId: docsId}, nil
+}
+// ImportDocument creates a document with the given body. A taken title gets
+// a numbered suffix instead of failing the upload.
+func (r *documentRepositoryImpl) ImportDocument(ctx context.Context, authorId, title string, body *richtext.Document) (*pb.ImportDocumentRes, error) {
+	coll := r.coll.Collection("docs")
+
+	if authorId == "" {
+		return nil, apperr.Missing("author_id")
+	}
+
+	docsId, err := authorDocsId(ctx, coll, authorId)
+	if err != nil {
+		return nil, err
+	}
+	title, err = freeTitle(ctx, coll, docsId, title)
+	if err != nil {
+		return nil, err
+	}
+	// A new document has no attachments yet, so an imported body cannot
+	// refer to any.
+	if err := checkRefs(ctx, r.coll, body, docsId, title); err != nil {
+		return nil, err
+	}
+
+	doc := bson.M{
+		"_id":            uuid.New().String(),
+		"title":          title,
+		"content":        richtext.ToPlain(body),
+		"body":           body,
+		"docsId":         docsId,
+		"authorId":       authorId,
+		"collaboratorId": "",
+		"version":        0,
+		"createdAt":      time.Now(),
+		"updatedAt":      time.Now(),
+		"deletedAt":      0,
+	}
+	err = withTransaction(ctx, r.coll, func(ctx context.Context) error {
+		if _, err := coll.InsertOne(ctx, doc); err != nil {
+			return err
+		}
+		if err := addRefs(ctx, r.coll, body, 1); err != nil {
+			return err
+		}
+		return recordEvent(ctx, r.coll, EventCreated, doc, authorId)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ImportDocumentRes{Title: title, AuthorId: authorId, DocsId: docsId}, nil
 }
 
+// PurgeDocument removes every version of a deleted document for good, with
+// the comments, suggestions and other rows that belong to it. Its
+// attachments are released to the attachment sweeper, which deletes them
+// with their blobs. Only the owner may purge.
+func (r *documentRepositoryImpl) PurgeDocument(ctx context.Context, req *pb.PurgeDocumentReq) (*pb.PurgeDocumentRes, error) {
+	coll := r.coll.Collection("docs")
+
+	if req.AuthorId == "" {
+		return nil, apperr.Missing("author_id")
+	}
+	if req.DocsId == "" {
+		return nil, apperr.Missing("docs_id")
+	}
+
+	filter := bson.M{"docsId": req.DocsId, "title": req.Title}
+	cursor, err := coll.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "version", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	var rows []bson.M
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, apperr.NotFound("document", req.Title)
+	}
+	if roleOf(rows[0], req.AuthorId) != RoleOwner {
+		return nil, apperr.NotAllowed("document", req.Title, "only the owner can purge document '%s'", req.Title)
+	}
+	live, err := coll.CountDocuments(ctx, bson.M{"docsId": req.DocsId, "title": req.Title, "deletedAt": 0})
+	if err != nil {
+		return nil, err
+	}
+	if live > 0 {
+		return nil, apperr.Conflict("document", req.Title, "document '%s' must be deleted before it can be purged", req.Title)
+	}
+
+	err = withTransaction(ctx, r.coll, func(ctx context.Context) error {
+		for _, row := range rows {
+			if err := addRefs(ctx, r.coll, bodyOf(row), -1); err != nil {
+				return err
+			}
+		}
+		if _, err := coll.DeleteMany(ctx, filter); err != nil {
+			return err
+		}
+		for _, name := range documentCollections {
+			if name == "attachments" {
+				continue
+			}
+			if _, err := r.coll.Collection(name).DeleteMany(ctx, filter); err != nil {
+				return err
+			}
+		}
+		// Attachments no body refers to any more are orphans; the sweeper
+		// deletes them and their blobs once the grace period is over.
+		_, err := r.coll.Collection("attachments").UpdateMany(ctx, filter, bson.M{"$set": bson.M{"refCount": int32(0)}})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.PurgeDocumentRes{Message: "Document purged successfully", PurgedVersions: int32(len(rows))}, nil
+}
+
+// ReadDocument returns the body of the head, or of an older version when one
+// is asked for. Access is checked against the head.
+func (r *documentRepositoryImpl) ReadDocument(ctx context.Context, req *pb.StreamDocumentReq) (*richtext.Document, error) {
+	head, err := findHead(ctx, r.coll, req.DocsId, req.Title)
+	if err != nil {
+		return nil, err
+	}
+	if roleOf(head, req.UserId) == "" {
+		return nil, apperr.NoAccess(req.UserId, "document", req.Title)
+	}
+
+	doc := head
+	if req.Version > 0 && req.Version != head["version"] {
+		err := r.coll.Collection("docs").FindOne(ctx, bson.M{
+			"docsId":  head["docsId"],
+			"title":   head["title"],
+			"version": req.Version,
+		}).Decode(&doc)
+		if err == mongo.ErrNoDocuments {
+			return nil, apperr.NotFound("version", fmt.Sprint(req.Version))
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bodyOf(doc), nil
+}
+
+// authorDocsId returns the docsId shared by all personal documents of an
+// author, or a fresh one for an author without documents. Workspace
+// documents carry the workspace id instead and are skipped.
+func authorDocsId(ctx context.Context, coll *mongo.Collection, authorId string) (string, error) {
+	var existingDoc bson.M
+
+	err := coll.FindOne(ctx, bson.M{"authorId": authorId, "workspaceId": bson.M{"$in": bson.A{nil, ""}}}).Decode(&existingDoc)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return "", err
+	}
+
+	if err == mongo.ErrNoDocuments {
+		return uuid.NewString(), nil
+	}
+	return existingDoc["docsId"].(string), nil
+}
+
+func (r *documentRepositoryImpl) CopyDocument(ctx context.Context, req *pb.CopyDocumentReq) (*pb.CopyDocumentRes, error) {
+	coll := r.coll.Collection("docs")
+
+	if req.UserId == "" {
+		return nil, apperr.Missing("user_id")
+	}
+
+	source, err := findHead(ctx, r.coll, req.DocsId, req.Title)
+	if err != nil {
+		return nil, err
+	}
+	if roleOf(source, req.UserId) == "" {
+		return nil, apperr.NoAccess(req.UserId, "document", req.Title)
+	}
+
+	docsId, err := authorDocsId(ctx, coll, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	title := req.NewTitle
+	if title == "" {
+		title = "Copy of " + req.Title
+	}
+	title, err = freeTitle(ctx, coll, docsId, title)
+	if err != nil {
+		return nil, err
+	}
+
+	var collaboratorId interface{} = ""
+	if req.IncludeSharing {
+		now := time.Now()
+		copied := grants(source)
+		for id, g := range copied {
+			if g.expired(now) {
+				delete(copied, id)
+			}
+		}
+		delete(copied, req.UserId)
+		if authorId, _ := source["authorId"].(string); authorId != req.UserId {
+			copied[authorId] = Grant{Role: RoleEditor, GrantedBy: req.UserId, GrantedAt: now}
+		}
+		collaboratorId = encodeGrants(copied)
+	}
+
+	doc := bson.M{
+		"_id":            uuid.New().String(),
+		"title":          title,
+		"content":        source["content"],
+		"body":           source["body"],
+		"docsId":         docsId,
+		"authorId":       req.UserId,
+		"collaboratorId": collaboratorId,
+		"version":        0,
+		"createdAt":      time.Now(),
+		"updatedAt":      time.Now(),
+		"deletedAt":      0,
+	}
+	err = withTransaction(ctx, r.coll, func(ctx context.Context) error {
+		// The copy gets its own copies of the images, so it does not hand
+		// out those of the source to its readers.
+		if body := bodyOf(source); body != nil {
+			body, err := copyAttachments(ctx, r.coll, body, source, docsId, title)
+			if err != nil {
+				return err
+			}
+			doc["body"] = body
+			if err := addRefs(ctx, r.coll, body, 1); err != nil {
+				return err
+			}
+		}
+		if _, err := coll.InsertOne(ctx, doc); err != nil {
+			return err
+		}
+		if req.IncludeComments {
+			if err := copyComments(ctx, r.coll, source, docsId, title); err != nil {
+				return err
+			}
+		}
+		return recordEvent(ctx, r.coll, EventCreated, doc, req.UserId)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.CopyDocumentRes{Title: title, AuthorId: req.UserId, DocsId: docsId}, nil
+}
+
+// freeTitle appends " (n)" to title until no row of docsId uses it. Older
+// versions and documents in the trash count too: rows moved or created under
+// their title would be mixed into their history.
+func freeTitle(ctx context.Context, coll *mongo.Collection, docsId, title string) (string, error) {
+	candidate := title
+	for n := 2; ; n++ {
+		count, err := coll.CountDocuments(ctx, bson.M{"docsId": docsId, "title": candidate})
+		if err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s (%d)", title, n)
+	}
+}
+
+// copyComments duplicates the comment threads of source onto the new
+// document, keeping the thread structure.
+func copyComments(ctx context.Context, db *mongo.Database, source bson.M, docsId, title string) error {
+	coll := db.Collection("comments")
+
+	cursor, err := coll.Find(ctx, bson.M{"docsId": source["docsId"], "title": source["title"]},
+		options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	ids := map[interface{}]string{}
+	var copies []interface{}
+
+	for cursor.Next(ctx) {
+		var c bson.M
+		if err := cursor.Decode(&c); err != nil {
+			return err
+		}
+
+		id := uuid.NewString()
+		ids[c["_id"]] = id
+
+		c["_id"] = id
+		c["docsId"] = docsId
+		c["title"] = title
+		if threadId, ok := ids[c["threadId"]]; ok {
+			c["threadId"] = threadId
+		}
+		if parentId, ok := ids[c["parentId"]]; ok {
+			c["parentId"] = parentId
+		}
+		if _, ok := c["version"]; ok {
+			c["version"] = int32(0)
+		}
+		copies = append(copies, c)
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+	if len(copies) == 0 {
+		return nil
+	}
+
+	_, err = coll.InsertMany(ctx, copies)
+	return err
+}
+
+func (r *documentRepositoryImpl) GetDocument(ctx context.Context, req *pb.GetDocumentReq) (*pb.GetDocumentRes, error) {
+	if req.Title == "" {
+		return nil, apperr.Missing("title")
+	}
+	if req.DocsId == "" {
+		return nil, apperr.Missing("docs_id")
+	}
+
+	doc, err := findHead(ctx, r.coll, req.DocsId, req.Title)
+	if err != nil {
+		return nil, err
+	}
+
+	if roleOf(doc, req.AuthorId) == "" {
+		return nil, apperr.NoAccess(req.AuthorId, "document", req.Title)
+	}
+
+	return toDocumentRes(doc), nil
+}
 
 func (r *documentRepositoryImpl) SearchDocument(ctx context.Context, req *pb.SearchDocumentReq) (*pb.SearchDocumentRes, error) {
 	coll := r.coll.Collection("docs")
@@ -91,58 +499,36 @@ func (r *documentRepositoryImpl) SearchDocument(ctx context.Context, req *pb.Sea
 			return nil, err
 		}
 
-		if doc["authorId"] == req.AuthorId {
-			result := &pb.GetDocumentRes{
-				Title:          doc["title"].(string),
-				Content:        doc["content"].(string),
-				DocsId:         doc["docsId"].(string),
-				AuthorId:       doc["authorId"].(string),
-				Version: 		doc["version"].(int32),	
-			}
-			results = append(results, result)
-		} else {
-			collaboratorIds, ok := doc["collaboratorId"].([]string)
-			if !ok || !contains(collaboratorIds, req.AuthorId) {
-				continue
-			}
-
-			result := &pb.GetDocumentRes{
-				Title:          doc["title"].(string),
-				Content:        doc["content"].(string),
-				DocsId:         doc["docsId"].(string),
-				AuthorId:       doc["authorId"].(string),
-				Version:        doc["version"].(int32),
-			}
-			results = append(results, result)
+		if roleOf(doc, req.AuthorId) == "" {
+			continue
 		}
+
+		results = append(results, toDocumentRes(doc))
 	}
 
 	if len(results) == 0 {
-		return nil, fmt.Errorf("authorId '%s' hujjatlar topilmadi", req.AuthorId)
+		return nil, apperr.NotFound("document", req.Title)
 	}
 
 	return &pb.SearchDocumentRes{Documents: results}, nil
 }
 
-func contains(ids []string, id string) bool {
-	for _, v := range ids {
-		if v == id {
-			return true
-		}
-	}
-	return false
-}
-
 func (r *documentRepositoryImpl) GetAllDocuments(ctx context.Context, req *pb.GetAllDocumentsReq) (*pb.GetAllDocumentsRes, error) {
 	coll := r.coll.Collection("docs")
 
 	if req.DocsId == "" {
-		return nil, fmt.Errorf("docs id '%s' is not set", req.DocsId)
+		return nil, apperr.Missing("docs_id")
 	}
 
 	filter := bson.M{
 		"docsId": req.DocsId,
 	}
+	if req.Folder != "" {
+		filter["folder"] = req.Folder
+	}
+	if req.Tag != "" {
+		filter["tags"] = req.Tag
+	}
 
 	findOptions := options.Find()
 
@@ -173,105 +559,134 @@ func (r *documentRepositoryImpl) GetAllDocuments(ctx context.Context, req *pb.Ge
 			return nil, err
 		}
 
-		authorId, ok := doc["authorId"].(string)
-		if !ok {
+		if roleOf(doc, req.AuthorId) == "" {
 			continue
 		}
 
-		if authorId == req.AuthorId {
-			result := &pb.GetDocumentRes{
-				Title:          doc["title"].(string),
-				Content:        doc["content"].(string),
-				DocsId:         doc["docsId"].(string),
-				AuthorId:       doc["authorId"].(string),
-				Version:        doc["version"].(int32),
-			}
-			docs = append(docs, result)
-			authorFound = true
-		} else {
-			collaboratorIds, ok := doc["collaboratorId"].([]string)
-			if ok && contains(collaboratorIds, req.AuthorId) {
-				result := &pb.GetDocumentRes{
-					Title:          doc["title"].(string),
-					Content:        doc["content"].(string),
-					DocsId:         doc["docsId"].(string),
-					AuthorId:       doc["authorId"].(string),
-					Version:        doc["version"].(int32),
-				}
-				docs = append(docs, result)
-				authorFound = true
-			}
-		}
+		docs = append(docs, toDocumentRes(doc))
+		authorFound = true
 	}
 
 	if !authorFound {
-		return nil, fmt.Errorf("no documents found for docsId '%s' with the given authorId or in collaboratorId", req.DocsId)
+		return nil, apperr.NotFound("document", req.DocsId)
 	}
 
 	return &pb.GetAllDocumentsRes{Documents: docs}, nil
 }
 
 
+// UpdateDocument saves req as the next version of the document. AuthorId
+// is the user making the edit: any editor may save, the document keeps its
+// owner and the new version records the editor in updatedBy.
 func (r *documentRepositoryImpl) UpdateDocument(ctx context.Context, req *pb.UpdateDocumentReq) (*pb.UpdateDocumentRes, error) {
-	coll := r.coll.Collection("docs")
+	return r.update(ctx, req, nil)
+}
 
-	if req.AuthorId == "" {
-		return nil, fmt.Errorf("authorId '%s' is not set", req.AuthorId)
+// ApplySuggestedUpdate is UpdateDocument for an accepted suggestion: the new
+// version records the accepting editor in updatedBy and the user that made
+// the suggestion in suggestedBy.
+func (r *documentRepositoryImpl) ApplySuggestedUpdate(ctx context.Context, req *pb.UpdateDocumentReq, suggestedBy string) (*pb.UpdateDocumentRes, error) {
+	if suggestedBy == "" {
+		return nil, apperr.Missing("suggested_by")
 	}
+	return r.update(ctx, req, bson.M{"suggestedBy": suggestedBy})
+}
 
-	if req.DocsId == ""{
-		return nil,fmt.Errorf("docs id '%s' is not set",req.DocsId)
+// update saves req as the next version, with the fields of set on it.
+func (r *documentRepositoryImpl) update(ctx context.Context, req *pb.UpdateDocumentReq, set bson.M) (*pb.UpdateDocumentRes, error) {
+	if req.AuthorId == "" {
+		return nil, apperr.Missing("author_id")
 	}
 
-	filter := bson.D{{Key: "authorId", Value: req.AuthorId}, {Key: "docsId", Value: req.DocsId}, {Key: "deletedAt", Value: 0}}
-
-	var existingDoc bson.M
-	err := coll.FindOne(ctx, filter).Decode(&existingDoc)
+	head, err := authorizedHead(ctx, r.coll, req.AuthorId, req.DocsId, req.Title, RoleEditor)
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			return nil, fmt.Errorf("document with authorId '%s' and docsId '%s' not found", req.AuthorId, req.DocsId)
-		}
 		return nil, err
 	}
 
-	newVersion := existingDoc["version"].(int32) + 1
-
-	_, err = coll.UpdateOne(ctx, filter, bson.D{
-		{Key: "$set", Value: bson.D{
-			{Key: "deletedAt", Value: time.Now().Unix()},
-		}},
-	})
+	content, body, err := contentOf(req)
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = coll.InsertOne(ctx, bson.M{
-		"_id":            uuid.New().String(),
-		"title":          req.Title,
-		"content":        req.Content,
-		"docsId":         req.DocsId,
-		"authorId":       req.AuthorId,
-		"collaboratorId": existingDoc["collaboratorId"].(string),
-		"version":        newVersion,
-		"createdAt":      existingDoc["createdAt"],
-		"updatedAt":      time.Now(),
-		"deletedAt":      0,
-	})
+	version, err := newVersion(ctx, r.coll, head, req.AuthorId, content, body, EventUpdated, set)
 	if err != nil {
 		return nil, err
 	}
 
-	return &pb.UpdateDocumentRes{Message: "Document updated successfully with version " + fmt.Sprintf("%d", newVersion)}, nil
+	return &pb.UpdateDocumentRes{Message: "Document updated successfully with version " + fmt.Sprintf("%d", version)}, nil
 }
 
+// newVersion closes head and inserts content and body, saved by editorId,
+// as the version after it, in one transaction with the event kind. The
+// fields of set are stored on the new row as well. It fails if head was
+// replaced since it was read.
+func newVersion(ctx context.Context, db *mongo.Database, head bson.M, editorId, content string, body *richtext.Document, kind string, set bson.M) (int32, error) {
+	coll := db.Collection("docs")
+
+	version := head["version"].(int32) + 1
+	title, _ := head["title"].(string)
+
+	if err := checkRefs(ctx, db, body, head["docsId"].(string), title); err != nil {
+		return 0, err
+	}
+
+	err := withTransaction(ctx, db, func(ctx context.Context) error {
+		result, err := coll.UpdateOne(ctx, bson.M{"_id": head["_id"], "deletedAt": 0}, bson.D{
+			{Key: "$set", Value: bson.D{
+				{Key: "deletedAt", Value: time.Now().Unix()},
+			}},
+		})
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return apperr.Conflict("document", title, "document '%s' was changed concurrently, save again", title)
+		}
 
+		row := bson.M{
+			"_id":             uuid.New().String(),
+			"title":           title,
+			"content":         content,
+			"body":            body,
+			"docsId":          head["docsId"],
+			"authorId":        head["authorId"],
+			"updatedBy":       editorId,
+			"collaboratorId":  head["collaboratorId"],
+			"folder":          head["folder"],
+			"tags":            head["tags"],
+			"workspaceId":     head["workspaceId"],
+			"workspaceAccess": head["workspaceAccess"],
+			"version":         version,
+			"createdAt":       head["createdAt"],
+			"updatedAt":       time.Now(),
+			"deletedAt":       0,
+		}
+		for k, v := range set {
+			row[k] = v
+		}
+		if _, err := coll.InsertOne(ctx, row); err != nil {
+			return err
+		}
+		if err := addRefs(ctx, db, body, 1); err != nil {
+			return err
+		}
+		return recordEvent(ctx, db, kind, row, editorId)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return version, nil
+}
+
+// DeleteDocument moves the document to the trash. Any editor may delete it.
 func (r *documentRepositoryImpl) DeleteDocument(ctx context.Context, req *pb.DeleteDocumentReq) (*pb.DeleteDocumentRes, error) {
-	coll := r.coll.Collection("docs")
+	if req.AuthorId == "" {
+		return nil, apperr.Missing("author_id")
+	}
 
-	filter := bson.D{
-		{Key: "authorId", Value: req.AuthorId},
-		{Key: "title", Value: req.Title},
-		{Key: "deletedAt", Value: 0},
+	head, err := authorizedHead(ctx, r.coll, req.AuthorId, req.DocsId, req.Title, RoleEditor)
+	if err != nil {
+		return nil, err
 	}
 
 	update := bson.D{
@@ -280,11 +695,18 @@ func (r *documentRepositoryImpl) DeleteDocument(ctx context.Context, req *pb.Del
 		}},
 	}
 
-	result, err := coll.UpdateOne(ctx, filter, update)
-	if err != nil || result.MatchedCount == 0 {
-		return &pb.DeleteDocumentRes{
-			Message: "Document deletion failed",
-		}, fmt.Errorf("failed to delete document: %w", err)
+	err = withTransaction(ctx, r.coll, func(ctx context.Context) error {
+		result, err := r.coll.Collection("docs").UpdateOne(ctx, bson.M{"_id": head["_id"], "deletedAt": 0}, update)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return apperr.NotFound("document", req.Title)
+		}
+		return recordEvent(ctx, r.coll, EventDeleted, head, req.AuthorId)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &pb.DeleteDocumentRes{
@@ -292,49 +714,176 @@ func (r *documentRepositoryImpl) DeleteDocument(ctx context.Context, req *pb.Del
 	}, nil
 }
 
+// ShareDocument grants req.UserId the requested role on the document. The
+// grant replaces any earlier one of that user and may carry an expiry.
 func (r *documentRepositoryImpl) ShareDocument(ctx context.Context, req *pb.ShareDocumentReq) (*pb.ShareDocumentRes, error) {
 	coll := r.coll.Collection("docs")
+
+	if req.UserId == "" {
+		return nil, apperr.Missing("user_id")
+	}
+	if req.GrantedBy == "" {
+		return nil, apperr.Missing("granted_by")
+	}
+
+	role := NormalizeRole(req.Permissions)
+	if role == "" || role == RoleOwner {
+		return nil, apperr.Invalid("permissions", "permission '%s' is not supported", req.Permissions)
+	}
+	if req.ExpiresInSeconds < 0 {
+		return nil, apperr.Invalid("expires_in_seconds", "expiry '%d' must not be negative", req.ExpiresInSeconds)
+	}
+
 	filter := bson.M{
-		"title": req.Title,
-		"_id":   req.Id,
+		"title":     req.Title,
+		"_id":       req.Id,
+		"deletedAt": 0,
 	}
 
 	var existingDoc bson.M
 	err := coll.FindOne(ctx, filter).Decode(&existingDoc)
-	if err != nil && err != mongo.ErrNoDocuments {
-		return nil, fmt.Errorf("error while finding document: %v", err)
+	if err == mongo.ErrNoDocuments {
+		existingDoc, err = findHead(ctx, r.coll, req.DocsId, req.Title)
 	}
-
-	newCollaboration := bson.M{
-		req.UserId: req.Permissions,
+	if err != nil {
+		return nil, err
 	}
 
-	if existingCollaboration, ok := existingDoc["collaboratorId"].(bson.M); ok {
-		for key, value := range newCollaboration {
-			existingCollaboration[key] = value
-		}
-		newCollaboration = existingCollaboration
+	if !RoleAtLeast(roleOf(existingDoc, req.GrantedBy), RoleEditor) {
+		return nil, apperr.NotAllowed("document", req.Title, "userId '%s' is not allowed to share document '%s'", req.GrantedBy, req.Title)
+	}
+	if roleOf(existingDoc, req.UserId) == RoleOwner {
+		return nil, apperr.Invalid("user_id", "userId '%s' already owns document '%s'", req.UserId, req.Title)
 	}
 
-	collaborationBytes, err := json.Marshal(newCollaboration)
-	if err != nil {
-		return nil, fmt.Errorf("error while marshalling collaboration data: %v", err)
+	grant := Grant{Role: role, GrantedBy: req.GrantedBy, GrantedAt: time.Now()}
+	if req.ExpiresInSeconds > 0 {
+		grant.ExpiresAt = grant.GrantedAt.Add(time.Duration(req.ExpiresInSeconds) * time.Second)
 	}
 
-	update := bson.M{
-		"$set": bson.M{
-			"collaboratorId": string(collaborationBytes),
-		},
+	data := map[string]string{
+		"sharedBy": req.GrantedBy,
+		"title":    req.Title,
+		"role":     role,
+	}
+	if !grant.ExpiresAt.IsZero() {
+		data["expiresAt"] = grant.ExpiresAt.Format(time.RFC3339)
 	}
 
-	_, err = coll.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	err = withTransaction(ctx, r.coll, func(ctx context.Context) error {
+		if err := putGrant(ctx, r.coll, existingDoc, req.UserId, grant); err != nil {
+			return err
+		}
+		// The recipient is notified at their registered address, never at
+		// one named by the caller.
+		if err := enqueueNotification(ctx, r.coll, notify.KindShare, "", req.UserId, data); err != nil {
+			return err
+		}
+		return recordEvent(ctx, r.coll, EventShared, existingDoc, req.GrantedBy)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("error while updating document: %v", err)
+		return nil, err
 	}
 
 	res := &pb.ShareDocumentRes{
 		Message: "Document shared successfully!",
 	}
+	res.DocsId, _ = existingDoc["docsId"].(string)
 
 	return res, nil
 }
+
+// TransferOwnership moves the document to newOwnerId on the head and every
+// older version, and keeps the previous owner on it as an editor.
+func (r *documentRepositoryImpl) TransferOwnership(ctx context.Context, req *pb.TransferOwnershipReq, newOwnerId string) (*pb.TransferOwnershipRes, error) {
+	coll := r.coll.Collection("docs")
+
+	if req.UserId == "" {
+		return nil, apperr.Missing("user_id")
+	}
+	if newOwnerId == "" || newOwnerId == req.UserId {
+		return nil, apperr.Invalid("new_owner_email", "new owner must be a different user")
+	}
+
+	head, err := findHead(ctx, r.coll, req.DocsId, req.Title)
+	if err != nil {
+		return nil, err
+	}
+	if roleOf(head, req.UserId) != RoleOwner {
+		return nil, apperr.NotAllowed("document", req.Title, "only the owner can transfer document '%s'", req.Title)
+	}
+
+	all := grants(head)
+	delete(all, newOwnerId)
+	all[req.UserId] = Grant{Role: RoleEditor, GrantedBy: req.UserId, GrantedAt: time.Now()}
+
+	// A personal document moves into the document space of the new owner,
+	// under a free title there. Workspace documents stay in the workspace.
+	docsId, _ := head["docsId"].(string)
+	title, _ := head["title"].(string)
+	if workspaceId, _ := head["workspaceId"].(string); workspaceId == "" {
+		docsId, err = authorDocsId(ctx, coll, newOwnerId)
+		if err != nil {
+			return nil, err
+		}
+		title, err = freeTitle(ctx, coll, docsId, title)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = withTransaction(ctx, r.coll, func(ctx context.Context) error {
+		_, err := coll.UpdateMany(ctx, bson.M{"docsId": head["docsId"], "title": head["title"]}, bson.M{
+			"$set": bson.M{
+				"authorId":       newOwnerId,
+				"collaboratorId": encodeGrants(all),
+				"docsId":         docsId,
+				"title":          title,
+			},
+		})
+		if err != nil {
+			return err
+		}
+		if err := relocateDocument(ctx, r.coll, head, docsId, title); err != nil {
+			return err
+		}
+		// The event names the document as its watchers knew it.
+		transferred := bson.M{}
+		for k, v := range head {
+			transferred[k] = v
+		}
+		transferred["authorId"] = newOwnerId
+		transferred["collaboratorId"] = encodeGrants(all)
+		return recordEvent(ctx, r.coll, EventTransferred, transferred, req.UserId)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.TransferOwnershipRes{
+		Message:    "Ownership transferred successfully",
+		NewOwnerId: newOwnerId,
+		DocsId:     docsId,
+		Title:      title,
+	}, nil
+}
+
+// documentCollections hold rows that belong to one document by docsId and
+// title and follow it when it moves.
+var documentCollections = []string{"comments", "suggestions", "attachments", "share_links", "access_requests", "starred", "recent"}
+
+// relocateDocument points the rows of head in documentCollections at the
+// new docsId and title.
+func relocateDocument(ctx context.Context, db *mongo.Database, head bson.M, docsId, title string) error {
+	if head["docsId"] == docsId && head["title"] == title {
+		return nil
+	}
+	filter := bson.M{"docsId": head["docsId"], "title": head["title"]}
+	update := bson.M{"$set": bson.M{"docsId": docsId, "title": title}}
+	for _, name := range documentCollections {
+		if _, err := db.Collection(name).UpdateMany(ctx, filter, update); err != nil {
+			return err
+		}
+	}
+	return nil
+}
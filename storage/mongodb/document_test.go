@@ -120,8 +120,10 @@ func TestShareDocument(t *testing.T) {
 	shareReq := &pb.ShareDocumentReq{
 		Title:       createRes.Title,
 		Id:          createRes.Title,
+		DocsId:      createRes.DocsId,
 		UserId:      "collaborator_id",
 		Permissions: "read",
+		GrantedBy:   createRes.AuthorId,
 	}
 	shareRes, err := repo.ShareDocument(context.Background(), shareReq)
 	assert.NoError(t, err)
@@ -143,6 +145,7 @@ func TestDeleteDocument(t *testing.T) {
 	deleteReq := &pb.DeleteDocumentReq{
 		Title:    createRes.Title,
 		AuthorId: createRes.AuthorId,
+		DocsId:   createRes.DocsId,
 	}
 	deleteRes, err := repo.DeleteDocument(context.Background(), deleteReq)
 	assert.NoError(t, err)
@@ -0,0 +1,199 @@
+package mongodb
+
+import (
+	"context"
+	pb "mainService/genproto/doccs"
+	"mainService/pkg/apperr"
+	"mainService/pkg/eventbus"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	EventCreated     = "created"
+	EventUpdated     = "updated"
+	EventShared      = "shared"
+	EventUnshared    = "unshared"
+	EventRestored    = "restored"
+	EventDeleted     = "deleted"
+	EventTransferred = "transferred"
+)
+
+// EventRepository delivers document events to watchers. On a replica set the
+// events go through the document_events collection and a change stream, so
+// every instance sees them; on a standalone server they stay in process.
+type EventRepository interface {
+	PublishEvent(ctx context.Context, event *pb.DocumentEvent) error
+	WatchEvents(ctx context.Context, req *pb.WatchDocumentReq, fn func(*pb.DocumentEvent) error) error
+}
+
+type eventRepositoryImpl struct {
+	coll *mongo.Database
+	bus  *eventbus.Bus[*pb.DocumentEvent]
+
+	mu      sync.Mutex
+	known   bool
+	streams bool
+}
+
+func NewEventRepository(db *mongo.Database) EventRepository {
+	return &eventRepositoryImpl{coll: db, bus: eventbus.New[*pb.DocumentEvent]()}
+}
+
+func (r *eventRepositoryImpl) PublishEvent(ctx context.Context, event *pb.DocumentEvent) error {
+	if event.Id == "" {
+		event.Id = uuid.NewString()
+	}
+	if event.CreatedAt == "" {
+		event.CreatedAt = time.Now().Format(time.RFC3339)
+	}
+
+	if !r.useStreams(ctx) {
+		r.bus.Publish(event)
+		return nil
+	}
+
+	_, err := r.coll.Collection("document_events").InsertOne(ctx, bson.M{
+		"_id":       event.Id,
+		"kind":      event.Kind,
+		"docsId":    event.DocsId,
+		"title":     event.Title,
+		"authorId":  event.AuthorId,
+		"actor":     event.Actor,
+		"createdAt": event.CreatedAt,
+	})
+	return err
+}
+
+// WatchEvents calls fn with every event of one document until ctx is done
+// or fn fails. The caller needs at least viewer access, which is checked
+// again for every event; the watch ends with an error once it is lost, and
+// without one after the document was deleted or transferred.
+func (r *eventRepositoryImpl) WatchEvents(ctx context.Context, req *pb.WatchDocumentReq, fn func(*pb.DocumentEvent) error) error {
+	doc, err := r.watchable(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	if !r.useStreams(ctx) {
+		events, cancel := r.bus.Subscribe()
+		defer cancel()
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case event := <-events:
+				if !eventMatches(event, doc) {
+					continue
+				}
+				if done, err := r.deliver(ctx, req, event, fn); done || err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: bson.M{
+		"operationType":      "insert",
+		"fullDocument.title": doc["title"],
+	}}}}
+	stream, err := r.coll.Collection("document_events").Watch(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	defer stream.Close(context.Background())
+
+	for stream.Next(ctx) {
+		var change struct {
+			FullDocument bson.M `bson:"fullDocument"`
+		}
+		if err := stream.Decode(&change); err != nil {
+			return err
+		}
+		event := toDocumentEvent(change.FullDocument)
+		if !eventMatches(event, doc) {
+			continue
+		}
+		if done, err := r.deliver(ctx, req, event, fn); done || err != nil {
+			return err
+		}
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+	return stream.Err()
+}
+
+// watchable loads the watched document and checks the watcher's access.
+func (r *eventRepositoryImpl) watchable(ctx context.Context, req *pb.WatchDocumentReq) (bson.M, error) {
+	doc, err := findHead(ctx, r.coll, req.DocsId, req.Title)
+	if err != nil {
+		return nil, err
+	}
+	if roleOf(doc, req.UserId) == "" {
+		return nil, apperr.NoAccess(req.UserId, "document", req.Title)
+	}
+	return doc, nil
+}
+
+// deliver passes event to fn. done is set when the watch is over: the
+// document is gone from under its docsId and title, or the watcher lost
+// access to it.
+func (r *eventRepositoryImpl) deliver(ctx context.Context, req *pb.WatchDocumentReq, event *pb.DocumentEvent, fn func(*pb.DocumentEvent) error) (bool, error) {
+	switch event.Kind {
+	case EventDeleted, EventTransferred:
+		return true, fn(event)
+	}
+	if _, err := r.watchable(ctx, req); err != nil {
+		return true, err
+	}
+	return false, fn(event)
+}
+
+// useStreams reports whether the server supports change streams, which it
+// does for replica sets and sharded clusters. A successful answer is cached;
+// after a failed check it is asked again next time.
+func (r *eventRepositoryImpl) useStreams(ctx context.Context) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.known {
+		return r.streams
+	}
+
+	var hello bson.M
+	if err := r.coll.RunCommand(ctx, bson.M{"hello": 1}).Decode(&hello); err != nil {
+		return false
+	}
+	_, replicaSet := hello["setName"]
+	r.streams = replicaSet || hello["msg"] == "isdbgrid"
+	r.known = true
+	return r.streams
+}
+
+// eventMatches reports whether event belongs to doc. Some operations only know
+// the author and title of a document, so either docsId or authorId may match.
+func eventMatches(event *pb.DocumentEvent, doc bson.M) bool {
+	if event.Title != doc["title"] {
+		return false
+	}
+	if event.DocsId != "" {
+		return event.DocsId == doc["docsId"]
+	}
+	return event.AuthorId != "" && event.AuthorId == doc["authorId"]
+}
+
+func toDocumentEvent(event bson.M) *pb.DocumentEvent {
+	res := &pb.DocumentEvent{}
+	res.Id, _ = event["_id"].(string)
+	res.Kind, _ = event["kind"].(string)
+	res.DocsId, _ = event["docsId"].(string)
+	res.Title, _ = event["title"].(string)
+	res.AuthorId, _ = event["authorId"].(string)
+	res.Actor, _ = event["actor"].(string)
+	res.CreatedAt, _ = event["createdAt"].(string)
+	return res
+}
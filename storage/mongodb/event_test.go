@@ -0,0 +1,20 @@
+package mongodb
+
+import (
+	pb "mainService/genproto/doccs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// TestEventMatches tests matching events by docsId or, failing that, authorId.
+func TestEventMatches(t *testing.T) {
+	doc := bson.M{"docsId": "d1", "title": "Plan", "authorId": "owner"}
+
+	assert.True(t, eventMatches(&pb.DocumentEvent{DocsId: "d1", Title: "Plan"}, doc))
+	assert.False(t, eventMatches(&pb.DocumentEvent{DocsId: "d2", Title: "Plan", AuthorId: "owner"}, doc))
+	assert.False(t, eventMatches(&pb.DocumentEvent{DocsId: "d1", Title: "Notes"}, doc))
+	assert.True(t, eventMatches(&pb.DocumentEvent{AuthorId: "owner", Title: "Plan"}, doc))
+	assert.False(t, eventMatches(&pb.DocumentEvent{Title: "Plan"}, doc))
+}
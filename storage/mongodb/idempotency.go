@@ -0,0 +1,113 @@
+package mongodb
+
+import (
+	"context"
+	"mainService/pkg/apperr"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// idempotencyPendingTimeout is how long a key stays claimed by a call that
+// has not finished. After that a crashed call no longer blocks retries.
+const idempotencyPendingTimeout = time.Minute
+
+// IdempotencyRecord is what an earlier call with the same key left behind.
+// Response is only set once that call is Done.
+type IdempotencyRecord struct {
+	RequestHash string
+	Done        bool
+	Response    []byte
+}
+
+type IdempotencyRepository interface {
+	EnsureIdempotencyIndex(ctx context.Context) error
+	BeginIdempotent(ctx context.Context, key, requestHash string) (*IdempotencyRecord, error)
+	CompleteIdempotent(ctx context.Context, key string, response []byte) error
+	AbandonIdempotent(ctx context.Context, key string) error
+}
+
+type idempotencyRepositoryImpl struct {
+	coll *mongo.Database
+	ttl  time.Duration
+}
+
+// NewIdempotencyRepository keeps the response of a finished call for ttl.
+func NewIdempotencyRepository(db *mongo.Database, ttl time.Duration) IdempotencyRepository {
+	return &idempotencyRepositoryImpl{coll: db, ttl: ttl}
+}
+
+// EnsureIdempotencyIndex creates the TTL index that lets Mongo drop expired
+// keys.
+func (r *idempotencyRepositoryImpl) EnsureIdempotencyIndex(ctx context.Context) error {
+	_, err := r.coll.Collection("idempotency_keys").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	return err
+}
+
+// BeginIdempotent claims key for a new call and returns nil. If an earlier
+// call holds the key, its record is returned instead. Mongo removes expired
+// keys only about once a minute, so an expired key is taken over here.
+func (r *idempotencyRepositoryImpl) BeginIdempotent(ctx context.Context, key, requestHash string) (*IdempotencyRecord, error) {
+	coll := r.coll.Collection("idempotency_keys")
+	now := time.Now()
+	claim := bson.M{
+		"_id":         key,
+		"requestHash": requestHash,
+		"done":        false,
+		"createdAt":   now,
+		"expiresAt":   now.Add(idempotencyPendingTimeout),
+	}
+
+	_, err := coll.InsertOne(ctx, claim)
+	if err == nil {
+		return nil, nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		return nil, err
+	}
+
+	result, err := coll.ReplaceOne(ctx, bson.M{"_id": key, "expiresAt": bson.M{"$lte": now}}, claim)
+	if err != nil {
+		return nil, err
+	}
+	if result.MatchedCount == 1 {
+		return nil, nil
+	}
+
+	var existing bson.M
+	err = coll.FindOne(ctx, bson.M{"_id": key}).Decode(&existing)
+	if err == mongo.ErrNoDocuments {
+		return nil, apperr.Aborted("idempotency key", "", "idempotency key '%s' expired while it was read, try again", key)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	record := &IdempotencyRecord{}
+	record.RequestHash, _ = existing["requestHash"].(string)
+	record.Done, _ = existing["done"].(bool)
+	if response, ok := existing["response"].(primitive.Binary); ok {
+		record.Response = response.Data
+	}
+	return record, nil
+}
+
+// CompleteIdempotent stores the response of the call holding key.
+func (r *idempotencyRepositoryImpl) CompleteIdempotent(ctx context.Context, key string, response []byte) error {
+	_, err := r.coll.Collection("idempotency_keys").UpdateOne(ctx, bson.M{"_id": key, "done": false}, bson.M{
+		"$set": bson.M{"done": true, "response": response, "expiresAt": time.Now().Add(r.ttl)},
+	})
+	return err
+}
+
+// AbandonIdempotent frees key after a failed call, so a retry runs again.
+func (r *idempotencyRepositoryImpl) AbandonIdempotent(ctx context.Context, key string) error {
+	_, err := r.coll.Collection("idempotency_keys").DeleteOne(ctx, bson.M{"_id": key, "done": false})
+	return err
+}
@@ -0,0 +1,153 @@
+package mongodb
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	NotificationPending = "pending"
+	NotificationSending = "sending"
+	NotificationSent    = "sent"
+	NotificationFailed  = "failed"
+)
+
+// notificationLease is how long a claimed notification stays locked before
+// another worker may pick it up again.
+const notificationLease = 2 * time.Minute
+
+// Notification is an outbox entry. To is an email address; when it is empty
+// the registered address of UserId is looked up on delivery.
+type Notification struct {
+	Id       string
+	Kind     string
+	To       string
+	UserId   string
+	Data     map[string]string
+	Attempts int
+}
+
+type NotificationRepository interface {
+	ClaimNotification(ctx context.Context) (*Notification, error)
+	CompleteNotification(ctx context.Context, id string) error
+	FailNotification(ctx context.Context, id string, attempts int, cause error, retryAt time.Time, dead bool) error
+}
+
+type notificationRepositoryImpl struct {
+	coll *mongo.Database
+}
+
+func NewNotificationRepository(db *mongo.Database) NotificationRepository {
+	return &notificationRepositoryImpl{coll: db}
+}
+
+// ClaimNotification locks the oldest due notification for delivery. It
+// returns nil when nothing is due.
+func (r *notificationRepositoryImpl) ClaimNotification(ctx context.Context) (*Notification, error) {
+	n, err := notificationQueue.claim(ctx, r.coll)
+	if n == nil || err != nil {
+		return nil, err
+	}
+
+	res := &Notification{Data: map[string]string{}}
+	res.Id, _ = n["_id"].(string)
+	res.Kind, _ = n["kind"].(string)
+	res.To, _ = n["to"].(string)
+	res.UserId, _ = n["userId"].(string)
+	if attempts, ok := n["attempts"].(int32); ok {
+		res.Attempts = int(attempts)
+	}
+	if data, ok := n["data"].(bson.M); ok {
+		for k, v := range data {
+			res.Data[k], _ = v.(string)
+		}
+	}
+	return res, nil
+}
+
+func (r *notificationRepositoryImpl) CompleteNotification(ctx context.Context, id string) error {
+	return notificationQueue.release(ctx, r.coll, id, NotificationSent, bson.M{"sentAt": time.Now()}, nil)
+}
+
+// FailNotification records a failed attempt. The notification is retried at
+// retryAt, or parked as failed when dead is set.
+func (r *notificationRepositoryImpl) FailNotification(ctx context.Context, id string, attempts int, cause error, retryAt time.Time, dead bool) error {
+	status := NotificationPending
+	if dead {
+		status = NotificationFailed
+	}
+	return notificationQueue.release(ctx, r.coll, id, status, bson.M{
+		"attempts":      int32(attempts),
+		"lastError":     cause.Error(),
+		"nextAttemptAt": retryAt,
+	}, nil)
+}
+
+// enqueueNotification writes an outbox entry. Call it inside the same
+// transaction as the change that triggers it.
+func enqueueNotification(ctx context.Context, db *mongo.Database, kind, to, userId string, data map[string]string) error {
+	now := time.Now()
+	_, err := db.Collection("notifications").InsertOne(ctx, bson.M{
+		"_id":           uuid.NewString(),
+		"kind":          kind,
+		"to":            to,
+		"userId":        userId,
+		"data":          data,
+		"status":        NotificationPending,
+		"attempts":      int32(0),
+		"nextAttemptAt": now,
+		"createdAt":     now,
+	})
+	return err
+}
+
+// withTransaction runs fn in a transaction. Standalone servers (as in the
+// docker-compose setup) do not support transactions; there fn runs once
+// without one. Which of the two applies is decided before fn runs, so fn is
+// never run again after a failed transaction left part of its writes behind.
+func withTransaction(ctx context.Context, db *mongo.Database, fn func(ctx context.Context) error) error {
+	supported, err := supportsTransactions(ctx, db)
+	if err != nil {
+		return err
+	}
+	if !supported {
+		return fn(ctx)
+	}
+
+	session, err := db.Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sc)
+	})
+	return err
+}
+
+// transactionSupport caches supportsTransactions per client.
+var transactionSupport sync.Map
+
+// supportsTransactions reports whether the deployment of db is a replica set
+// or a sharded cluster, the ones that support transactions.
+func supportsTransactions(ctx context.Context, db *mongo.Database) (bool, error) {
+	client := db.Client()
+	if supported, ok := transactionSupport.Load(client); ok {
+		return supported.(bool), nil
+	}
+
+	var hello bson.M
+	if err := db.RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		return false, err
+	}
+	_, replicaSet := hello["setName"]
+	supported := replicaSet || hello["msg"] == "isdbgrid"
+	transactionSupport.Store(client, supported)
+	return supported, nil
+}
@@ -0,0 +1,81 @@
+package mongodb
+
+import (
+	"context"
+	"mainService/pkg/apperr"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const maxTagLength = 64
+
+// AuthorizeDocument checks that the document exists and that userId holds at
+// least minRole on it.
+func (r *documentRepositoryImpl) AuthorizeDocument(ctx context.Context, userId, docsId, title, minRole string) error {
+	_, err := authorizedHead(ctx, r.coll, userId, docsId, title, minRole)
+	return err
+}
+
+// MoveDocument puts the document into folder; an empty folder moves it back
+// to the top level. The folder belongs to the document, so every
+// collaborator sees the move.
+func (r *documentRepositoryImpl) MoveDocument(ctx context.Context, userId, docsId, title, folder string) error {
+	folder = strings.Trim(strings.TrimSpace(folder), "/")
+
+	head, err := authorizedHead(ctx, r.coll, userId, docsId, title, RoleEditor)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.coll.Collection("docs").UpdateOne(ctx, bson.M{"_id": head["_id"]}, bson.M{
+		"$set": bson.M{"folder": folder},
+	})
+	return err
+}
+
+// TagDocument adds tags to the document, or removes them when remove is set.
+func (r *documentRepositoryImpl) TagDocument(ctx context.Context, userId, docsId, title string, tags []string, remove bool) error {
+	var clean []string
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" {
+			continue
+		}
+		if len(t) > maxTagLength {
+			return apperr.Invalid("tags", "tag '%s' is longer than %d characters", t, maxTagLength)
+		}
+		clean = append(clean, t)
+	}
+	if len(clean) == 0 {
+		return apperr.Missing("tags")
+	}
+
+	head, err := authorizedHead(ctx, r.coll, userId, docsId, title, RoleEditor)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{"$addToSet": bson.M{"tags": bson.M{"$each": clean}}}
+	if remove {
+		update = bson.M{"$pullAll": bson.M{"tags": clean}}
+	}
+	_, err = r.coll.Collection("docs").UpdateOne(ctx, bson.M{"_id": head["_id"]}, update)
+	return err
+}
+
+func authorizedHead(ctx context.Context, db *mongo.Database, userId, docsId, title, minRole string) (bson.M, error) {
+	if userId == "" {
+		return nil, apperr.Missing("user_id")
+	}
+
+	head, err := findHead(ctx, db, docsId, title)
+	if err != nil {
+		return nil, err
+	}
+	if !RoleAtLeast(roleOf(head, userId), minRole) {
+		return nil, apperr.NotAllowed("document", title, "userId '%s' needs the %s role on document '%s'", userId, minRole, title)
+	}
+	return head, nil
+}
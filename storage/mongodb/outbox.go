@@ -0,0 +1,106 @@
+package mongodb
+
+import (
+	"context"
+	pb "mainService/genproto/doccs"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	OutboxPending    = "pending"
+	OutboxPublishing = "publishing"
+	OutboxPublished  = "published"
+)
+
+// outboxLease is how long a claimed event stays locked before another relay
+// may pick it up again.
+const outboxLease = time.Minute
+
+// OutboxEvent is a claimed event waiting to be handed to the broker.
+type OutboxEvent struct {
+	Event    *pb.DocumentEvent
+	Attempts int
+}
+
+// OutboxRepository keeps document events until the broker has them. Events
+// are never dropped; failed ones are retried until they go through. They are
+// written by recordEvent together with the change they describe.
+type OutboxRepository interface {
+	ClaimOutboxEvent(ctx context.Context) (*OutboxEvent, error)
+	CompleteOutboxEvent(ctx context.Context, id string) error
+	FailOutboxEvent(ctx context.Context, id string, attempts int, cause error, retryAt time.Time) error
+}
+
+type outboxRepositoryImpl struct {
+	coll *mongo.Database
+}
+
+func NewOutboxRepository(db *mongo.Database) OutboxRepository {
+	return &outboxRepositoryImpl{coll: db}
+}
+
+// recordEvent queues the event kind about doc, done by actor, for the broker
+// and for the webhook subscribers of the document. Call it inside the
+// transaction of the change, so the event exists exactly when the change
+// does.
+func recordEvent(ctx context.Context, db *mongo.Database, kind string, doc bson.M, actor string) error {
+	event := &pb.DocumentEvent{
+		Id:        uuid.NewString(),
+		Kind:      kind,
+		Actor:     actor,
+		CreatedAt: time.Now().Format(time.RFC3339),
+	}
+	event.DocsId, _ = doc["docsId"].(string)
+	event.Title, _ = doc["title"].(string)
+	event.AuthorId, _ = doc["authorId"].(string)
+
+	now := time.Now()
+	_, err := db.Collection("outbox").InsertOne(ctx, bson.M{
+		"_id":           event.Id,
+		"kind":          event.Kind,
+		"docsId":        event.DocsId,
+		"title":         event.Title,
+		"authorId":      event.AuthorId,
+		"actor":         event.Actor,
+		"createdAt":     event.CreatedAt,
+		"status":        OutboxPending,
+		"attempts":      int32(0),
+		"nextAttemptAt": now,
+		"enqueuedAt":    now,
+	})
+	if err != nil {
+		return err
+	}
+	return enqueueWebhookDeliveries(ctx, db, event, doc)
+}
+
+// ClaimOutboxEvent locks the oldest due event. It returns nil when nothing is
+// due.
+func (r *outboxRepositoryImpl) ClaimOutboxEvent(ctx context.Context) (*OutboxEvent, error) {
+	event, err := outboxQueue.claim(ctx, r.coll)
+	if event == nil || err != nil {
+		return nil, err
+	}
+
+	res := &OutboxEvent{Event: toDocumentEvent(event)}
+	if attempts, ok := event["attempts"].(int32); ok {
+		res.Attempts = int(attempts)
+	}
+	return res, nil
+}
+
+func (r *outboxRepositoryImpl) CompleteOutboxEvent(ctx context.Context, id string) error {
+	return outboxQueue.release(ctx, r.coll, id, OutboxPublished, bson.M{"publishedAt": time.Now()}, nil)
+}
+
+func (r *outboxRepositoryImpl) FailOutboxEvent(ctx context.Context, id string, attempts int, cause error, retryAt time.Time) error {
+	return outboxQueue.release(ctx, r.coll, id, OutboxPending, bson.M{
+		"attempts":      int32(attempts),
+		"lastError":     cause.Error(),
+		"nextAttemptAt": retryAt,
+	}, nil)
+}
@@ -0,0 +1,77 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// queuePending is the status of a queued row that waits for a worker, in
+// every queue.
+const queuePending = "pending"
+
+// workQueue is a collection worked off by background workers: notifications,
+// webhook deliveries and outbox events. A worker claims a due row, which
+// locks it for lease; if the worker dies the lock runs out and another
+// worker picks the row up again.
+type workQueue struct {
+	collection string
+	// claimed is the status of a row while a worker holds it.
+	claimed string
+	lease   time.Duration
+	// order is the field the oldest due row is picked by.
+	order string
+}
+
+var (
+	notificationQueue = workQueue{collection: "notifications", claimed: NotificationSending, lease: notificationLease, order: "nextAttemptAt"}
+	webhookQueue      = workQueue{collection: "webhook_deliveries", claimed: WebhookSending, lease: webhookLease, order: "nextAttemptAt"}
+	outboxQueue       = workQueue{collection: "outbox", claimed: OutboxPublishing, lease: outboxLease, order: "enqueuedAt"}
+)
+
+// claim locks the oldest due row and returns it. It returns nil when nothing
+// is due.
+func (q workQueue) claim(ctx context.Context, db *mongo.Database) (bson.M, error) {
+	now := time.Now()
+	filter := bson.M{
+		"$or": bson.A{
+			bson.M{"status": queuePending, "nextAttemptAt": bson.M{"$lte": now}},
+			bson.M{"status": q.claimed, "lockedUntil": bson.M{"$lte": now}},
+		},
+	}
+	update := bson.M{"$set": bson.M{
+		"status":      q.claimed,
+		"lockedUntil": now.Add(q.lease),
+	}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: q.order, Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var row bson.M
+	err := db.Collection(q.collection).FindOneAndUpdate(ctx, filter, update, opts).Decode(&row)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	return row, err
+}
+
+// release unlocks a claimed row and moves it to status, setting the fields
+// of set and, if given, pushing those of push.
+func (q workQueue) release(ctx context.Context, db *mongo.Database, id, status string, set, push bson.M) error {
+	fields := bson.M{"status": status}
+	for k, v := range set {
+		fields[k] = v
+	}
+	update := bson.M{
+		"$set":   fields,
+		"$unset": bson.M{"lockedUntil": ""},
+	}
+	if push != nil {
+		update["$push"] = push
+	}
+	_, err := db.Collection(q.collection).UpdateOne(ctx, bson.M{"_id": id}, update)
+	return err
+}
@@ -0,0 +1,326 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	pb "mainService/genproto/doccs"
+	"mainService/pkg/apperr"
+	"mainService/pkg/richtext"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErrQuotaExceeded is wrapped by the errors of a write that would take a user
+// or workspace over its quota.
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// Quota limits the storage of one user or workspace. Zero means unlimited.
+type Quota struct {
+	MaxDocuments int64
+	MaxBytes     int64
+}
+
+// Usage is what a user or workspace stores. Bytes count every version and
+// the deleted documents still in the trash; ContentBytes are the plain
+// content and the rich body of those rows, as measured by rowBytes.
+type Usage struct {
+	Documents       int64
+	ContentBytes    int64
+	AttachmentBytes int64
+}
+
+func (u Usage) Bytes() int64 {
+	return u.ContentBytes + u.AttachmentBytes
+}
+
+type QuotaRepository interface {
+	GetUsage(ctx context.Context, req *pb.GetUsageReq) (*pb.GetUsageRes, error)
+	CheckCreateQuota(ctx context.Context, authorId, workspaceId string, bytes int64) error
+	CheckUpdateQuota(ctx context.Context, docsId, title string, bytes int64) error
+	CheckTemplateQuota(ctx context.Context, req *pb.CreateDocumentReq) error
+	CheckCopyQuota(ctx context.Context, req *pb.CopyDocumentReq) error
+	CheckRestoreQuota(ctx context.Context, req *pb.RestoreVersionReq) error
+}
+
+type quotaRepositoryImpl struct {
+	coll      *mongo.Database
+	user      Quota
+	workspace Quota
+}
+
+// NewQuotaRepository returns a repository that enforces user on personal
+// documents and workspace on the documents of each workspace.
+func NewQuotaRepository(db *mongo.Database, user, workspace Quota) QuotaRepository {
+	return &quotaRepositoryImpl{coll: db, user: user, workspace: workspace}
+}
+
+// GetUsage reports the usage of the caller, or of a workspace the caller is
+// a member of.
+func (r *quotaRepositoryImpl) GetUsage(ctx context.Context, req *pb.GetUsageReq) (*pb.GetUsageRes, error) {
+	if req.UserId == "" {
+		return nil, apperr.Missing("user_id")
+	}
+
+	scope, err := r.userScope(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	quota := r.user
+	if req.WorkspaceId != "" {
+		ws, err := findWorkspace(ctx, r.coll, req.WorkspaceId)
+		if err != nil {
+			return nil, err
+		}
+		if workspaceRoleOf(ws, req.UserId) == "" {
+			return nil, apperr.NoAccess(req.UserId, "workspace", req.WorkspaceId)
+		}
+		scope, quota = r.workspaceScope(req.WorkspaceId), r.workspace
+	}
+
+	usage, err := r.usage(ctx, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.GetUsageRes{
+		DocumentCount:   usage.Documents,
+		ContentBytes:    usage.ContentBytes,
+		AttachmentBytes: usage.AttachmentBytes,
+		TotalBytes:      usage.Bytes(),
+		MaxDocuments:    quota.MaxDocuments,
+		MaxBytes:        quota.MaxBytes,
+	}, nil
+}
+
+// CheckCreateQuota fails when one more document of the given size does not
+// fit into the quota of the author, or of the workspace when one is given.
+func (r *quotaRepositoryImpl) CheckCreateQuota(ctx context.Context, authorId, workspaceId string, bytes int64) error {
+	if workspaceId != "" {
+		return r.check(ctx, r.workspaceScope(workspaceId), r.workspace, 1, bytes)
+	}
+	scope, err := r.userScope(ctx, authorId)
+	if err != nil {
+		return err
+	}
+	return r.check(ctx, scope, r.user, 1, bytes)
+}
+
+// CheckUpdateQuota fails when a new version of the given size does not fit
+// into the quota the document counts against.
+func (r *quotaRepositoryImpl) CheckUpdateQuota(ctx context.Context, docsId, title string, bytes int64) error {
+	head, err := findHead(ctx, r.coll, docsId, title)
+	if err != nil {
+		return err
+	}
+	if workspaceId, _ := head["workspaceId"].(string); workspaceId != "" {
+		return r.check(ctx, r.workspaceScope(workspaceId), r.workspace, 0, bytes)
+	}
+	authorId, _ := head["authorId"].(string)
+	scope, err := r.userScope(ctx, authorId)
+	if err != nil {
+		return err
+	}
+	return r.check(ctx, scope, r.user, 0, bytes)
+}
+
+// CheckTemplateQuota is CheckCreateQuota for a document created from
+// req.TemplateId, sized as the template with its attachments. Placeholders
+// are measured unrendered.
+func (r *quotaRepositoryImpl) CheckTemplateQuota(ctx context.Context, req *pb.CreateDocumentReq) error {
+	_, body, err := findTemplate(ctx, r.coll, req.TemplateId, req.AuthorId)
+	if err != nil {
+		return err
+	}
+	attachments, err := attachmentBytes(ctx, r.coll, body, templateKey(req.TemplateId))
+	if err != nil {
+		return err
+	}
+	return r.CheckCreateQuota(ctx, req.AuthorId, req.WorkspaceId, rowBytes(richtext.ToPlain(body), body)+attachments)
+}
+
+// CheckCopyQuota is CheckCreateQuota for a copy of the document, which is
+// as large as its head and the attachments it gets copies of. Copies are
+// made in the personal space of the caller, as CopyDocument does.
+func (r *quotaRepositoryImpl) CheckCopyQuota(ctx context.Context, req *pb.CopyDocumentReq) error {
+	source, err := findHead(ctx, r.coll, req.DocsId, req.Title)
+	if err != nil {
+		return err
+	}
+	if roleOf(source, req.UserId) == "" {
+		return apperr.NoAccess(req.UserId, "document", req.Title)
+	}
+	attachments, err := attachmentBytes(ctx, r.coll, bodyOf(source), source)
+	if err != nil {
+		return err
+	}
+	return r.CheckCreateQuota(ctx, req.UserId, "", storedBytes(source)+attachments)
+}
+
+// CheckRestoreQuota is CheckUpdateQuota for the version a restore saves
+// again.
+func (r *quotaRepositoryImpl) CheckRestoreQuota(ctx context.Context, req *pb.RestoreVersionReq) error {
+	var row bson.M
+	err := r.coll.Collection("docs").FindOne(ctx, bson.M{"docsId": req.Id, "title": req.Title, "version": req.Version}).Decode(&row)
+	if err == mongo.ErrNoDocuments {
+		return apperr.NotFound("version", fmt.Sprint(req.Version))
+	}
+	if err != nil {
+		return err
+	}
+	content, _ := row["content"].(string)
+	return r.CheckUpdateQuota(ctx, req.Id, req.Title, rowBytes(content, bodyOf(row)))
+}
+
+// RowBytes is what a document row with content and body counts against a
+// quota.
+func RowBytes(content string, body *richtext.Document) int64 {
+	return rowBytes(content, body)
+}
+
+// UpdateBytes is what the version req saves counts against a quota. It
+// fails like UpdateDocument on a body that is not valid.
+func UpdateBytes(req *pb.UpdateDocumentReq) (int64, error) {
+	content, body, err := contentOf(req)
+	if err != nil {
+		return 0, err
+	}
+	return rowBytes(content, body), nil
+}
+
+// rowBytes measures a row the way usage does: the bytes of the content and
+// the BSON size of the body.
+func rowBytes(content string, body *richtext.Document) int64 {
+	n := int64(len(content))
+	if body != nil {
+		if data, err := bson.Marshal(body); err == nil {
+			n += int64(len(data))
+		}
+	}
+	return n
+}
+
+// storedBytes is rowBytes of a row as it is stored.
+func storedBytes(row bson.M) int64 {
+	content, _ := row["content"].(string)
+	n := int64(len(content))
+	if body, ok := row["body"]; ok && body != nil {
+		if data, err := bson.Marshal(body); err == nil {
+			n += int64(len(data))
+		}
+	}
+	return n
+}
+
+// attachmentBytes is the size of the attachments body refers to that belong
+// to the document owner names by docsId and title.
+func attachmentBytes(ctx context.Context, db *mongo.Database, body *richtext.Document, owner bson.M) (int64, error) {
+	ids := richtext.Attachments(body)
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	return sum(ctx, db.Collection("attachments"), bson.M{
+		"_id":    bson.M{"$in": ids},
+		"docsId": owner["docsId"],
+		"title":  owner["title"],
+	}, "$size")
+}
+
+func (r *quotaRepositoryImpl) check(ctx context.Context, s scope, quota Quota, docs, bytes int64) error {
+	if quota.MaxDocuments == 0 && quota.MaxBytes == 0 {
+		return nil
+	}
+
+	usage, err := r.usage(ctx, s)
+	if err != nil {
+		return err
+	}
+	return quota.allows(s.name, usage, docs, bytes)
+}
+
+// allows fails when adding docs documents and bytes bytes to usage goes over
+// the quota. Adding no documents never fails on the document count, so a
+// user at the limit can still edit.
+func (q Quota) allows(name string, usage Usage, docs, bytes int64) error {
+	if q.MaxDocuments > 0 && docs > 0 && usage.Documents+docs > q.MaxDocuments {
+		return apperr.QuotaExceeded("%s: %s already has %d of %d documents", ErrQuotaExceeded, name, usage.Documents, q.MaxDocuments).Wrap(ErrQuotaExceeded)
+	}
+	if q.MaxBytes > 0 && usage.Bytes()+bytes > q.MaxBytes {
+		return apperr.QuotaExceeded("%s: %s uses %d of %d bytes", ErrQuotaExceeded, name, usage.Bytes(), q.MaxBytes).Wrap(ErrQuotaExceeded)
+	}
+	return nil
+}
+
+// scope selects the documents and attachments that count against a quota.
+type scope struct {
+	name        string
+	docs        bson.M
+	attachments bson.M
+}
+
+// userScope covers the personal documents of userId. Attachments are found
+// by the docsId those documents share.
+func (r *quotaRepositoryImpl) userScope(ctx context.Context, userId string) (scope, error) {
+	docsId, err := authorDocsId(ctx, r.coll.Collection("docs"), userId)
+	if err != nil {
+		return scope{}, err
+	}
+	return scope{
+		name:        fmt.Sprintf("user '%s'", userId),
+		docs:        bson.M{"authorId": userId, "workspaceId": bson.M{"$in": bson.A{nil, ""}}},
+		attachments: bson.M{"docsId": docsId},
+	}, nil
+}
+
+func (r *quotaRepositoryImpl) workspaceScope(workspaceId string) scope {
+	return scope{
+		name:        fmt.Sprintf("workspace '%s'", workspaceId),
+		docs:        bson.M{"workspaceId": workspaceId},
+		attachments: bson.M{"docsId": workspaceId},
+	}
+}
+
+func (r *quotaRepositoryImpl) usage(ctx context.Context, s scope) (Usage, error) {
+	var usage Usage
+
+	live := bson.M{"deletedAt": 0}
+	for k, v := range s.docs {
+		live[k] = v
+	}
+	count, err := r.coll.Collection("docs").CountDocuments(ctx, live)
+	if err != nil {
+		return usage, err
+	}
+	usage.Documents = count
+
+	usage.ContentBytes, err = sum(ctx, r.coll.Collection("docs"), s.docs, bson.M{"$add": bson.A{
+		bson.M{"$strLenBytes": bson.M{"$ifNull": bson.A{"$content", ""}}},
+		bson.M{"$ifNull": bson.A{bson.M{"$bsonSize": "$body"}, 0}},
+	}})
+	if err != nil {
+		return usage, err
+	}
+	usage.AttachmentBytes, err = sum(ctx, r.coll.Collection("attachments"), s.attachments, "$size")
+	return usage, err
+}
+
+// sum adds up expr over the rows matching filter.
+func sum(ctx context.Context, coll *mongo.Collection, filter bson.M, expr interface{}) (int64, error) {
+	cursor, err := coll.Aggregate(ctx, bson.A{
+		bson.M{"$match": filter},
+		bson.M{"$group": bson.M{"_id": nil, "total": bson.M{"$sum": expr}}},
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var res []struct {
+		Total int64 `bson:"total"`
+	}
+	if err := cursor.All(ctx, &res); err != nil || len(res) == 0 {
+		return 0, err
+	}
+	return res[0].Total, nil
+}
@@ -0,0 +1,41 @@
+package mongodb
+
+import (
+	"errors"
+	pb "mainService/genproto/doccs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// TestQuotaAllows tests the document and byte limits, and that zero means unlimited.
+func TestQuotaAllows(t *testing.T) {
+	q := Quota{MaxDocuments: 2, MaxBytes: 100}
+	usage := Usage{Documents: 2, ContentBytes: 60, AttachmentBytes: 30}
+
+	err := q.allows("user 'u1'", usage, 1, 0)
+	assert.True(t, errors.Is(err, ErrQuotaExceeded))
+	assert.NoError(t, q.allows("user 'u1'", usage, 0, 10))
+	assert.True(t, errors.Is(q.allows("user 'u1'", usage, 0, 11), ErrQuotaExceeded))
+
+	assert.NoError(t, Quota{}.allows("user 'u1'", usage, 1, 1<<40))
+}
+
+// TestQuotaRowBytes tests that a save is measured as its row is once stored.
+func TestQuotaRowBytes(t *testing.T) {
+	req := &pb.UpdateDocumentReq{Content: "hello\nworld"}
+	bytes, err := UpdateBytes(req)
+	assert.NoError(t, err)
+
+	content, body, _ := contentOf(req)
+	data, _ := bson.Marshal(body)
+	assert.Equal(t, int64(len(content)+len(data)), bytes)
+
+	var row bson.M
+	raw, _ := bson.Marshal(bson.M{"content": content, "body": body})
+	assert.NoError(t, bson.Unmarshal(raw, &row))
+	assert.Equal(t, bytes, storedBytes(row))
+
+	assert.Equal(t, int64(5), RowBytes("hello", nil))
+}
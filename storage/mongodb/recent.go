@@ -0,0 +1,85 @@
+package mongodb
+
+import (
+	"context"
+	pb "mainService/genproto/doccs"
+	"mainService/pkg/apperr"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// maxRecent is how many recently opened documents are kept per user.
+const maxRecent = 50
+
+type RecentRepository interface {
+	RecordOpen(ctx context.Context, userId string, docs ...*pb.GetDocumentRes) error
+	ListRecentDocuments(ctx context.Context, req *pb.ListRecentDocumentsReq) (*pb.ListRecentDocumentsRes, error)
+}
+
+type recentRepositoryImpl struct {
+	coll *mongo.Database
+}
+
+func NewRecentRepository(db *mongo.Database) RecentRepository {
+	return &recentRepositoryImpl{coll: db}
+}
+
+func (r *recentRepositoryImpl) RecordOpen(ctx context.Context, userId string, docs ...*pb.GetDocumentRes) error {
+	if userId == "" || len(docs) == 0 {
+		return nil
+	}
+	coll := r.coll.Collection("recent")
+
+	now := time.Now()
+	for _, doc := range docs {
+		filter := bson.M{"userId": userId, "docsId": doc.DocsId, "title": doc.Title}
+		update := bson.M{
+			"$set":         bson.M{"openedAt": now},
+			"$setOnInsert": bson.M{"_id": uuid.NewString()},
+		}
+		if _, err := coll.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true)); err != nil {
+			return err
+		}
+	}
+
+	cursor, err := coll.Find(ctx, bson.M{"userId": userId}, options.Find().
+		SetSort(bson.D{{Key: "openedAt", Value: -1}}).
+		SetSkip(maxRecent).
+		SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	var stale []interface{}
+	for cursor.Next(ctx) {
+		var ref bson.M
+		if err := cursor.Decode(&ref); err != nil {
+			return err
+		}
+		stale = append(stale, ref["_id"])
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+
+	_, err = coll.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": stale}})
+	return err
+}
+
+func (r *recentRepositoryImpl) ListRecentDocuments(ctx context.Context, req *pb.ListRecentDocumentsReq) (*pb.ListRecentDocumentsRes, error) {
+	if req.UserId == "" {
+		return nil, apperr.Missing("user_id")
+	}
+
+	docs, err := listUserDocuments(ctx, r.coll, "recent", "openedAt", req.UserId, req.Limit, req.Page)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ListRecentDocumentsRes{Documents: docs}, nil
+}
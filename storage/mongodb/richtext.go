@@ -0,0 +1,110 @@
+package mongodb
+
+import (
+	pb "mainService/genproto/doccs"
+	"mainService/pkg/apperr"
+	"mainService/pkg/richtext"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// contentOf returns the plain content and the rich body to store for an
+// update. Legacy clients only send content, which becomes plain paragraphs;
+// rich clients send a body and content is derived from it.
+func contentOf(req *pb.UpdateDocumentReq) (string, *richtext.Document, error) {
+	if req.Body == nil {
+		return req.Content, richtext.FromPlain(req.Content), nil
+	}
+
+	body := fromRichTextPb(req.Body)
+	if body.Version == 0 {
+		body.Version = richtext.SchemaVersion
+	}
+	if err := richtext.Validate(body); err != nil {
+		return "", nil, apperr.Invalid("body", "body is not valid rich text: %v", err).Wrap(err)
+	}
+	return richtext.ToPlain(body), body, nil
+}
+
+// bodyOf reads the rich body of a row. Rows written before the rich model
+// existed only have content and are read as plain paragraphs.
+func bodyOf(doc bson.M) *richtext.Document {
+	if raw, ok := doc["body"]; ok && raw != nil {
+		if data, err := bson.Marshal(raw); err == nil {
+			var body richtext.Document
+			if err := bson.Unmarshal(data, &body); err == nil {
+				return &body
+			}
+		}
+	}
+	content, _ := doc["content"].(string)
+	return richtext.FromPlain(content)
+}
+
+func fromRichTextPb(in *pb.RichText) *richtext.Document {
+	doc := &richtext.Document{Version: int(in.Version)}
+	for _, b := range in.Blocks {
+		block := richtext.Block{
+			Type:    b.Type,
+			Level:   int(b.Level),
+			Ordered: b.Ordered,
+			Inlines: fromInlinesPb(b.Inlines),
+			Src:     b.Src,
+			Alt:     b.Alt,
+		}
+		for _, item := range b.Items {
+			block.Items = append(block.Items, fromInlinesPb(item.Inlines))
+		}
+		for _, row := range b.Rows {
+			cells := make([]richtext.Cell, len(row.Cells))
+			for i, cell := range row.Cells {
+				cells[i] = fromInlinesPb(cell.Inlines)
+			}
+			block.Rows = append(block.Rows, cells)
+		}
+		doc.Blocks = append(doc.Blocks, block)
+	}
+	return doc
+}
+
+func toRichTextPb(doc *richtext.Document) *pb.RichText {
+	res := &pb.RichText{Version: int32(doc.Version)}
+	for _, b := range doc.Blocks {
+		block := &pb.RichTextBlock{
+			Type:    b.Type,
+			Level:   int32(b.Level),
+			Ordered: b.Ordered,
+			Inlines: toInlinesPb(b.Inlines),
+			Src:     b.Src,
+			Alt:     b.Alt,
+		}
+		for _, item := range b.Items {
+			block.Items = append(block.Items, &pb.RichTextListItem{Inlines: toInlinesPb(item)})
+		}
+		for _, row := range b.Rows {
+			r := &pb.RichTextTableRow{}
+			for _, cell := range row {
+				r.Cells = append(r.Cells, &pb.RichTextTableCell{Inlines: toInlinesPb(cell)})
+			}
+			block.Rows = append(block.Rows, r)
+		}
+		res.Blocks = append(res.Blocks, block)
+	}
+	return res
+}
+
+func fromInlinesPb(in []*pb.RichTextInline) []richtext.Inline {
+	var res []richtext.Inline
+	for _, i := range in {
+		res = append(res, richtext.Inline{Text: i.Text, Bold: i.Bold, Italic: i.Italic, Link: i.Link})
+	}
+	return res
+}
+
+func toInlinesPb(in []richtext.Inline) []*pb.RichTextInline {
+	var res []*pb.RichTextInline
+	for _, i := range in {
+		res = append(res, &pb.RichTextInline{Text: i.Text, Bold: i.Bold, Italic: i.Italic, Link: i.Link})
+	}
+	return res
+}
@@ -0,0 +1,45 @@
+package mongodb
+
+import (
+	pb "mainService/genproto/doccs"
+	"mainService/pkg/apperr"
+	"mainService/pkg/richtext"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// TestContentOf tests legacy and rich updates.
+func TestContentOf(t *testing.T) {
+	content, body, err := contentOf(&pb.UpdateDocumentReq{Content: "a\n\nb"})
+	assert.NoError(t, err)
+	assert.Equal(t, "a\n\nb", content)
+	assert.Len(t, body.Blocks, 2)
+
+	content, body, err = contentOf(&pb.UpdateDocumentReq{Content: "ignored", Body: &pb.RichText{Blocks: []*pb.RichTextBlock{
+		{Type: richtext.Heading, Level: 2, Inlines: []*pb.RichTextInline{{Text: "Title", Bold: true}}},
+		{Type: richtext.List, Items: []*pb.RichTextListItem{{Inlines: []*pb.RichTextInline{{Text: "item"}}}}},
+	}}})
+	assert.NoError(t, err)
+	assert.Equal(t, "Title\n\n- item", content)
+	assert.Equal(t, richtext.SchemaVersion, body.Version)
+
+	_, _, err = contentOf(&pb.UpdateDocumentReq{Body: &pb.RichText{Blocks: []*pb.RichTextBlock{{Type: "video"}}}})
+	assert.Equal(t, apperr.ReasonInvalidField, apperr.ReasonOf(err))
+}
+
+// TestBodyOf tests reading stored bodies and legacy rows.
+func TestBodyOf(t *testing.T) {
+	stored := &richtext.Document{Version: 1, Blocks: []richtext.Block{
+		{Type: richtext.Table, Rows: [][]richtext.Cell{{{{Text: "x"}}}}},
+	}}
+	data, err := bson.Marshal(bson.M{"body": stored})
+	assert.NoError(t, err)
+	var row bson.M
+	assert.NoError(t, bson.Unmarshal(data, &row))
+
+	assert.Equal(t, stored, bodyOf(row))
+	assert.Equal(t, richtext.FromPlain("old"), bodyOf(bson.M{"content": "old"}))
+	assert.Equal(t, "x", toRichTextPb(bodyOf(row)).Blocks[0].Rows[0].Cells[0].Inlines[0].Text)
+}
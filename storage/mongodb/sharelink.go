@@ -0,0 +1,223 @@
+package mongodb
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	pb "mainService/genproto/doccs"
+	"mainService/pkg/apperr"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ShareLinkRepository interface {
+	CreateShareLink(ctx context.Context, req *pb.CreateShareLinkReq) (*pb.CreateShareLinkRes, error)
+	RevokeShareLink(ctx context.Context, req *pb.RevokeShareLinkReq) (*pb.RevokeShareLinkRes, error)
+	ListShareLinks(ctx context.Context, req *pb.ListShareLinksReq) (*pb.ListShareLinksRes, error)
+	ResolveShareLink(ctx context.Context, req *pb.ResolveShareLinkReq, email string) (*pb.ResolveShareLinkRes, error)
+}
+
+type shareLinkRepositoryImpl struct {
+	coll    *mongo.Database
+	baseURL string
+}
+
+// NewShareLinkRepository returns a repository whose links are rendered as
+// baseURL followed by the token.
+func NewShareLinkRepository(db *mongo.Database, baseURL string) ShareLinkRepository {
+	return &shareLinkRepositoryImpl{coll: db, baseURL: baseURL}
+}
+
+func (r *shareLinkRepositoryImpl) CreateShareLink(ctx context.Context, req *pb.CreateShareLinkReq) (*pb.CreateShareLinkRes, error) {
+	if req.UserId == "" {
+		return nil, apperr.Missing("user_id")
+	}
+
+	role := NormalizeRole(req.Role)
+	if role == "" || role == RoleOwner {
+		return nil, apperr.Invalid("role", "role '%s' is not allowed for share links", req.Role)
+	}
+	if req.ExpiresInSeconds < 0 {
+		return nil, apperr.Invalid("expires_in_seconds", "expiry '%d' must not be negative", req.ExpiresInSeconds)
+	}
+
+	doc, err := findHead(ctx, r.coll, req.DocsId, req.Title)
+	if err != nil {
+		return nil, err
+	}
+	own := roleOf(doc, req.UserId)
+	if !RoleAtLeast(own, RoleEditor) || !RoleAtLeast(own, role) {
+		return nil, apperr.NotAllowed("document", req.Title, "userId '%s' is not allowed to share document '%s' as %s", req.UserId, req.Title, role)
+	}
+
+	token, err := newShareToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	link := bson.M{
+		"_id":       token,
+		"docsId":    doc["docsId"],
+		"title":     doc["title"],
+		"role":      role,
+		"domain":    strings.ToLower(strings.TrimPrefix(req.Domain, "@")),
+		"createdBy": req.UserId,
+		"createdAt": now,
+		"revokedAt": 0,
+	}
+	if req.ExpiresInSeconds > 0 {
+		link["expiresAt"] = now.Add(time.Duration(req.ExpiresInSeconds) * time.Second)
+	}
+
+	if _, err := r.coll.Collection("share_links").InsertOne(ctx, link); err != nil {
+		return nil, err
+	}
+
+	return &pb.CreateShareLinkRes{Link: r.toShareLink(link)}, nil
+}
+
+func (r *shareLinkRepositoryImpl) RevokeShareLink(ctx context.Context, req *pb.RevokeShareLinkReq) (*pb.RevokeShareLinkRes, error) {
+	link, err := r.findLink(ctx, req.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := findHead(ctx, r.coll, link["docsId"].(string), link["title"].(string))
+	if err != nil {
+		return nil, err
+	}
+	if link["createdBy"] != req.UserId && !RoleAtLeast(roleOf(doc, req.UserId), RoleOwner) {
+		return nil, apperr.NotAllowed("share link", "", "userId '%s' is not allowed to revoke this link", req.UserId)
+	}
+
+	err = withTransaction(ctx, r.coll, func(ctx context.Context) error {
+		_, err := r.coll.Collection("share_links").UpdateOne(ctx, bson.M{"_id": req.Token}, bson.M{
+			"$set": bson.M{"revokedAt": time.Now().Unix()},
+		})
+		if err != nil {
+			return err
+		}
+		return dropLinkGrants(ctx, r.coll, doc, req.Token)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.RevokeShareLinkRes{Message: "Share link revoked successfully"}, nil
+}
+
+func (r *shareLinkRepositoryImpl) ListShareLinks(ctx context.Context, req *pb.ListShareLinksReq) (*pb.ListShareLinksRes, error) {
+	doc, err := findHead(ctx, r.coll, req.DocsId, req.Title)
+	if err != nil {
+		return nil, err
+	}
+	if !RoleAtLeast(roleOf(doc, req.UserId), RoleEditor) {
+		return nil, apperr.NotAllowed("document", req.Title, "userId '%s' is not allowed to see links of document '%s'", req.UserId, req.Title)
+	}
+
+	cursor, err := r.coll.Collection("share_links").Find(ctx,
+		bson.M{"docsId": doc["docsId"], "title": doc["title"], "revokedAt": 0},
+		options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var links []*pb.ShareLink
+	for cursor.Next(ctx) {
+		var link bson.M
+		if err := cursor.Decode(&link); err != nil {
+			return nil, err
+		}
+		if linkExpired(link) {
+			continue
+		}
+		links = append(links, r.toShareLink(link))
+	}
+
+	return &pb.ListShareLinksRes{Links: links}, cursor.Err()
+}
+
+// ResolveShareLink returns the document behind a token. When the caller is
+// identified, the link's role is granted to them so the other RPCs work too;
+// that grant expires and is revoked together with the link. email is the
+// registered address of req.UserId, which domain restricted links check;
+// req.Email is set by the client and is not trusted.
+func (r *shareLinkRepositoryImpl) ResolveShareLink(ctx context.Context, req *pb.ResolveShareLinkReq, email string) (*pb.ResolveShareLinkRes, error) {
+	link, err := r.findLink(ctx, req.Token)
+	if err != nil {
+		return nil, err
+	}
+	if revokedAt, _ := link["revokedAt"].(int64); revokedAt != 0 {
+		return nil, apperr.Conflict("share link", "", "share link has been revoked")
+	}
+	if linkExpired(link) {
+		return nil, apperr.Conflict("share link", "", "share link has expired")
+	}
+	if domain, _ := link["domain"].(string); domain != "" {
+		if email == "" || !strings.HasSuffix(strings.ToLower(email), "@"+domain) {
+			return nil, apperr.NotAllowed("share link", "", "share link is restricted to the '%s' domain", domain)
+		}
+	}
+
+	doc, err := findHead(ctx, r.coll, link["docsId"].(string), link["title"].(string))
+	if err != nil {
+		return nil, err
+	}
+
+	role := link["role"].(string)
+	if req.UserId != "" {
+		createdBy, _ := link["createdBy"].(string)
+		expiresAt, _ := toTime(link["expiresAt"])
+		grant := Grant{Role: role, GrantedBy: createdBy, GrantedAt: time.Now(), ExpiresAt: expiresAt, ViaLink: req.Token}
+		if err := grantRole(ctx, r.coll, doc, req.UserId, grant); err != nil {
+			return nil, err
+		}
+		if own := roleOf(doc, req.UserId); RoleAtLeast(own, role) {
+			role = own
+		}
+	}
+
+	return &pb.ResolveShareLinkRes{Document: toDocumentRes(doc), Role: role}, nil
+}
+
+func (r *shareLinkRepositoryImpl) findLink(ctx context.Context, token string) (bson.M, error) {
+	var link bson.M
+	err := r.coll.Collection("share_links").FindOne(ctx, bson.M{"_id": token}).Decode(&link)
+	if err == mongo.ErrNoDocuments {
+		return nil, apperr.NotFound("share link", "")
+	}
+	return link, err
+}
+
+func (r *shareLinkRepositoryImpl) toShareLink(link bson.M) *pb.ShareLink {
+	res := &pb.ShareLink{}
+	res.Token, _ = link["_id"].(string)
+	res.DocsId, _ = link["docsId"].(string)
+	res.Title, _ = link["title"].(string)
+	res.Role, _ = link["role"].(string)
+	res.Domain, _ = link["domain"].(string)
+	res.CreatedBy, _ = link["createdBy"].(string)
+	res.CreatedAt = formatTime(link["createdAt"])
+	res.ExpiresAt = formatTime(link["expiresAt"])
+	res.Url = r.baseURL + res.Token
+	return res
+}
+
+func linkExpired(link bson.M) bool {
+	expiresAt, ok := toTime(link["expiresAt"])
+	return ok && time.Now().After(expiresAt)
+}
+
+func newShareToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,140 @@
+package mongodb
+
+import (
+	"context"
+	pb "mainService/genproto/doccs"
+	"mainService/pkg/apperr"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type StarredRepository interface {
+	StarDocument(ctx context.Context, req *pb.StarDocumentReq) (*pb.StarDocumentRes, error)
+	UnstarDocument(ctx context.Context, req *pb.UnstarDocumentReq) (*pb.UnstarDocumentRes, error)
+	ListStarredDocuments(ctx context.Context, req *pb.ListStarredDocumentsReq) (*pb.ListStarredDocumentsRes, error)
+}
+
+type starredRepositoryImpl struct {
+	coll *mongo.Database
+}
+
+func NewStarredRepository(db *mongo.Database) StarredRepository {
+	return &starredRepositoryImpl{coll: db}
+}
+
+func (r *starredRepositoryImpl) StarDocument(ctx context.Context, req *pb.StarDocumentReq) (*pb.StarDocumentRes, error) {
+	if req.UserId == "" {
+		return nil, apperr.Missing("user_id")
+	}
+	if req.Title == "" {
+		return nil, apperr.Missing("title")
+	}
+
+	doc, err := findHead(ctx, r.coll, req.DocsId, req.Title)
+	if err != nil {
+		return nil, err
+	}
+	if roleOf(doc, req.UserId) == "" {
+		return nil, apperr.NoAccess(req.UserId, "document", req.Title)
+	}
+
+	filter := bson.M{"userId": req.UserId, "docsId": doc["docsId"], "title": req.Title}
+	update := bson.M{
+		"$setOnInsert": bson.M{
+			"_id":       uuid.NewString(),
+			"starredAt": time.Now(),
+		},
+	}
+
+	_, err = r.coll.Collection("starred").UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.StarDocumentRes{Message: "Document starred successfully"}, nil
+}
+
+func (r *starredRepositoryImpl) UnstarDocument(ctx context.Context, req *pb.UnstarDocumentReq) (*pb.UnstarDocumentRes, error) {
+	if req.UserId == "" {
+		return nil, apperr.Missing("user_id")
+	}
+
+	filter := bson.M{"userId": req.UserId, "title": req.Title}
+	if req.DocsId != "" {
+		filter["docsId"] = req.DocsId
+	}
+
+	result, err := r.coll.Collection("starred").DeleteOne(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if result.DeletedCount == 0 {
+		return nil, apperr.NotFound("document", req.Title)
+	}
+
+	return &pb.UnstarDocumentRes{Message: "Document unstarred successfully"}, nil
+}
+
+func (r *starredRepositoryImpl) ListStarredDocuments(ctx context.Context, req *pb.ListStarredDocumentsReq) (*pb.ListStarredDocumentsRes, error) {
+	if req.UserId == "" {
+		return nil, apperr.Missing("user_id")
+	}
+
+	docs, err := listUserDocuments(ctx, r.coll, "starred", "starredAt", req.UserId, req.Limit, req.Page)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ListStarredDocumentsRes{Documents: docs}, nil
+}
+
+// listUserDocuments resolves the per-user references stored in collection
+// (newest first by sortKey) to live documents the user can still read.
+// Deleted documents and revoked grants are skipped before paginating.
+func listUserDocuments(ctx context.Context, db *mongo.Database, collection, sortKey, userId string, limit, page int32) ([]*pb.GetDocumentRes, error) {
+	cursor, err := db.Collection(collection).Find(ctx, bson.M{"userId": userId},
+		options.Find().SetSort(bson.D{{Key: sortKey, Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	size, skip := pageOptions(limit, page)
+	var docs []*pb.GetDocumentRes
+
+	for cursor.Next(ctx) {
+		var ref bson.M
+		if err := cursor.Decode(&ref); err != nil {
+			return nil, err
+		}
+
+		docsId, _ := ref["docsId"].(string)
+		title, _ := ref["title"].(string)
+
+		doc, err := findHead(ctx, db, docsId, title)
+		if apperr.ReasonOf(err) == apperr.ReasonNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if roleOf(doc, userId) == "" {
+			continue
+		}
+
+		if skip > 0 {
+			skip--
+			continue
+		}
+		docs = append(docs, toDocumentRes(doc))
+		if int64(len(docs)) == size {
+			break
+		}
+	}
+
+	return docs, cursor.Err()
+}
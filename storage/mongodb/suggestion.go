@@ -0,0 +1,319 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	pb "mainService/genproto/doccs"
+	"mainService/pkg/apperr"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	SuggestionInsert = "insert"
+	SuggestionDelete = "delete"
+
+	SuggestionPending   = "pending"
+	SuggestionAccepting = "accepting"
+	SuggestionAccepted  = "accepted"
+	SuggestionRejected  = "rejected"
+
+	// suggestionClaimTimeout frees a suggestion whose accept stalled between
+	// claiming it and saving the new version.
+	suggestionClaimTimeout = time.Minute
+)
+
+type SuggestionRepository interface {
+	CreateSuggestion(ctx context.Context, req *pb.CreateSuggestionReq) (*pb.CreateSuggestionRes, error)
+	ListSuggestions(ctx context.Context, req *pb.ListSuggestionsReq) (*pb.ListSuggestionsRes, error)
+	ApplySuggestion(ctx context.Context, req *pb.AcceptSuggestionReq) (*pb.UpdateDocumentReq, string, error)
+	MarkSuggestionAccepted(ctx context.Context, req *pb.AcceptSuggestionReq) (*pb.AcceptSuggestionRes, error)
+	ReleaseSuggestion(ctx context.Context, req *pb.AcceptSuggestionReq) error
+	RejectSuggestion(ctx context.Context, req *pb.RejectSuggestionReq) (*pb.RejectSuggestionRes, error)
+}
+
+type suggestionRepositoryImpl struct {
+	coll *mongo.Database
+}
+
+func NewSuggestionRepository(db *mongo.Database) SuggestionRepository {
+	return &suggestionRepositoryImpl{coll: db}
+}
+
+func (r *suggestionRepositoryImpl) CreateSuggestion(ctx context.Context, req *pb.CreateSuggestionReq) (*pb.CreateSuggestionRes, error) {
+	if req.UserId == "" {
+		return nil, apperr.Missing("user_id")
+	}
+
+	doc, err := findHead(ctx, r.coll, req.DocsId, req.Title)
+	if err != nil {
+		return nil, err
+	}
+	if !RoleAtLeast(roleOf(doc, req.UserId), RoleCommenter) {
+		return nil, apperr.NotAllowed("document", req.Title, "userId '%s' is not allowed to suggest changes to document '%s'", req.UserId, req.Title)
+	}
+	if err := closeAutosave(ctx, r.coll, doc); err != nil {
+		return nil, err
+	}
+
+	text, _ := doc["content"].(string)
+	content := []rune(text)
+	if req.Start < 0 || req.End < req.Start || int(req.End) > len(content) {
+		return nil, apperr.Invalid("start", "range [%d, %d) is outside of the document", req.Start, req.End)
+	}
+
+	switch req.Kind {
+	case SuggestionInsert:
+		if req.Text == "" || req.Start != req.End {
+			return nil, apperr.Invalid("text", "insert suggestion needs text and an empty range")
+		}
+	case SuggestionDelete:
+		if req.Start == req.End {
+			return nil, apperr.Invalid("end", "delete suggestion needs a non-empty range")
+		}
+	default:
+		return nil, apperr.Invalid("kind", "suggestion kind '%s' is not supported", req.Kind)
+	}
+
+	suggestion := bson.M{
+		"_id":        uuid.NewString(),
+		"docsId":     doc["docsId"],
+		"title":      doc["title"],
+		"userId":     req.UserId,
+		"kind":       req.Kind,
+		"start":      req.Start,
+		"end":        req.End,
+		"text":       req.Text,
+		"quotedText": string(content[req.Start:req.End]),
+		"version":    doc["version"],
+		"status":     SuggestionPending,
+		"resolvedBy": "",
+		"createdAt":  time.Now(),
+	}
+
+	if _, err := r.coll.Collection("suggestions").InsertOne(ctx, suggestion); err != nil {
+		return nil, err
+	}
+
+	return &pb.CreateSuggestionRes{Suggestion: toSuggestion(suggestion)}, nil
+}
+
+func (r *suggestionRepositoryImpl) ListSuggestions(ctx context.Context, req *pb.ListSuggestionsReq) (*pb.ListSuggestionsRes, error) {
+	doc, err := findHead(ctx, r.coll, req.DocsId, req.Title)
+	if err != nil {
+		return nil, err
+	}
+	if roleOf(doc, req.UserId) == "" {
+		return nil, apperr.NoAccess(req.UserId, "document", req.Title)
+	}
+
+	cursor, err := r.coll.Collection("suggestions").Find(ctx,
+		bson.M{"docsId": doc["docsId"], "title": doc["title"], "status": SuggestionPending},
+		options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var suggestions []*pb.Suggestion
+	for cursor.Next(ctx) {
+		var s bson.M
+		if err := cursor.Decode(&s); err != nil {
+			return nil, err
+		}
+		suggestions = append(suggestions, toSuggestion(s))
+	}
+
+	return &pb.ListSuggestionsRes{Suggestions: suggestions}, cursor.Err()
+}
+
+// ApplySuggestion claims a pending suggestion for req.UserId and builds the
+// UpdateDocument request that applies it to the current head, saved in the
+// name of the accepting user. It also returns the userId that made the
+// suggestion, which the new version records as suggestedBy. The claim keeps a concurrent accept from
+// applying it twice; MarkSuggestionAccepted or ReleaseSuggestion ends it.
+// The range is mapped from the version the suggestion was made against; it
+// fails if later edits touched that range.
+func (r *suggestionRepositoryImpl) ApplySuggestion(ctx context.Context, req *pb.AcceptSuggestionReq) (*pb.UpdateDocumentReq, string, error) {
+	suggestion, head, err := r.findPending(ctx, req.SuggestionId, req.UserId)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := r.resolve(ctx, req.SuggestionId, req.UserId, SuggestionAccepting); err != nil {
+		return nil, "", err
+	}
+
+	update, err := applyTo(ctx, r.coll, suggestion, head, req.UserId)
+	if err != nil {
+		// A failed release only delays the suggestion by the claim timeout.
+		r.ReleaseSuggestion(ctx, req)
+		return nil, "", err
+	}
+	suggestedBy, _ := suggestion["userId"].(string)
+	return update, suggestedBy, nil
+}
+
+func applyTo(ctx context.Context, db *mongo.Database, suggestion, head bson.M, userId string) (*pb.UpdateDocumentReq, error) {
+	id, _ := suggestion["_id"].(string)
+
+	headContent, _ := head["content"].(string)
+	baseContent := headContent
+	version, _ := suggestion["version"].(int32)
+	if headVersion, _ := head["version"].(int32); headVersion != version {
+		var row bson.M
+		err := db.Collection("docs").FindOne(ctx, bson.M{
+			"docsId":  head["docsId"],
+			"title":   head["title"],
+			"version": version,
+		}).Decode(&row)
+		if err != nil {
+			return nil, apperr.NotFound("version", fmt.Sprint(version))
+		}
+		baseContent, _ = row["content"].(string)
+	}
+
+	start, _ := suggestion["start"].(int32)
+	end, _ := suggestion["end"].(int32)
+	quoted, _ := suggestion["quotedText"].(string)
+	from, to, ok := reanchor(baseContent, headContent, int(start), int(end), quoted)
+	if !ok {
+		return nil, apperr.Conflict("suggestion", id, "suggestion '%s' conflicts with later edits", id)
+	}
+
+	text, _ := suggestion["text"].(string)
+	content := []rune(headContent)
+	updated := string(content[:from]) + text + string(content[to:])
+
+	return &pb.UpdateDocumentReq{
+		Title:    head["title"].(string),
+		Content:  updated,
+		AuthorId: userId,
+		DocsId:   head["docsId"].(string),
+	}, nil
+}
+
+// MarkSuggestionAccepted ends the claim of ApplySuggestion once the new
+// version is saved.
+func (r *suggestionRepositoryImpl) MarkSuggestionAccepted(ctx context.Context, req *pb.AcceptSuggestionReq) (*pb.AcceptSuggestionRes, error) {
+	result, err := r.coll.Collection("suggestions").UpdateOne(ctx,
+		bson.M{"_id": req.SuggestionId, "status": SuggestionAccepting, "resolvedBy": req.UserId},
+		bson.M{"$set": bson.M{"status": SuggestionAccepted, "resolvedAt": time.Now()}})
+	if err != nil {
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, apperr.Conflict("suggestion", req.SuggestionId, "suggestion '%s' is no longer claimed by userId '%s'", req.SuggestionId, req.UserId)
+	}
+	return &pb.AcceptSuggestionRes{Message: "Suggestion accepted successfully"}, nil
+}
+
+// ReleaseSuggestion puts a suggestion claimed by ApplySuggestion back to
+// pending when its new version could not be saved.
+func (r *suggestionRepositoryImpl) ReleaseSuggestion(ctx context.Context, req *pb.AcceptSuggestionReq) error {
+	_, err := r.coll.Collection("suggestions").UpdateOne(ctx,
+		bson.M{"_id": req.SuggestionId, "status": SuggestionAccepting, "resolvedBy": req.UserId},
+		bson.M{
+			"$set":   bson.M{"status": SuggestionPending},
+			"$unset": bson.M{"resolvedBy": "", "resolvedAt": ""},
+		})
+	return err
+}
+
+func (r *suggestionRepositoryImpl) RejectSuggestion(ctx context.Context, req *pb.RejectSuggestionReq) (*pb.RejectSuggestionRes, error) {
+	if _, _, err := r.findPending(ctx, req.SuggestionId, req.UserId); err != nil {
+		return nil, err
+	}
+	if err := r.resolve(ctx, req.SuggestionId, req.UserId, SuggestionRejected); err != nil {
+		return nil, err
+	}
+	return &pb.RejectSuggestionRes{Message: "Suggestion rejected successfully"}, nil
+}
+
+// findPending loads a pending suggestion together with its document head and
+// checks that userId may accept or reject it.
+func (r *suggestionRepositoryImpl) findPending(ctx context.Context, id, userId string) (bson.M, bson.M, error) {
+	if userId == "" {
+		return nil, nil, apperr.Missing("user_id")
+	}
+
+	var suggestion bson.M
+	err := r.coll.Collection("suggestions").FindOne(ctx, bson.M{"_id": id}).Decode(&suggestion)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil, apperr.NotFound("suggestion", id)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if !claimable(suggestion, time.Now()) {
+		return nil, nil, apperr.Conflict("suggestion", id, "suggestion '%s' is already %s", id, suggestion["status"])
+	}
+
+	head, err := findHead(ctx, r.coll, suggestion["docsId"].(string), suggestion["title"].(string))
+	if err != nil {
+		return nil, nil, err
+	}
+	if !RoleAtLeast(roleOf(head, userId), RoleEditor) {
+		return nil, nil, apperr.NotAllowed("document", fmt.Sprint(head["title"]), "userId '%s' is not allowed to review suggestions on document '%s'", userId, head["title"])
+	}
+
+	return suggestion, head, nil
+}
+
+// resolve moves a claimable suggestion to status. It fails when another call
+// got there first.
+func (r *suggestionRepositoryImpl) resolve(ctx context.Context, id, userId, status string) error {
+	now := time.Now()
+	result, err := r.coll.Collection("suggestions").UpdateOne(ctx,
+		bson.M{"_id": id, "$or": bson.A{
+			bson.M{"status": SuggestionPending},
+			bson.M{"status": SuggestionAccepting, "resolvedAt": bson.M{"$lt": now.Add(-suggestionClaimTimeout)}},
+		}},
+		bson.M{"$set": bson.M{
+			"status":     status,
+			"resolvedBy": userId,
+			"resolvedAt": now,
+		}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return apperr.Conflict("suggestion", id, "suggestion '%s' is no longer pending", id)
+	}
+	return nil
+}
+
+// claimable reports whether a suggestion may still be accepted or rejected:
+// it is pending, or an accept claimed it and then stalled.
+func claimable(s bson.M, now time.Time) bool {
+	switch s["status"] {
+	case SuggestionPending:
+		return true
+	case SuggestionAccepting:
+		claimed, ok := toTime(s["resolvedAt"])
+		return ok && now.After(claimed.Add(suggestionClaimTimeout))
+	}
+	return false
+}
+
+func toSuggestion(s bson.M) *pb.Suggestion {
+	res := &pb.Suggestion{}
+	res.Id, _ = s["_id"].(string)
+	res.DocsId, _ = s["docsId"].(string)
+	res.Title, _ = s["title"].(string)
+	res.UserId, _ = s["userId"].(string)
+	res.Kind, _ = s["kind"].(string)
+	res.Start, _ = s["start"].(int32)
+	res.End, _ = s["end"].(int32)
+	res.Text, _ = s["text"].(string)
+	res.QuotedText, _ = s["quotedText"].(string)
+	res.Version, _ = s["version"].(int32)
+	res.Status, _ = s["status"].(string)
+	res.ResolvedBy, _ = s["resolvedBy"].(string)
+
+	res.CreatedAt = formatTime(s["createdAt"])
+	return res
+}
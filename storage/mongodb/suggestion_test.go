@@ -0,0 +1,20 @@
+package mongodb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// TestClaimable tests which suggestions may still be accepted or rejected.
+func TestClaimable(t *testing.T) {
+	now := time.Now()
+
+	assert.True(t, claimable(bson.M{"status": SuggestionPending}, now))
+	assert.False(t, claimable(bson.M{"status": SuggestionAccepting, "resolvedAt": now.Add(-time.Second)}, now), "accept in progress")
+	assert.True(t, claimable(bson.M{"status": SuggestionAccepting, "resolvedAt": now.Add(-2 * suggestionClaimTimeout)}, now), "stalled accept")
+	assert.False(t, claimable(bson.M{"status": SuggestionAccepted}, now))
+	assert.False(t, claimable(bson.M{"status": SuggestionRejected}, now))
+}
@@ -0,0 +1,237 @@
+package mongodb
+
+import (
+	"context"
+	pb "mainService/genproto/doccs"
+	"mainService/pkg/apperr"
+	"mainService/pkg/richtext"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// templateDocsId is the docsId the attachments of saved templates are kept
+// under, with the id of the template as their title.
+const templateDocsId = "templates"
+
+func templateKey(templateId string) bson.M {
+	return bson.M{"docsId": templateDocsId, "title": templateId}
+}
+
+var placeholderPattern = regexp.MustCompile(`\{\{\s*(\w+)\s*\}\}`)
+
+// systemTemplates are available to every user and are not stored in Mongo.
+var systemTemplates = []*pb.Template{
+	{
+		Id:           "system-blank",
+		Name:         "Blank document",
+		TitlePattern: "Untitled document {{date}}",
+		System:       true,
+	},
+	{
+		Id:           "system-meeting-notes",
+		Name:         "Meeting notes",
+		TitlePattern: "Meeting notes {{date}}",
+		Content:      "Meeting notes\nDate: {{date}}\nNote taker: {{author}}\n\nAttendees\n\nAgenda\n\nAction items\n",
+		System:       true,
+	},
+	{
+		Id:           "system-project-proposal",
+		Name:         "Project proposal",
+		TitlePattern: "Project proposal {{date}}",
+		Content:      "Project proposal\nAuthor: {{author}}\nDate: {{date}}\n\nOverview\n\nGoals\n\nMilestones\n\nRisks\n",
+		System:       true,
+	},
+	{
+		Id:           "system-letter",
+		Name:         "Letter",
+		TitlePattern: "Letter {{date}}",
+		Content:      "{{date}}\n\nDear ...,\n\n\n\nSincerely,\n{{author}}\n",
+		System:       true,
+	},
+}
+
+type TemplateRepository interface {
+	SaveAsTemplate(ctx context.Context, req *pb.SaveAsTemplateReq) (*pb.SaveAsTemplateRes, error)
+	ListTemplates(ctx context.Context, req *pb.ListTemplatesReq) (*pb.ListTemplatesRes, error)
+}
+
+type templateRepositoryImpl struct {
+	coll *mongo.Database
+}
+
+func NewTemplateRepository(db *mongo.Database) TemplateRepository {
+	return &templateRepositoryImpl{coll: db}
+}
+
+func (r *templateRepositoryImpl) SaveAsTemplate(ctx context.Context, req *pb.SaveAsTemplateReq) (*pb.SaveAsTemplateRes, error) {
+	if req.UserId == "" {
+		return nil, apperr.Missing("user_id")
+	}
+
+	doc, err := findHead(ctx, r.coll, req.DocsId, req.Title)
+	if err != nil {
+		return nil, err
+	}
+	if roleOf(doc, req.UserId) == "" {
+		return nil, apperr.NoAccess(req.UserId, "document", req.Title)
+	}
+
+	name := req.Name
+	if name == "" {
+		name = req.Title
+	}
+	titlePattern := req.TitlePattern
+	if titlePattern == "" {
+		titlePattern = req.Title + " {{date}}"
+	}
+
+	template := bson.M{
+		"_id":          uuid.NewString(),
+		"name":         name,
+		"titlePattern": titlePattern,
+		"content":      doc["content"],
+		"ownerId":      req.UserId,
+		"createdAt":    time.Now(),
+	}
+
+	err = withTransaction(ctx, r.coll, func(ctx context.Context) error {
+		// The template owns copies of the images, which outlive the
+		// document and are not readable through it.
+		key := templateKey(template["_id"].(string))
+		body, err := copyAttachments(ctx, r.coll, bodyOf(doc), doc, key["docsId"].(string), key["title"].(string))
+		if err != nil {
+			return err
+		}
+		template["body"] = body
+		if _, err := r.coll.Collection("templates").InsertOne(ctx, template); err != nil {
+			return err
+		}
+		return addRefs(ctx, r.coll, body, 1)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.SaveAsTemplateRes{Template: toTemplate(template)}, nil
+}
+
+func (r *templateRepositoryImpl) ListTemplates(ctx context.Context, req *pb.ListTemplatesReq) (*pb.ListTemplatesRes, error) {
+	templates := append([]*pb.Template{}, systemTemplates...)
+	if req.UserId == "" {
+		return &pb.ListTemplatesRes{Templates: templates}, nil
+	}
+
+	cursor, err := r.coll.Collection("templates").Find(ctx, bson.M{"ownerId": req.UserId},
+		options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var t bson.M
+		if err := cursor.Decode(&t); err != nil {
+			return nil, err
+		}
+		templates = append(templates, toTemplate(t))
+	}
+
+	return &pb.ListTemplatesRes{Templates: templates}, cursor.Err()
+}
+
+// findTemplate returns a system template or one owned by userId, with its
+// rich body. System templates and templates saved before the rich model
+// only have content, which is read as plain paragraphs.
+func findTemplate(ctx context.Context, db *mongo.Database, id, userId string) (*pb.Template, *richtext.Document, error) {
+	for _, t := range systemTemplates {
+		if t.Id == id {
+			return t, richtext.FromPlain(t.Content), nil
+		}
+	}
+
+	var t bson.M
+	err := db.Collection("templates").FindOne(ctx, bson.M{"_id": id, "ownerId": userId}).Decode(&t)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil, apperr.NotFound("template", id)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return toTemplate(t), bodyOf(t), nil
+}
+
+// renderTemplate substitutes {{name}} placeholders; unknown ones are kept.
+func renderTemplate(text string, vars map[string]string) string {
+	return placeholderPattern.ReplaceAllStringFunc(text, func(m string) string {
+		name := strings.ToLower(placeholderPattern.FindStringSubmatch(m)[1])
+		if v, ok := vars[name]; ok {
+			return v
+		}
+		return m
+	})
+}
+
+// renderBody returns a copy of body with the placeholders of every text run
+// substituted. A placeholder split across differently formatted runs is
+// kept as it is.
+func renderBody(body *richtext.Document, vars map[string]string) *richtext.Document {
+	inlines := func(in []richtext.Inline) []richtext.Inline {
+		out := make([]richtext.Inline, len(in))
+		for i, inline := range in {
+			inline.Text = renderTemplate(inline.Text, vars)
+			out[i] = inline
+		}
+		return out
+	}
+
+	res := &richtext.Document{Version: body.Version, Blocks: make([]richtext.Block, len(body.Blocks))}
+	for i, b := range body.Blocks {
+		b.Inlines = inlines(b.Inlines)
+		b.Alt = renderTemplate(b.Alt, vars)
+		if b.Items != nil {
+			items := make([][]richtext.Inline, len(b.Items))
+			for j, item := range b.Items {
+				items[j] = inlines(item)
+			}
+			b.Items = items
+		}
+		if b.Rows != nil {
+			rows := make([][]richtext.Cell, len(b.Rows))
+			for j, row := range b.Rows {
+				rows[j] = make([]richtext.Cell, len(row))
+				for k, cell := range row {
+					rows[j][k] = inlines(cell)
+				}
+			}
+			b.Rows = rows
+		}
+		res.Blocks[i] = b
+	}
+	return res
+}
+
+func templateVars(authorName, title string, now time.Time) map[string]string {
+	return map[string]string{
+		"date":   now.Format("2006-01-02"),
+		"time":   now.Format("15:04"),
+		"author": authorName,
+		"title":  title,
+	}
+}
+
+func toTemplate(t bson.M) *pb.Template {
+	res := &pb.Template{}
+	res.Id, _ = t["_id"].(string)
+	res.Name, _ = t["name"].(string)
+	res.TitlePattern, _ = t["titlePattern"].(string)
+	res.Content, _ = t["content"].(string)
+	res.OwnerId, _ = t["ownerId"].(string)
+	res.CreatedAt = formatTime(t["createdAt"])
+	return res
+}
@@ -0,0 +1,38 @@
+package mongodb
+
+import (
+	"testing"
+	"mainService/pkg/richtext"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRenderTemplate tests placeholder substitution in templates.
+func TestRenderTemplate(t *testing.T) {
+	now := time.Date(2024, 8, 15, 9, 30, 0, 0, time.UTC)
+	vars := templateVars("testAuthor", "Weekly sync", now)
+
+	assert.Equal(t, "Meeting notes 2024-08-15", renderTemplate("Meeting notes {{date}}", vars))
+	assert.Equal(t, "Weekly sync by testAuthor", renderTemplate("{{ title }} by {{AUTHOR}}", vars))
+	assert.Equal(t, "keep {{unknown}}", renderTemplate("keep {{unknown}}", vars))
+}
+
+// TestRenderBody tests that placeholders are substituted in every text run
+// of a rich template body and that the template itself is left unchanged.
+func TestRenderBody(t *testing.T) {
+	vars := templateVars("Ann Lee", "Weekly sync", time.Date(2024, 8, 15, 9, 30, 0, 0, time.UTC))
+	body := &richtext.Document{Version: richtext.SchemaVersion, Blocks: []richtext.Block{
+		{Type: "heading", Level: 1, Inlines: []richtext.Inline{{Text: "{{title}}", Bold: true}}},
+		{Type: "list", Items: [][]richtext.Inline{{{Text: "Owner: {{author}}"}}}},
+		{Type: "table", Rows: [][]richtext.Cell{{{{Text: "{{date}}"}}}}},
+	}}
+
+	res := renderBody(body, vars)
+
+	assert.Equal(t, "Weekly sync", res.Blocks[0].Inlines[0].Text)
+	assert.True(t, res.Blocks[0].Inlines[0].Bold)
+	assert.Equal(t, "Owner: Ann Lee", res.Blocks[1].Items[0][0].Text)
+	assert.Equal(t, "2024-08-15", res.Blocks[2].Rows[0][0][0].Text)
+	assert.Equal(t, "{{title}}", body.Blocks[0].Inlines[0].Text)
+}
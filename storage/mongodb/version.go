@@ -4,11 +4,11 @@ import (
 	"context"
 	"fmt"
 	pb "mainService/genproto/doccs"
-	"time"
+	"mainService/pkg/apperr"
 
-	"github.com/syndtr/goleveldb/leveldb/errors"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type DocumentVersionRepository interface {
@@ -24,110 +24,89 @@ func NewDocumentVersionRepository(db *mongo.Database) DocumentVersionRepository
 	return &documentVersionRepositoryImpl{coll: db}
 }
 
+// GetAllVersions lists every version of a document, oldest first, to any
+// user with a role on it.
 func (r *documentVersionRepositoryImpl) GetAllVersions(ctx context.Context, req *pb.GetAllVersionsReq) (*pb.GetAllDocumentsRes, error) {
 	coll := r.coll.Collection("docs")
 
 	if req.AuthorId == "" {
-		return nil, errors.New("Author is required")
+		return nil, apperr.Missing("author_id")
 	}
 	if req.Title == "" {
-		return nil, errors.New("Title is required")
+		return nil, apperr.Missing("title")
+	}
+
+	head, err := authorizedHead(ctx, r.coll, req.AuthorId, req.DocsId, req.Title, RoleViewer)
+	if err != nil {
+		return nil, err
 	}
 
 	filter := bson.M{
-		"authorId": req.AuthorId,
-		"title":    req.Title,
+		"docsId": head["docsId"],
+		"title":  head["title"],
 	}
 
-	cursor, err := coll.Find(ctx, filter, nil)
+	cursor, err := coll.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "version", Value: 1}}))
 	if err != nil {
 		return nil, err
 	}
 	defer cursor.Close(ctx)
 
 	var docs []*pb.GetDocumentRes
-	var authorFound bool
-
 	for cursor.Next(ctx) {
 		var doc bson.M
 		if err := cursor.Decode(&doc); err != nil {
 			return nil, err
 		}
-
-		authorId, ok := doc["authorId"].(string)
-		if !ok {
-			continue
-		}
-
-		if authorId == req.AuthorId {
-			result := &pb.GetDocumentRes{
-				Title:    doc["title"].(string),
-				Content:  doc["content"].(string),
-				DocsId:   doc["docsId"].(string),
-				AuthorId: doc["authorId"].(string),
-				Version:  doc["version"].(int32),
-			}
-			docs = append(docs, result)
-			authorFound = true
-		} else {
-			collaboratorIds, ok := doc["collaboratorId"].([]string)
-			if ok && contains(collaboratorIds, req.AuthorId) {
-				result := &pb.GetDocumentRes{
-					Title:    doc["title"].(string),
-					Content:  doc["content"].(string),
-					DocsId:   doc["docsId"].(string),
-					AuthorId: doc["authorId"].(string),
-					Version:  doc["version"].(int32),
-				}
-				docs = append(docs, result)
-				authorFound = true
-			}
-		}
+		docs = append(docs, toDocumentRes(doc))
 	}
-
-	if !authorFound {
-		return nil, fmt.Errorf("no documents found '%s' with the given authorId or in collaboratorId", req.AuthorId)
+	if err := cursor.Err(); err != nil {
+		return nil, err
 	}
 
 	return &pb.GetAllDocumentsRes{Documents: docs}, nil
 }
 
+// RestoreVersion saves the content of an earlier version as the newest one,
+// so the versions in between stay in the history. Any editor may restore.
 func (r *documentVersionRepositoryImpl) RestoreVersion(ctx context.Context, req *pb.RestoreVersionReq) (*pb.RestoreVersionRes, error) {
 	coll := r.coll.Collection("docs")
 
-	authorId := req.AuthorId
-
-	if authorId == "" {
-		return nil, errors.New("Author is required")
+	if req.AuthorId == "" {
+		return nil, apperr.Missing("author_id")
 	}
 	if req.Title == "" {
-		return nil, errors.New("Title is required")
+		return nil, apperr.Missing("title")
+	}
+	if req.Id == "" {
+		return nil, apperr.Missing("id")
 	}
 	if req.Version == 0 {
-        return nil, errors.New("Version is required")
-    }
+		return nil, apperr.Missing("version")
+	}
+
+	head, err := authorizedHead(ctx, r.coll, req.AuthorId, req.Id, req.Title, RoleEditor)
+	if err != nil {
+		return nil, err
+	}
 
 	filter := bson.D{
-		{Key: "authorId", Value: authorId},
-		{Key: "title", Value: req.Title},
+		{Key: "docsId", Value: head["docsId"]},
+		{Key: "title", Value: head["title"]},
 		{Key: "version", Value: req.Version},
-		{Key: "docsId", Value: req.Id},
 	}
 
-	var doc pb.GetDocumentRes
-	err := coll.FindOne(ctx, filter).Decode(&doc)
+	var doc bson.M
+	err = coll.FindOne(ctx, filter).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, apperr.NotFound("version", fmt.Sprint(req.Version))
+	}
 	if err != nil {
-		return nil, fmt.Errorf("document with docsId '%s' and title '%s' not found", req.Id, req.Title)
+		return nil, err
 	}
 
-	update := bson.D{{Key: "$set", Value: bson.D{
-		{Key: "title", Value: req.Title},
-		{Key: "content", Value: doc.Content},
-		{Key: "updatedAt", Value: time.Now()},
-	}}}
-
-	_, err = coll.UpdateOne(ctx, filter, update)
-	if err != nil {
+	content, _ := doc["content"].(string)
+	if _, err := newVersion(ctx, r.coll, head, req.AuthorId, content, bodyOf(doc), EventRestored, nil); err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,378 @@
+package mongodb
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	pb "mainService/genproto/doccs"
+	"mainService/pkg/apperr"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	WebhookPending   = "pending"
+	WebhookSending   = "sending"
+	WebhookDelivered = "delivered"
+	WebhookDead      = "dead"
+)
+
+// webhookLease is how long a claimed delivery stays locked before another
+// worker may pick it up again.
+const webhookLease = 2 * time.Minute
+
+// WebhookDelivery is a claimed delivery together with the target of its
+// subscription. URL is empty when the subscription has been deleted.
+type WebhookDelivery struct {
+	Id       string
+	Event    string
+	URL      string
+	Secret   string
+	Payload  []byte
+	Attempts int
+}
+
+type WebhookRepository interface {
+	CreateWebhook(ctx context.Context, req *pb.CreateWebhookReq) (*pb.CreateWebhookRes, error)
+	DeleteWebhook(ctx context.Context, req *pb.DeleteWebhookReq) (*pb.DeleteWebhookRes, error)
+	ListWebhooks(ctx context.Context, req *pb.ListWebhooksReq) (*pb.ListWebhooksRes, error)
+	ListWebhookDeliveries(ctx context.Context, req *pb.ListWebhookDeliveriesReq) (*pb.ListWebhookDeliveriesRes, error)
+	RetryWebhookDelivery(ctx context.Context, req *pb.RetryWebhookDeliveryReq) (*pb.RetryWebhookDeliveryRes, error)
+	ClaimWebhookDelivery(ctx context.Context) (*WebhookDelivery, error)
+	CompleteWebhookDelivery(ctx context.Context, id string, statusCode int) error
+	FailWebhookDelivery(ctx context.Context, id string, attempts, statusCode int, cause error, retryAt time.Time, dead bool) error
+}
+
+type webhookRepositoryImpl struct {
+	coll *mongo.Database
+}
+
+func NewWebhookRepository(db *mongo.Database) WebhookRepository {
+	return &webhookRepositoryImpl{coll: db}
+}
+
+// CreateWebhook stores a subscription. Without a secret one is generated; the
+// secret is only ever returned here.
+func (r *webhookRepositoryImpl) CreateWebhook(ctx context.Context, req *pb.CreateWebhookReq) (*pb.CreateWebhookRes, error) {
+	if req.UserId == "" {
+		return nil, apperr.Missing("user_id")
+	}
+	u, err := url.Parse(req.Url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, apperr.Invalid("url", "url '%s' is not a valid http(s) url", req.Url)
+	}
+	for _, event := range req.Events {
+		if !validEvent(event) {
+			return nil, apperr.Invalid("events", "event '%s' is not supported", event)
+		}
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		b := make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		secret = hex.EncodeToString(b)
+	}
+
+	hook := bson.M{
+		"_id":       uuid.NewString(),
+		"userId":    req.UserId,
+		"url":       req.Url,
+		"secret":    secret,
+		"events":    req.Events,
+		"docsId":    req.DocsId,
+		"createdAt": time.Now(),
+	}
+	if _, err := r.coll.Collection("webhooks").InsertOne(ctx, hook); err != nil {
+		return nil, err
+	}
+
+	return &pb.CreateWebhookRes{Webhook: toWebhook(hook), Secret: secret}, nil
+}
+
+func (r *webhookRepositoryImpl) DeleteWebhook(ctx context.Context, req *pb.DeleteWebhookReq) (*pb.DeleteWebhookRes, error) {
+	result, err := r.coll.Collection("webhooks").DeleteOne(ctx, bson.M{"_id": req.WebhookId, "userId": req.UserId})
+	if err != nil {
+		return nil, err
+	}
+	if result.DeletedCount == 0 {
+		return nil, apperr.NotFound("webhook", req.WebhookId)
+	}
+
+	return &pb.DeleteWebhookRes{Message: "Webhook deleted successfully"}, nil
+}
+
+func (r *webhookRepositoryImpl) ListWebhooks(ctx context.Context, req *pb.ListWebhooksReq) (*pb.ListWebhooksRes, error) {
+	if req.UserId == "" {
+		return nil, apperr.Missing("user_id")
+	}
+
+	cursor, err := r.coll.Collection("webhooks").Find(ctx, bson.M{"userId": req.UserId},
+		options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var hooks []*pb.Webhook
+	for cursor.Next(ctx) {
+		var hook bson.M
+		if err := cursor.Decode(&hook); err != nil {
+			return nil, err
+		}
+		hooks = append(hooks, toWebhook(hook))
+	}
+
+	return &pb.ListWebhooksRes{Webhooks: hooks}, cursor.Err()
+}
+
+// ListWebhookDeliveries returns the deliveries of a subscription, newest
+// first. Filtering by the dead status gives the dead-letter list.
+func (r *webhookRepositoryImpl) ListWebhookDeliveries(ctx context.Context, req *pb.ListWebhookDeliveriesReq) (*pb.ListWebhookDeliveriesRes, error) {
+	if err := r.checkOwner(ctx, req.WebhookId, req.UserId); err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{"webhookId": req.WebhookId}
+	if req.Status != "" {
+		filter["status"] = req.Status
+	}
+
+	size, skip := pageOptions(req.Limit, req.Page)
+	cursor, err := r.coll.Collection("webhook_deliveries").Find(ctx, filter, options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}}).
+		SetSkip(skip).
+		SetLimit(size))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var deliveries []*pb.WebhookDelivery
+	for cursor.Next(ctx) {
+		var delivery bson.M
+		if err := cursor.Decode(&delivery); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, toWebhookDelivery(delivery))
+	}
+
+	return &pb.ListWebhookDeliveriesRes{Deliveries: deliveries}, cursor.Err()
+}
+
+// RetryWebhookDelivery moves a dead delivery back to the queue.
+func (r *webhookRepositoryImpl) RetryWebhookDelivery(ctx context.Context, req *pb.RetryWebhookDeliveryReq) (*pb.RetryWebhookDeliveryRes, error) {
+	var delivery bson.M
+	err := r.coll.Collection("webhook_deliveries").FindOne(ctx, bson.M{"_id": req.DeliveryId}).Decode(&delivery)
+	if err == mongo.ErrNoDocuments {
+		return nil, apperr.NotFound("webhook delivery", req.DeliveryId)
+	}
+	if err != nil {
+		return nil, err
+	}
+	webhookId, _ := delivery["webhookId"].(string)
+	if err := r.checkOwner(ctx, webhookId, req.UserId); err != nil {
+		return nil, err
+	}
+	if delivery["status"] != WebhookDead {
+		return nil, apperr.Conflict("webhook delivery", req.DeliveryId, "webhook delivery '%s' is %s, only dead deliveries can be retried", req.DeliveryId, delivery["status"])
+	}
+
+	_, err = r.coll.Collection("webhook_deliveries").UpdateOne(ctx, bson.M{"_id": req.DeliveryId}, bson.M{
+		"$set": bson.M{"status": WebhookPending, "nextAttemptAt": time.Now()},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.RetryWebhookDeliveryRes{Message: "Webhook delivery queued again"}, nil
+}
+
+// enqueueWebhookDeliveries queues one delivery per subscription interested
+// in event about doc. Subscribers only hear about documents they can access.
+func enqueueWebhookDeliveries(ctx context.Context, db *mongo.Database, event *pb.DocumentEvent, doc bson.M) error {
+	filter := bson.M{
+		"$and": bson.A{
+			bson.M{"$or": bson.A{
+				bson.M{"events": bson.M{"$size": 0}},
+				bson.M{"events": nil},
+				bson.M{"events": event.Kind},
+			}},
+			bson.M{"$or": bson.A{
+				bson.M{"docsId": ""},
+				bson.M{"docsId": event.DocsId},
+			}},
+		},
+	}
+	cursor, err := db.Collection("webhooks").Find(ctx, filter)
+	if err != nil {
+		return err
+	}
+	var hooks []bson.M
+	if err := cursor.All(ctx, &hooks); err != nil {
+		return err
+	}
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(map[string]string{
+		"id":        event.Id,
+		"event":     event.Kind,
+		"docsId":    event.DocsId,
+		"title":     event.Title,
+		"actor":     event.Actor,
+		"createdAt": event.CreatedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var deliveries []interface{}
+	for _, hook := range hooks {
+		userId, _ := hook["userId"].(string)
+		if roleOf(doc, userId) == "" {
+			continue
+		}
+		deliveries = append(deliveries, bson.M{
+			"_id":           uuid.NewString(),
+			"webhookId":     hook["_id"],
+			"event":         event.Kind,
+			"docsId":        event.DocsId,
+			"title":         event.Title,
+			"payload":       string(payload),
+			"status":        WebhookPending,
+			"attempts":      bson.A{},
+			"nextAttemptAt": now,
+			"createdAt":     now,
+		})
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	_, err = db.Collection("webhook_deliveries").InsertMany(ctx, deliveries)
+	return err
+}
+
+// ClaimWebhookDelivery locks the oldest due delivery. It returns nil when
+// nothing is due.
+func (r *webhookRepositoryImpl) ClaimWebhookDelivery(ctx context.Context) (*WebhookDelivery, error) {
+	delivery, err := webhookQueue.claim(ctx, r.coll)
+	if delivery == nil || err != nil {
+		return nil, err
+	}
+
+	res := &WebhookDelivery{}
+	res.Id, _ = delivery["_id"].(string)
+	res.Event, _ = delivery["event"].(string)
+	payload, _ := delivery["payload"].(string)
+	res.Payload = []byte(payload)
+	if attempts, ok := delivery["attempts"].(primitive.A); ok {
+		res.Attempts = len(attempts)
+	}
+
+	var hook bson.M
+	err = r.coll.Collection("webhooks").FindOne(ctx, bson.M{"_id": delivery["webhookId"]}).Decode(&hook)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+	res.URL, _ = hook["url"].(string)
+	res.Secret, _ = hook["secret"].(string)
+	return res, nil
+}
+
+func (r *webhookRepositoryImpl) CompleteWebhookDelivery(ctx context.Context, id string, statusCode int) error {
+	return webhookQueue.release(ctx, r.coll, id, WebhookDelivered, nil,
+		bson.M{"attempts": bson.M{"at": time.Now(), "statusCode": int32(statusCode)}})
+}
+
+// FailWebhookDelivery records a failed attempt. The delivery is retried at
+// retryAt, or moved to the dead-letter list when dead is set.
+func (r *webhookRepositoryImpl) FailWebhookDelivery(ctx context.Context, id string, attempts, statusCode int, cause error, retryAt time.Time, dead bool) error {
+	status := WebhookPending
+	if dead {
+		status = WebhookDead
+	}
+	return webhookQueue.release(ctx, r.coll, id, status, bson.M{"nextAttemptAt": retryAt}, bson.M{"attempts": bson.M{
+		"at":         time.Now(),
+		"statusCode": int32(statusCode),
+		"error":      cause.Error(),
+	}})
+}
+
+func (r *webhookRepositoryImpl) checkOwner(ctx context.Context, webhookId, userId string) error {
+	count, err := r.coll.Collection("webhooks").CountDocuments(ctx, bson.M{"_id": webhookId, "userId": userId})
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return apperr.NotFound("webhook", webhookId)
+	}
+	return nil
+}
+
+func validEvent(event string) bool {
+	switch event {
+	case EventCreated, EventUpdated, EventShared, EventUnshared, EventRestored, EventDeleted, EventTransferred:
+		return true
+	}
+	return false
+}
+
+func toWebhook(hook bson.M) *pb.Webhook {
+	res := &pb.Webhook{}
+	res.Id, _ = hook["_id"].(string)
+	res.UserId, _ = hook["userId"].(string)
+	res.Url, _ = hook["url"].(string)
+	res.DocsId, _ = hook["docsId"].(string)
+	res.CreatedAt = formatTime(hook["createdAt"])
+	switch events := hook["events"].(type) {
+	case []string:
+		res.Events = events
+	case primitive.A:
+		for _, e := range events {
+			if s, ok := e.(string); ok {
+				res.Events = append(res.Events, s)
+			}
+		}
+	}
+	return res
+}
+
+func toWebhookDelivery(delivery bson.M) *pb.WebhookDelivery {
+	res := &pb.WebhookDelivery{}
+	res.Id, _ = delivery["_id"].(string)
+	res.WebhookId, _ = delivery["webhookId"].(string)
+	res.Event, _ = delivery["event"].(string)
+	res.DocsId, _ = delivery["docsId"].(string)
+	res.Title, _ = delivery["title"].(string)
+	res.Status, _ = delivery["status"].(string)
+	res.NextAttemptAt = formatTime(delivery["nextAttemptAt"])
+	res.CreatedAt = formatTime(delivery["createdAt"])
+	if attempts, ok := delivery["attempts"].(primitive.A); ok {
+		for _, a := range attempts {
+			attempt, ok := a.(bson.M)
+			if !ok {
+				continue
+			}
+			at := &pb.WebhookAttempt{At: formatTime(attempt["at"])}
+			if code, ok := attempt["statusCode"].(int32); ok {
+				at.StatusCode = code
+			}
+			at.Error, _ = attempt["error"].(string)
+			res.Attempts = append(res.Attempts, at)
+		}
+	}
+	return res
+}
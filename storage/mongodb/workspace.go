@@ -0,0 +1,357 @@
+package mongodb
+
+import (
+	"context"
+	pb "mainService/genproto/doccs"
+	"mainService/pkg/apperr"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Workspace roles. They are separate from document roles: workspaceAccessOf
+// turns them into the access members get on the workspace's documents.
+const (
+	WorkspaceOwner  = "owner"
+	WorkspaceAdmin  = "admin"
+	WorkspaceMember = "member"
+	WorkspaceGuest  = "guest"
+)
+
+var workspaceRank = map[string]int{
+	WorkspaceGuest:  1,
+	WorkspaceMember: 2,
+	WorkspaceAdmin:  3,
+	WorkspaceOwner:  4,
+}
+
+type WorkspaceRepository interface {
+	CreateWorkspace(ctx context.Context, req *pb.CreateWorkspaceReq) (*pb.CreateWorkspaceRes, error)
+	AddWorkspaceMember(ctx context.Context, req *pb.AddWorkspaceMemberReq) (*pb.AddWorkspaceMemberRes, error)
+	RemoveWorkspaceMember(ctx context.Context, req *pb.RemoveWorkspaceMemberReq) (*pb.RemoveWorkspaceMemberRes, error)
+	ListWorkspaces(ctx context.Context, req *pb.ListWorkspacesReq) (*pb.ListWorkspacesRes, error)
+	ListWorkspaceDocuments(ctx context.Context, req *pb.ListWorkspaceDocumentsReq) (*pb.ListWorkspaceDocumentsRes, error)
+}
+
+type workspaceRepositoryImpl struct {
+	coll *mongo.Database
+}
+
+func NewWorkspaceRepository(db *mongo.Database) WorkspaceRepository {
+	return &workspaceRepositoryImpl{coll: db}
+}
+
+func (r *workspaceRepositoryImpl) CreateWorkspace(ctx context.Context, req *pb.CreateWorkspaceReq) (*pb.CreateWorkspaceRes, error) {
+	if req.UserId == "" {
+		return nil, apperr.Missing("user_id")
+	}
+	if req.Name == "" {
+		return nil, apperr.Missing("name")
+	}
+
+	defaultRole := RoleEditor
+	if req.DefaultRole != "" {
+		defaultRole = NormalizeRole(req.DefaultRole)
+		if defaultRole == "" || defaultRole == RoleOwner {
+			return nil, apperr.Invalid("default_role", "default role '%s' is not supported", req.DefaultRole)
+		}
+	}
+
+	now := time.Now()
+	ws := bson.M{
+		"_id":         uuid.NewString(),
+		"name":        req.Name,
+		"ownerId":     req.UserId,
+		"defaultRole": defaultRole,
+		"members": bson.A{
+			bson.M{"userId": req.UserId, "role": WorkspaceOwner, "addedBy": req.UserId, "addedAt": now},
+		},
+		"createdAt": now,
+	}
+	if _, err := r.coll.Collection("workspaces").InsertOne(ctx, ws); err != nil {
+		return nil, err
+	}
+
+	return &pb.CreateWorkspaceRes{Workspace: toWorkspace(ws)}, nil
+}
+
+// AddWorkspaceMember adds a member or changes the role of one. Admins manage
+// members and guests; only the owner appoints admins. The owner's own role
+// cannot be changed.
+func (r *workspaceRepositoryImpl) AddWorkspaceMember(ctx context.Context, req *pb.AddWorkspaceMemberReq) (*pb.AddWorkspaceMemberRes, error) {
+	if req.MemberId == "" {
+		return nil, apperr.Missing("member_id")
+	}
+	role := req.Role
+	if role == "" {
+		role = WorkspaceMember
+	}
+	if _, ok := workspaceRank[role]; !ok || role == WorkspaceOwner {
+		return nil, apperr.Invalid("role", "workspace role '%s' is not supported", req.Role)
+	}
+
+	ws, err := findWorkspace(ctx, r.coll, req.WorkspaceId)
+	if err != nil {
+		return nil, err
+	}
+	actorRole := workspaceRoleOf(ws, req.UserId)
+	if workspaceRank[actorRole] < workspaceRank[WorkspaceAdmin] {
+		return nil, apperr.NotAllowed("workspace", req.WorkspaceId, "userId '%s' is not allowed to manage workspace '%s'", req.UserId, req.WorkspaceId)
+	}
+	if role == WorkspaceAdmin && actorRole != WorkspaceOwner {
+		return nil, apperr.NotAllowed("workspace", req.WorkspaceId, "only the owner can appoint admins of workspace '%s'", req.WorkspaceId)
+	}
+	current := workspaceRoleOf(ws, req.MemberId)
+	if current == WorkspaceOwner {
+		return nil, apperr.Conflict("workspace", req.WorkspaceId, "the owner of workspace '%s' cannot be changed", req.WorkspaceId)
+	}
+	if current == WorkspaceAdmin && actorRole != WorkspaceOwner {
+		return nil, apperr.NotAllowed("workspace", req.WorkspaceId, "only the owner can change admins of workspace '%s'", req.WorkspaceId)
+	}
+
+	member := bson.M{"userId": req.MemberId, "role": role, "addedBy": req.UserId, "addedAt": time.Now()}
+	if err := r.putMember(ctx, ws, member); err != nil {
+		return nil, err
+	}
+
+	ws, err = findWorkspace(ctx, r.coll, req.WorkspaceId)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.AddWorkspaceMemberRes{Workspace: toWorkspace(ws)}, nil
+}
+
+// RemoveWorkspaceMember removes a member. Everybody but the owner may leave;
+// removing someone else takes the rights AddWorkspaceMember needs for them.
+func (r *workspaceRepositoryImpl) RemoveWorkspaceMember(ctx context.Context, req *pb.RemoveWorkspaceMemberReq) (*pb.RemoveWorkspaceMemberRes, error) {
+	ws, err := findWorkspace(ctx, r.coll, req.WorkspaceId)
+	if err != nil {
+		return nil, err
+	}
+
+	actorRole := workspaceRoleOf(ws, req.UserId)
+	current := workspaceRoleOf(ws, req.MemberId)
+	switch {
+	case current == "":
+		return nil, apperr.NotFound("member", req.MemberId)
+	case current == WorkspaceOwner:
+		return nil, apperr.Conflict("workspace", req.WorkspaceId, "the owner cannot leave workspace '%s'", req.WorkspaceId)
+	case req.MemberId == req.UserId:
+	case workspaceRank[actorRole] < workspaceRank[WorkspaceAdmin],
+		current == WorkspaceAdmin && actorRole != WorkspaceOwner:
+		return nil, apperr.NotAllowed("workspace", req.WorkspaceId, "userId '%s' is not allowed to remove '%s' from workspace '%s'", req.UserId, req.MemberId, req.WorkspaceId)
+	}
+
+	if err := r.dropMember(ctx, ws, req.MemberId); err != nil {
+		return nil, err
+	}
+
+	return &pb.RemoveWorkspaceMemberRes{Message: "Member removed successfully"}, nil
+}
+
+func (r *workspaceRepositoryImpl) ListWorkspaces(ctx context.Context, req *pb.ListWorkspacesReq) (*pb.ListWorkspacesRes, error) {
+	if req.UserId == "" {
+		return nil, apperr.Missing("user_id")
+	}
+
+	cursor, err := r.coll.Collection("workspaces").Find(ctx, bson.M{"members.userId": req.UserId},
+		options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var workspaces []*pb.Workspace
+	for cursor.Next(ctx) {
+		var ws bson.M
+		if err := cursor.Decode(&ws); err != nil {
+			return nil, err
+		}
+		workspaces = append(workspaces, toWorkspace(ws))
+	}
+
+	return &pb.ListWorkspacesRes{Workspaces: workspaces}, cursor.Err()
+}
+
+// ListWorkspaceDocuments returns the live documents of a workspace, most
+// recently updated first.
+func (r *workspaceRepositoryImpl) ListWorkspaceDocuments(ctx context.Context, req *pb.ListWorkspaceDocumentsReq) (*pb.ListWorkspaceDocumentsRes, error) {
+	ws, err := findWorkspace(ctx, r.coll, req.WorkspaceId)
+	if err != nil {
+		return nil, err
+	}
+	if workspaceRoleOf(ws, req.UserId) == "" {
+		return nil, apperr.NoAccess(req.UserId, "workspace", req.WorkspaceId)
+	}
+
+	limit, skip := pageOptions(req.Limit, req.Page)
+	cursor, err := r.coll.Collection("docs").Find(ctx, bson.M{"workspaceId": ws["_id"], "deletedAt": 0},
+		options.Find().SetSort(bson.D{{Key: "updatedAt", Value: -1}}).SetLimit(limit).SetSkip(skip))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []*pb.GetDocumentRes
+	for cursor.Next(ctx) {
+		var doc bson.M
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		docs = append(docs, toDocumentRes(doc))
+	}
+
+	return &pb.ListWorkspaceDocumentsRes{Documents: docs}, cursor.Err()
+}
+
+// putMember adds member to the workspace or replaces the entry of that user,
+// and copies their access onto every row of the workspace's documents, so
+// roleOf needs no extra lookup. Only the entry of that user is written, so
+// concurrent changes for other members are kept. The owner's entry is never
+// replaced.
+func (r *workspaceRepositoryImpl) putMember(ctx context.Context, ws bson.M, member bson.M) error {
+	userId, _ := member["userId"].(string)
+	role, _ := member["role"].(string)
+	path, err := userField("workspaceAccess", userId)
+	if err != nil {
+		return err
+	}
+
+	return withTransaction(ctx, r.coll, func(ctx context.Context) error {
+		coll := r.coll.Collection("workspaces")
+		result, err := coll.UpdateOne(ctx, bson.M{
+			"_id":     ws["_id"],
+			"members": bson.M{"$elemMatch": bson.M{"userId": userId, "role": bson.M{"$ne": WorkspaceOwner}}},
+		}, bson.M{"$set": bson.M{"members.$": member}})
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			result, err = coll.UpdateOne(ctx, bson.M{"_id": ws["_id"], "members.userId": bson.M{"$ne": userId}},
+				bson.M{"$push": bson.M{"members": member}})
+			if err != nil {
+				return err
+			}
+			if result.MatchedCount == 0 {
+				return apperr.Conflict("workspace", ws["_id"].(string), "the member '%s' of workspace '%s' cannot be changed", userId, ws["_id"])
+			}
+		}
+
+		_, err = r.coll.Collection("docs").UpdateMany(ctx, bson.M{"workspaceId": ws["_id"]}, bson.M{
+			"$set": bson.M{path: memberAccess(ws, role)},
+		})
+		return err
+	})
+}
+
+// dropMember removes the entry of userId from the workspace and their access
+// from its documents. The owner's entry is never removed.
+func (r *workspaceRepositoryImpl) dropMember(ctx context.Context, ws bson.M, userId string) error {
+	path, err := userField("workspaceAccess", userId)
+	if err != nil {
+		return err
+	}
+
+	return withTransaction(ctx, r.coll, func(ctx context.Context) error {
+		_, err := r.coll.Collection("workspaces").UpdateOne(ctx, bson.M{"_id": ws["_id"]}, bson.M{
+			"$pull": bson.M{"members": bson.M{"userId": userId, "role": bson.M{"$ne": WorkspaceOwner}}},
+		})
+		if err != nil {
+			return err
+		}
+		_, err = r.coll.Collection("docs").UpdateMany(ctx, bson.M{"workspaceId": ws["_id"]}, bson.M{
+			"$unset": bson.M{path: ""},
+		})
+		return err
+	})
+}
+
+func findWorkspace(ctx context.Context, db *mongo.Database, workspaceId string) (bson.M, error) {
+	var ws bson.M
+	err := db.Collection("workspaces").FindOne(ctx, bson.M{"_id": workspaceId}).Decode(&ws)
+	if err == mongo.ErrNoDocuments {
+		return nil, apperr.NotFound("workspace", workspaceId)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ws, nil
+}
+
+func workspaceMembers(ws bson.M) []bson.M {
+	var res []bson.M
+	members, _ := ws["members"].(bson.A)
+	for _, m := range members {
+		if m, ok := m.(bson.M); ok {
+			res = append(res, m)
+		}
+	}
+	return res
+}
+
+// workspaceRoleOf returns the workspace role of userId, or "" for outsiders.
+func workspaceRoleOf(ws bson.M, userId string) string {
+	if userId == "" {
+		return ""
+	}
+	for _, m := range workspaceMembers(ws) {
+		if id, _ := m["userId"].(string); id == userId {
+			role, _ := m["role"].(string)
+			return role
+		}
+	}
+	return ""
+}
+
+// workspaceAccessOf maps every member to the document role they get on the
+// workspace's documents: admins edit, members get the workspace default and
+// guests may read.
+func workspaceAccessOf(ws bson.M) bson.M {
+	res := bson.M{}
+	for _, m := range workspaceMembers(ws) {
+		userId, _ := m["userId"].(string)
+		role, _ := m["role"].(string)
+		if access := memberAccess(ws, role); access != "" {
+			res[userId] = access
+		}
+	}
+	return res
+}
+
+// memberAccess is the document role of a member with the workspace role
+// role, or "" for unknown roles.
+func memberAccess(ws bson.M, role string) string {
+	switch role {
+	case WorkspaceOwner, WorkspaceAdmin:
+		return RoleEditor
+	case WorkspaceMember:
+		if defaultRole, _ := ws["defaultRole"].(string); defaultRole != "" {
+			return defaultRole
+		}
+		return RoleEditor
+	case WorkspaceGuest:
+		return RoleViewer
+	}
+	return ""
+}
+
+func toWorkspace(ws bson.M) *pb.Workspace {
+	res := &pb.Workspace{}
+	res.Id, _ = ws["_id"].(string)
+	res.Name, _ = ws["name"].(string)
+	res.OwnerId, _ = ws["ownerId"].(string)
+	res.DefaultRole, _ = ws["defaultRole"].(string)
+	res.CreatedAt = formatTime(ws["createdAt"])
+	for _, m := range workspaceMembers(ws) {
+		member := &pb.WorkspaceMember{}
+		member.UserId, _ = m["userId"].(string)
+		member.Role, _ = m["role"].(string)
+		member.AddedBy, _ = m["addedBy"].(string)
+		member.AddedAt = formatTime(m["addedAt"])
+		res.Members = append(res.Members, member)
+	}
+	return res
+}